@@ -17,13 +17,14 @@
 package cmd
 
 import (
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
 	"github.com/spf13/cobra"
 )
 
 // accountsCmd represents the deploy command
 var accountsCmd = &cobra.Command{
 	Use:   "accounts",
-	Short: "Work with accounts in a FireFly stack",
+	Short: climsgs.T(climsgs.MsgHelpAccounts),
 	Long:  `Work with accounts in a FireFly stack`,
 }
 
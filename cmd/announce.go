@@ -0,0 +1,69 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/mdns"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var announceAddress string
+
+var announceCmd = &cobra.Command{
+	Use:   "announce <stack_name>",
+	Short: "Advertise a stack on the local network",
+	Long: `Advertise the API, UI and sandbox endpoints of each member of a stack on
+the local network using mDNS, so colleagues can find it with "ff discover".
+
+The stack must have been created with --bind-address so it can be reached
+from other machines. The stack is advertised until the command is stopped.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		svc, err := stackManager.GetAnnouncement(announceAddress)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("announcing stack '%s' as '%s' on %s - press Ctrl+C to stop\n", stackName, svc.Instance, svc.IP)
+		for _, txt := range svc.TXT {
+			fmt.Printf("  %s\n", txt)
+		}
+		ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+		defer cancel()
+		return mdns.Announce(ctx, svc)
+	},
+}
+
+func init() {
+	announceCmd.Flags().StringVar(&announceAddress, "address", "", "The address to advertise. Defaults to the address of the network interface used for the default route")
+
+	rootCmd.AddCommand(announceCmd)
+}
@@ -0,0 +1,82 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var authTokenMember int
+var authTokenRotate bool
+
+var authTokenCmd = &cobra.Command{
+	Use:   "token <stack_name>",
+	Short: "Show or rotate the API token of a member",
+	Long: `Show the token that the FireFly API of a member requires. The token is the
+password of basic auth, with the username "firefly".
+
+With --rotate, a new token is generated and FireFly core is restarted so the old
+one stops working. On a stack created with --api-auth, all members share one
+token, so it is replaced for every member.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if authTokenRotate {
+			return docker.CheckDockerConfig()
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		if !authTokenRotate {
+			token, err := stackManager.GetAPIToken(authTokenMember)
+			if err != nil {
+				return err
+			}
+			fmt.Println(token)
+			return nil
+		}
+		if err := checkProtected(stackManager.Stack); err != nil {
+			return err
+		}
+		token, err := stackManager.RotateAPIToken(authTokenMember)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("the new token of member %d is %s (username '%s')\n", authTokenMember, token, constants.APIAuthUsername)
+		return nil
+	},
+}
+
+func init() {
+	authTokenCmd.Flags().IntVarP(&authTokenMember, "member", "m", 0, "Index of the member")
+	authTokenCmd.Flags().BoolVar(&authTokenRotate, "rotate", false, "Replace the token with a new one")
+	addProtectedOverrideFlag(authTokenCmd)
+	authCmd.AddCommand(authTokenCmd)
+}
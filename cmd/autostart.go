@@ -0,0 +1,34 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var autostartCmd = &cobra.Command{
+	Use:   "autostart",
+	Short: "Start a stack automatically when the machine restarts",
+	Long: `Start a stack automatically when the machine restarts
+
+On Linux a systemd user unit is installed, and on macOS a launchd agent. Elsewhere,
+the containers of the stack are given a docker restart policy.`,
+}
+
+func init() {
+	rootCmd.AddCommand(autostartCmd)
+}
@@ -0,0 +1,69 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/autostart"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var autostartDisableCmd = &cobra.Command{
+	Use:   "disable <stack_name>",
+	Short: "Stop starting a stack automatically after a reboot",
+	Long:  `Stop starting a stack automatically after a reboot. A running stack is left running.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		disabled := false
+		if stackManager.Stack.RestartPolicy != "" {
+			if err := stackManager.SetRestartPolicy(""); err != nil {
+				return err
+			}
+			disabled = true
+		}
+		if autostart.IsSupported() {
+			if enabled, _, err := autostart.IsEnabled(stackName); err != nil {
+				return err
+			} else if enabled {
+				if err := autostart.Disable(stackName); err != nil {
+					return err
+				}
+				disabled = true
+			}
+		}
+		if !disabled {
+			return fmt.Errorf("autostart is not enabled for stack '%s'", stackName)
+		}
+		fmt.Printf("stack '%s' will no longer start automatically\n", stackName)
+		return nil
+	},
+}
+
+func init() {
+	autostartCmd.AddCommand(autostartDisableCmd)
+}
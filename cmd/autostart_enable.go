@@ -0,0 +1,93 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/hyperledger/firefly-cli/internal/autostart"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var autostartRestartPolicy bool
+
+var autostartEnableCmd = &cobra.Command{
+	Use:   "enable <stack_name>",
+	Short: "Start a stack automatically after a reboot",
+	Long: `Start a stack automatically after a reboot
+
+The stack is started with "ff start" once the docker daemon is available. On Linux
+this is done by a systemd user unit, and on macOS by a launchd agent.
+
+With --restart-policy, or on other platforms, the containers of the stack are given
+the "unless-stopped" restart policy instead, so docker restarts them when the daemon
+starts. Docker Desktop has to be set to start when you log in for this to work.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		if autostartRestartPolicy || !autostart.IsSupported() {
+			if err := stackManager.SetRestartPolicy(stacks.RestartPolicyUnlessStopped); err != nil {
+				return err
+			}
+			fmt.Printf("the containers of stack '%s' will be restarted by docker when the docker daemon starts\n", stackName)
+			return nil
+		}
+		executable, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		if executable, err = filepath.EvalSymlinks(executable); err != nil {
+			return err
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		filename, err := autostart.Enable(&autostart.Service{
+			Stack:      stackName,
+			Executable: executable,
+			Path:       os.Getenv("PATH"),
+			Home:       home,
+			LogFile:    filepath.Join(stackManager.Stack.StackDir, "autostart.log"),
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("stack '%s' will start automatically. service installed at: %s\n", stackName, filename)
+		if runtime.GOOS == "linux" {
+			fmt.Printf("\nsystemd user units only run once you log in. To start the stack at boot without logging in, run:\n\nloginctl enable-linger %s\n\n", os.Getenv("USER"))
+		}
+		return nil
+	},
+}
+
+func init() {
+	autostartEnableCmd.Flags().BoolVar(&autostartRestartPolicy, "restart-policy", false, "Use a docker restart policy on the containers instead of a systemd or launchd service")
+	autostartCmd.AddCommand(autostartEnableCmd)
+}
@@ -0,0 +1,61 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/autostart"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var autostartStatusCmd = &cobra.Command{
+	Use:   "status <stack_name>",
+	Short: "Show whether a stack starts automatically after a reboot",
+	Long:  `Show whether a stack starts automatically after a reboot`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		if policy := stackManager.Stack.RestartPolicy; policy != "" {
+			fmt.Printf("autostart is enabled for stack '%s' with the docker restart policy '%s'\n", stackName, policy)
+			return nil
+		}
+		if autostart.IsSupported() {
+			if enabled, filename, err := autostart.IsEnabled(stackName); err != nil {
+				return err
+			} else if enabled {
+				fmt.Printf("autostart is enabled for stack '%s': %s\n", stackName, filename)
+				return nil
+			}
+		}
+		fmt.Printf("autostart is not enabled for stack '%s'\n", stackName)
+		return nil
+	},
+}
+
+func init() {
+	autostartCmd.AddCommand(autostartStatusCmd)
+}
@@ -0,0 +1,79 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var buildService string
+var buildContext string
+var buildDockerfile string
+var buildRevert bool
+
+var buildCmd = &cobra.Command{
+	Use:   "build <stack_name>",
+	Short: "Build a local image for a service in the stack",
+	Long: `Build a docker image from a local source tree, such as a fork of FireFly core or
+one of its connectors, and switch a service in the stack over to use it.
+
+If the stack is running, only the containers for that service are recreated.
+A service name like "firefly_core" applies to every member, while
+"firefly_core_0" applies only to the first member. Use --revert to switch the
+service back to the image from the version manifest.`,
+	Example: `  ff build dev --service firefly_core --context ~/src/firefly
+  ff build dev --service evmconnect_0 --context ~/src/firefly-evmconnect
+  ff build dev --service firefly_core --revert`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return docker.CheckDockerConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		if buildService == "" {
+			return fmt.Errorf("a service must be set with --service")
+		}
+		if buildRevert {
+			return stackManager.RevertLocalImage(buildService)
+		}
+		if buildContext == "" {
+			return fmt.Errorf("a build context must be set with --context")
+		}
+		return stackManager.BuildLocalImage(buildService, buildContext, buildDockerfile)
+	},
+}
+
+func init() {
+	buildCmd.Flags().StringVarP(&buildService, "service", "s", "", "Name of the service to build the image for")
+	_ = buildCmd.RegisterFlagCompletionFunc("service", completeServiceFlag)
+	buildCmd.Flags().StringVarP(&buildContext, "context", "c", "", "Path to the docker build context")
+	buildCmd.Flags().StringVarP(&buildDockerfile, "file", "f", "", "Path to the Dockerfile, if it is not at the root of the build context")
+	buildCmd.Flags().BoolVar(&buildRevert, "revert", false, "Switch the service back to the image from the version manifest")
+	rootCmd.AddCommand(buildCmd)
+}
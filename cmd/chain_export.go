@@ -0,0 +1,70 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/errcodes"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var chainExportOutput string
+
+var chainExportCmd = &cobra.Command{
+	Use:   "export <stack_name>",
+	Short: "Export the blocks and accounts of a stack's chain",
+	Long: `Export the blocks, genesis and member accounts of a stack's chain to a
+directory, so another stack can be created from it with "ff init --chain-data".
+
+The blockchain node is stopped while its blocks are exported, and started again afterwards.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return docker.CheckDockerConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		if hasRun, err := stackManager.Stack.HasRunBefore(); err != nil {
+			return err
+		} else if !hasRun {
+			return errcodes.New(errcodes.StackNotStarted, "stack '%s' has not been started, so there is no chain to export", stackName)
+		}
+		fmt.Printf("exporting chain for stack '%s'...\n", stackName)
+		outputDir, err := stackManager.ExportChain(chainExportOutput)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Chain exported to: %s\n", outputDir)
+		return nil
+	},
+}
+
+func init() {
+	chainExportCmd.Flags().StringVarP(&chainExportOutput, "output", "o", "chaindata", "Directory to write the exported chain to")
+
+	chainCmd.AddCommand(chainExportCmd)
+}
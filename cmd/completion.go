@@ -0,0 +1,107 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+// memberFlags are the flags that take the index of a member of the stack
+var memberFlags = []string{"member", "org"}
+
+// completeStackName completes the first argument of a command with the names of the existing stacks
+func completeStackName(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names, err := stacks.ListStacks()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeStackServices completes a stack name, then the names of the services in its compose file
+func completeStackServices(groups bool) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completeStackName(cmd, args, toComplete)
+		}
+		names, err := stacks.ServiceNames(args[0], groups)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		used := make(map[string]bool)
+		for _, arg := range args[1:] {
+			used[arg] = true
+		}
+		completions := []string{}
+		for _, name := range names {
+			if !used[name] {
+				completions = append(completions, name)
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeServiceFlag completes a flag with the names of the services of the stack in the first argument
+func completeServiceFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names, err := stacks.ServiceNames(args[0], true)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeMemberFlag completes a flag with the member indices of the stack in the first argument
+func completeMemberFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	indices, err := stacks.MemberIndices(args[0])
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return indices, cobra.ShellCompDirectiveNoFileComp
+}
+
+// registerCompletions adds completion of stack names to every command whose first argument is a
+// stack, and of member indices to their member flags. Commands that complete more than the stack
+// name set their own ValidArgsFunction.
+func registerCompletions(cmd *cobra.Command) {
+	takesStack := strings.Contains(cmd.Use, "<stack_name>")
+	if takesStack && cmd.ValidArgsFunction == nil {
+		cmd.ValidArgsFunction = completeStackName
+	}
+	if takesStack {
+		for _, name := range memberFlags {
+			if flag := cmd.Flags().Lookup(name); flag != nil && flag.Value.Type() == "int" {
+				_ = cmd.RegisterFlagCompletionFunc(name, completeMemberFlag)
+			}
+		}
+	}
+	for _, child := range cmd.Commands() {
+		registerCompletions(child)
+	}
+}
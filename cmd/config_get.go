@@ -0,0 +1,63 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <stack_name> [key]",
+	Short: "Show the options of a stack",
+	Long: `Show the value of an option of a stack, or of every option that can be
+changed with "ff config set" if no key is given.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(args[0]); err != nil {
+			return err
+		}
+		if len(args) == 2 {
+			value, err := stackManager.GetSetting(args[1])
+			if err != nil {
+				return err
+			}
+			fmt.Println(value)
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tVALUE\tDESCRIPTION")
+		for _, key := range stacks.SettingKeys() {
+			value, _ := stackManager.GetSetting(key)
+			fmt.Fprintf(w, "%s\t%s\t%s\n", key, value, stacks.SettingDescription(key))
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+}
@@ -0,0 +1,93 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var configSetApply bool
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <stack_name> <key> <value>",
+	Short: "Change an option of a stack",
+	Long: `Change an option of a stack without creating it again. The stack's config and
+docker compose files are regenerated, and the services that have to be updated
+for the change to take effect are listed. Use --apply to update them now, or
+they pick up the change the next time the stack is started. A service is only
+recreated when its docker compose definition changed, and restarted otherwise.
+
+The key and value can also be given as key=value. Run "ff config get <stack_name>"
+to see the options that can be changed.`,
+	Example: `  ff config set dev prometheus.enabled true
+  ff config set dev sandbox.enabled=false --apply`,
+	Args: cobra.RangeArgs(2, 3),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if configSetApply {
+			return docker.CheckDockerConfig()
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName, key := args[0], args[1]
+		var value string
+		if len(args) == 3 {
+			value = args[2]
+		} else if i := strings.Index(key, "="); i > 0 {
+			key, value = key[:i], key[i+1:]
+		} else {
+			return fmt.Errorf("no value given for '%s'", key)
+		}
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		change, err := stackManager.SetSetting(key, value)
+		if err != nil {
+			return err
+		}
+		services := change.Services()
+		if len(services) == 0 {
+			fmt.Printf("%s is set to %s. no services have to be updated\n", key, value)
+			return nil
+		}
+		if configSetApply {
+			if err := stackManager.ApplySettings(change); err != nil {
+				return err
+			}
+			fmt.Printf("%s is set to %s. updated services: %s\n", key, value, strings.Join(services, ", "))
+			return nil
+		}
+		fmt.Printf("%s is set to %s. these services have to be updated: %s\n", key, value, strings.Join(services, ", "))
+		fmt.Printf("run 'ff config set %s %s %s --apply' to update them now, or restart the stack with 'ff stop %s' and 'ff start %s'\n", stackName, key, value, stackName, stackName)
+		return nil
+	},
+}
+
+func init() {
+	configSetCmd.Flags().BoolVar(&configSetApply, "apply", false, "Recreate or restart the affected services of a running stack now")
+	configCmd.AddCommand(configSetCmd)
+}
@@ -0,0 +1,80 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var tenantCount int
+
+var demoMultitenantCmd = &cobra.Command{
+	Use:   "multitenant <stack_name>",
+	Short: "Create namespaces for several tenants on a running stack",
+	Long: `Create namespaces for several tenants on a running stack.
+
+Each tenant gets a gateway mode namespace on every member, with its own signing key
+as the default key of the namespace, and a username and token that can only access
+that namespace. The FireFly core containers are restarted to load the new namespaces.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return docker.CheckDockerConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		if tenantCount < 1 {
+			return fmt.Errorf("--tenants must be at least 1")
+		}
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		if err := checkProtected(stackManager.Stack); err != nil {
+			return err
+		}
+		tenants, err := stackManager.CreateTenants(tenantCount)
+		if err != nil {
+			return err
+		}
+		for _, tenant := range tenants {
+			fmt.Printf("\n%s\n", tenant.Name)
+			fmt.Printf("  username: %s\n", tenant.Username)
+			fmt.Printf("  token:    %s\n", tenant.Token)
+			for _, member := range stackManager.Stack.Members {
+				fmt.Printf("  member %s\n", member.ID)
+				fmt.Printf("    API: http://127.0.0.1:%d/api/v1/namespaces/%s\n", member.ExposedFireflyPort, tenant.Name)
+				fmt.Printf("    key: %s\n", tenant.Keys[member.ID])
+			}
+		}
+		fmt.Print("\n")
+		return nil
+	},
+}
+
+func init() {
+	demoMultitenantCmd.Flags().IntVar(&tenantCount, "tenants", 3, "Number of tenants to create")
+	addProtectedOverrideFlag(demoMultitenantCmd)
+	demoCmd.AddCommand(demoMultitenantCmd)
+}
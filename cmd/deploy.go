@@ -17,16 +17,67 @@
 package cmd
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
 	"github.com/spf13/cobra"
 )
 
+var deployApprovals int
+var deployApprovalMode string
+var deployApprovalPort int
+var deployApprovalTimeout time.Duration
+
 // deployCmd represents the deploy command
 var deployCmd = &cobra.Command{
 	Use:   "deploy",
-	Short: "Deploy a compiled smart contract",
+	Short: climsgs.T(climsgs.MsgHelpDeploy),
 	Long:  `Deploy a compiled smart contract to the blockchain used by a FireFly stack`,
 }
 
+// requireApprovals holds a deployment until enough members approve it, when --approvals is set. Approvals
+// are asked for in turn on the terminal, or are sent to an API by each member with "ff deploy approve".
+func requireApprovals(stackManager *stacks.StackManager, filename, contractName string) error {
+	if deployApprovals == 0 {
+		return nil
+	}
+	p, err := stackManager.NewDeploymentProposal(filename, contractName, 0, deployApprovals)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("deployment proposal %s for '%s' (sha256 %s) needs %d approvals\n", p.ID, p.Contract, p.FileSHA256, p.Required)
+	switch deployApprovalMode {
+	case "prompt":
+		for i, member := range stackManager.Stack.Members {
+			approve := confirm(fmt.Sprintf("member %s: approve the deployment of '%s'?", member.ID, p.Contract)) == nil
+			if err := stackManager.Vote(p, i, approve); err != nil {
+				return err
+			}
+			if p.Status == stacks.ProposalRejected {
+				return fmt.Errorf("deployment proposal '%s' was rejected by member %s", p.ID, member.ID)
+			}
+			if p.Status == stacks.ProposalApproved {
+				return nil
+			}
+		}
+		return fmt.Errorf("deployment proposal '%s' did not get enough approvals", p.ID)
+	case "api":
+		fmt.Printf("waiting for approvals. each member can approve with:\n\n%s deploy approve %s %s --member <index>\n\n", rootCmd.Use, stackManager.Stack.Name, p.ID)
+		return stackManager.WaitForApprovals(p, deployApprovalPort, deployApprovalTimeout)
+	default:
+		return fmt.Errorf("invalid approval mode '%s'. options are: [prompt api]", deployApprovalMode)
+	}
+}
+
+func addApprovalFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&deployApprovals, "approvals", 0, "Hold the deployment until this many members approve it")
+	cmd.Flags().StringVar(&deployApprovalMode, "approval-mode", "prompt", "How members approve a deployment. Options are: [prompt api]")
+	cmd.Flags().IntVar(&deployApprovalPort, "approval-port", 5050, "Port for the approval API, in api approval mode")
+	cmd.Flags().DurationVar(&deployApprovalTimeout, "approval-timeout", 10*time.Minute, "How long to wait for approvals, in api approval mode")
+}
+
 func init() {
 	rootCmd.AddCommand(deployCmd)
 }
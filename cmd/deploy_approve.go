@@ -0,0 +1,67 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var approveMember int
+var approveReject bool
+
+var deployApproveCmd = &cobra.Command{
+	Use:   "approve <stack_name> <proposal_id>",
+	Short: "Approve or reject a pending contract deployment on behalf of a member",
+	Long: `Approve or reject a pending contract deployment on behalf of a member.
+
+Deployments started with --approvals and --approval-mode api wait for members to
+vote through an API. This command calls that API, and can be used from a separate
+terminal or script to rehearse a governance controlled deployment.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		p, err := stackManager.SendVote(args[1], approveMember, !approveReject)
+		if err != nil {
+			return err
+		}
+		approvals := 0
+		for _, v := range p.Votes {
+			if v.Approved {
+				approvals++
+			}
+		}
+		fmt.Printf("deployment proposal %s is %s with %d of %d approvals\n", p.ID, p.Status, approvals, p.Required)
+		return nil
+	},
+}
+
+func init() {
+	deployApproveCmd.Flags().IntVarP(&approveMember, "member", "m", 0, "Index of the member voting on the deployment")
+	deployApproveCmd.Flags().BoolVar(&approveReject, "reject", false, "Reject the deployment instead of approving it")
+	deployCmd.AddCommand(deployApproveCmd)
+}
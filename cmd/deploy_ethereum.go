@@ -28,7 +28,7 @@ import (
 
 // deployEthereumCmd represents the "deploy ethereum" command
 var deployEthereumCmd = &cobra.Command{
-	Use:   "ethereum <stack_name> <contract_json_file>",
+	Use:   "ethereum <stack_name> <contract_json_file> [constructor_params...]",
 	Short: "Deploy a compiled solidity contract",
 	Long: `Deploy a solidity contract compiled with solc to the blockchain used by a FireFly stack
 
@@ -36,7 +36,7 @@ To compile a .sol file to a .json file run:
 
 solc --combined-json abi,bin contract.sol > contract.json
 `,
-	Args: cobra.ExactArgs(2),
+	Args: cobra.MinimumNArgs(2),
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		return docker.CheckDockerConfig()
 	},
@@ -64,6 +64,9 @@ solc --combined-json abi,bin contract.sol > contract.json
 				return err
 			}
 		}
+		if err := requireApprovals(stackManager, filename, selectedContractName); err != nil {
+			return err
+		}
 		location, err := stackManager.DeployContract(filename, selectedContractName, 0, args[2:])
 		if err != nil {
 			return err
@@ -74,5 +77,6 @@ solc --combined-json abi,bin contract.sol > contract.json
 }
 
 func init() {
+	addApprovalFlags(deployEthereumCmd)
 	deployCmd.AddCommand(deployEthereumCmd)
 }
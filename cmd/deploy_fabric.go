@@ -44,6 +44,9 @@ var deployFabricCmd = &cobra.Command{
 		if err := stackManager.LoadStack(stackName); err != nil {
 			return err
 		}
+		if err := requireApprovals(stackManager, filename, args[3]); err != nil {
+			return err
+		}
 		contractAddress, err := stackManager.DeployContract(filename, filename, 0, args[2:])
 		if err != nil {
 			return err
@@ -54,5 +57,6 @@ var deployFabricCmd = &cobra.Command{
 }
 
 func init() {
+	addApprovalFlags(deployFabricCmd)
 	deployCmd.AddCommand(deployFabricCmd)
 }
@@ -0,0 +1,78 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/mdns"
+	"github.com/spf13/cobra"
+)
+
+var discoverTimeout time.Duration
+
+var discoverCmd = &cobra.Command{
+	Use:   "discover",
+	Short: "Find stacks advertised on the local network",
+	Long:  `Find stacks that colleagues are advertising on the local network with "ff announce"`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services, err := mdns.Discover(discoverTimeout)
+		if err != nil {
+			return err
+		}
+		if len(services) == 0 {
+			fmt.Println("no stacks found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "STACK\tHOST\tENDPOINT\tURL")
+		for _, svc := range services {
+			stack := svc.Instance
+			endpoints := [][2]string{}
+			for _, txt := range svc.TXT {
+				kv := strings.SplitN(txt, "=", 2)
+				if len(kv) != 2 {
+					continue
+				}
+				switch {
+				case kv[0] == "stack":
+					stack = kv[1]
+				case kv[0] == "auth":
+					endpoints = append(endpoints, [2]string{"auth", kv[1]})
+				case strings.HasPrefix(kv[1], "http"):
+					endpoints = append(endpoints, [2]string{kv[0], kv[1]})
+				}
+			}
+			for _, e := range endpoints {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", stack, svc.Host, e[0], e[1])
+			}
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	discoverCmd.Flags().DurationVarP(&discoverTimeout, "timeout", "t", 3*time.Second, "How long to wait for stacks to answer")
+
+	rootCmd.AddCommand(discoverCmd)
+}
@@ -0,0 +1,59 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var envMember int
+
+var envCmd = &cobra.Command{
+	Use:   "env <stack_name>",
+	Short: "Print connection settings for a stack member as environment variables",
+	Long: `Print connection settings for a stack member as environment variables
+
+The output is in dotenv format, so it can be sourced from a shell or loaded
+by an application. An app.env file for the first member is also written to
+the stack directory when the stack is created and started.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		env, err := stackManager.GetAppEnv(envMember)
+		if err != nil {
+			return err
+		}
+		fmt.Print(env)
+		return nil
+	},
+}
+
+func init() {
+	envCmd.Flags().IntVarP(&envMember, "member", "m", 0, "Index of the member to print settings for")
+	rootCmd.AddCommand(envCmd)
+}
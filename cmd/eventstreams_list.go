@@ -0,0 +1,66 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var eventStreamsListMember int
+
+// eventStreamsListCmd represents the "eventstreams list" command
+var eventStreamsListCmd = &cobra.Command{
+	Use:     "list <stack_name>",
+	Short:   "List the event streams and listeners in a member's blockchain connector",
+	Long:    `List the event streams and listeners in a member's blockchain connector`,
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"ls"},
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return docker.CheckDockerConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		streams, err := stackManager.ListEventStreams(eventStreamsListMember)
+		if err != nil {
+			return err
+		}
+		b, err := json.MarshalIndent(streams, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", string(b))
+		return nil
+	},
+}
+
+func init() {
+	eventStreamsListCmd.Flags().IntVarP(&eventStreamsListMember, "member", "m", 0, "Index of the member whose connector to query")
+	eventStreamsCmd.AddCommand(eventStreamsListCmd)
+}
@@ -0,0 +1,67 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var eventStreamsResetMember int
+var eventStreamsResetListener string
+var eventStreamsResetFromBlock string
+
+// eventStreamsResetCmd represents the "eventstreams reset" command
+var eventStreamsResetCmd = &cobra.Command{
+	Use:   "reset <stack_name>",
+	Short: "Reset the checkpoints of event stream listeners in a member's blockchain connector",
+	Long: `Reset the checkpoints of event stream listeners in a member's blockchain connector
+
+By default every listener is reset to block 0, so all events are redelivered.
+This is useful when listeners are stuck after the chain has been reset.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return docker.CheckDockerConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		count, err := stackManager.ResetEventStreams(eventStreamsResetMember, eventStreamsResetListener, eventStreamsResetFromBlock)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("reset %d listener(s) to block %s\n", count, eventStreamsResetFromBlock)
+		return nil
+	},
+}
+
+func init() {
+	eventStreamsResetCmd.Flags().IntVarP(&eventStreamsResetMember, "member", "m", 0, "Index of the member whose connector to reset")
+	eventStreamsResetCmd.Flags().StringVarP(&eventStreamsResetListener, "listener", "l", "", "ID of a single listener to reset, instead of all listeners")
+	eventStreamsResetCmd.Flags().StringVar(&eventStreamsResetFromBlock, "from-block", "0", "Block number to restart the listeners from")
+	eventStreamsCmd.AddCommand(eventStreamsResetCmd)
+}
@@ -0,0 +1,69 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
+	"github.com/hyperledger/firefly-cli/internal/errcodes"
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain [error_code]",
+	Short: climsgs.T(climsgs.MsgHelpExplain),
+	Long: `Show the causes and fixes of an error code.
+
+Errors from the CLI start with a code like [FF-CLI-0004]. Codes don't change between releases,
+so they can be used in scripts and searched for. Without an argument, all codes are listed.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			for _, e := range errcodes.All() {
+				fmt.Printf("%s  %s\n", e.Code, e.Summary)
+			}
+			return nil
+		}
+		e, ok := errcodes.Lookup(args[0])
+		if !ok {
+			return errors.New(climsgs.T(climsgs.MsgUnknownErrorCode, args[0], rootCmd.Use))
+		}
+		fmt.Printf("%s: %s\n\n%s\n", e.Code, e.Summary, climsgs.T(climsgs.MsgExplainCauses))
+		for _, c := range e.Causes {
+			fmt.Printf("  - %s\n", c)
+		}
+		fmt.Printf("\n%s\n", climsgs.T(climsgs.MsgExplainFixes))
+		for _, f := range e.Fixes {
+			fmt.Printf("  - %s\n", f)
+		}
+		return nil
+	},
+}
+
+// printErrorHint points the user at "ff explain" for errors that have a code
+func printErrorHint(err error) {
+	if code := errcodes.CodeOf(err); code != "" {
+		fmt.Fprintln(os.Stderr, climsgs.T(climsgs.MsgExplainHint, rootCmd.Use, code))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+}
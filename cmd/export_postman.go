@@ -0,0 +1,60 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var exportPostmanOutput string
+
+var exportPostmanCmd = &cobra.Command{
+	Use:   "postman <stack_name>",
+	Short: "Export a Postman collection for the members of a stack",
+	Long: `Export a Postman collection with requests for the status, messages, tokens and
+contracts APIs of each member of a stack. The base URL of each member, and the
+namespace, are collection variables. Insomnia can import the collection too.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		output := exportPostmanOutput
+		if output == "" {
+			output = fmt.Sprintf("%s.postman_collection.json", stackName)
+		}
+		if err := stackManager.WritePostmanCollection(output); err != nil {
+			return err
+		}
+		fmt.Printf("Postman collection written to: %s\n", output)
+		return nil
+	},
+}
+
+func init() {
+	exportPostmanCmd.Flags().StringVarP(&exportPostmanOutput, "output", "o", "", "File to write the collection to. Defaults to <stack_name>.postman_collection.json")
+	exportCmd.AddCommand(exportPostmanCmd)
+}
@@ -0,0 +1,70 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var enrollOrg int
+var enrollUser string
+var enrollType string
+
+var fabricEnrollCmd = &cobra.Command{
+	Use:   "enroll <stack_name>",
+	Short: "Issue a new identity from the Fabric CA of a member's org",
+	Long: `Issue a new identity from the Fabric CA of a member's org.
+
+The identity is registered and enrolled with the CA, its MSP is stored with the rest of the
+crypto material of the stack, and it is added to the credential store of the member's fabconnect,
+so it can be used to sign transactions.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return docker.CheckDockerConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		account, err := stackManager.EnrollFabricUser(enrollOrg, enrollUser, enrollType)
+		if err != nil {
+			return err
+		}
+		fmt.Print(account)
+		fmt.Print("\n")
+		return nil
+	},
+}
+
+func init() {
+	fabricEnrollCmd.Flags().IntVar(&enrollOrg, "org", 0, "Index of the member whose Fabric org issues the identity")
+	fabricEnrollCmd.Flags().StringVar(&enrollUser, "user", "", "Name of the identity to enroll")
+	fabricEnrollCmd.Flags().StringVar(&enrollType, "type", "client", "Type of the identity: client or admin")
+	fabricEnrollCmd.MarkFlagRequired("user")
+
+	fabricCmd.AddCommand(fabricEnrollCmd)
+}
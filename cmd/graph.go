@@ -0,0 +1,87 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var graphOutput string
+var graphFormat string
+
+// graphFormatsByExtension picks the format of a graph from the extension of the file it is written to
+var graphFormatsByExtension = map[string]string{
+	".dot": "dot",
+	".gv":  "dot",
+	".mmd": "mermaid",
+	".svg": "svg",
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph <stack_name>",
+	Short: "Draw a diagram of the members, services and port mappings of a stack",
+	Long: `Draw a diagram of the members, services and port mappings of a stack
+
+The diagram is generated from the docker compose config of the stack, with a
+group for each member and arrows from each service to the services it depends
+on. It can be written as Graphviz DOT, a Mermaid flowchart or an SVG image.
+The format is taken from the extension of the output file, or --format.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		format := graphFormat
+		if format == "" {
+			format = graphFormatsByExtension[strings.ToLower(filepath.Ext(graphOutput))]
+		}
+		if format == "" {
+			format = "dot"
+		}
+		graph, err := stackManager.Topology().Render(format)
+		if err != nil {
+			return err
+		}
+		if graphOutput == "" {
+			fmt.Print(graph)
+			return nil
+		}
+		if err := ioutil.WriteFile(graphOutput, []byte(graph), 0644); err != nil {
+			return err
+		}
+		fmt.Printf("Diagram written to: %s\n", graphOutput)
+		return nil
+	},
+}
+
+func init() {
+	graphCmd.Flags().StringVarP(&graphOutput, "output", "o", "", "File to write the diagram to. Defaults to stdout")
+	graphCmd.Flags().StringVarP(&graphFormat, "format", "f", "", fmt.Sprintf("Format of the diagram. Options are: %s. Defaults to the format for the extension of the output file, or dot", strings.Join(stacks.GraphFormats, ", ")))
+	rootCmd.AddCommand(graphCmd)
+}
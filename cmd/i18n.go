@@ -0,0 +1,35 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
+	"github.com/spf13/cobra"
+)
+
+var i18nCmd = &cobra.Command{
+	Use:   "i18n",
+	Short: climsgs.T(climsgs.MsgHelpI18n),
+	Long: `Work with the translations of the CLI.
+
+The language of the CLI is selected with the FF_LANG environment variable, for example FF_LANG=es.
+Messages without a translation are shown in English.`,
+}
+
+func init() {
+	rootCmd.AddCommand(i18nCmd)
+}
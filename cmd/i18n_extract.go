@@ -0,0 +1,55 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
+	"github.com/spf13/cobra"
+)
+
+var extractMissing bool
+
+var i18nExtractCmd = &cobra.Command{
+	Use:   "extract [language]",
+	Short: climsgs.T(climsgs.MsgHelpI18nExtract),
+	Long: `Print the messages of the CLI as JSON for translators.
+
+Each entry has the message key, the English text and the existing translation in the given
+language. Translations are added to internal/climsgs/<language>_messages.go.`,
+	Example: `ff i18n extract es --missing`,
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lang := "en"
+		if len(args) > 0 {
+			lang = args[0]
+		}
+		b, err := json.MarshalIndent(climsgs.Extract(lang, extractMissing), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	},
+}
+
+func init() {
+	i18nExtractCmd.Flags().BoolVar(&extractMissing, "missing", false, "Only print the messages that have no translation yet")
+	i18nCmd.AddCommand(i18nExtractCmd)
+}
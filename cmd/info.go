@@ -18,8 +18,9 @@ package cmd
 
 import (
 	"context"
-	"fmt"
+	"errors"
 
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
 	"github.com/hyperledger/firefly-cli/internal/docker"
 	"github.com/hyperledger/firefly-cli/internal/log"
 	"github.com/hyperledger/firefly-cli/internal/stacks"
@@ -28,7 +29,7 @@ import (
 
 var infoCmd = &cobra.Command{
 	Use:   "info <stack_name>",
-	Short: "Get info about a stack",
+	Short: climsgs.T(climsgs.MsgHelpInfo),
 	Long: `Get info about a stack such as each container name
 	and image version.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -39,7 +40,7 @@ var infoCmd = &cobra.Command{
 		}
 		stackManager := stacks.NewStackManager(ctx)
 		if len(args) == 0 {
-			return fmt.Errorf("no stack specified")
+			return errors.New(climsgs.T(climsgs.MsgNoStackSpecified))
 		}
 		stackName := args[0]
 
@@ -20,6 +20,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -27,6 +29,12 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum"
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/rpcproxy"
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
+	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/errcodes"
 	"github.com/hyperledger/firefly-cli/internal/log"
 	"github.com/hyperledger/firefly-cli/internal/stacks"
 	"github.com/hyperledger/firefly-cli/pkg/types"
@@ -42,15 +50,25 @@ var stackNameInvalidRegex = regexp.MustCompile(`[^-_a-z0-9]`)
 
 var initCmd = &cobra.Command{
 	Use:   "init [stack_name] [member_count]",
-	Short: "Create a new FireFly local dev stack",
+	Short: climsgs.T(climsgs.MsgHelpInit),
 	Long:  `Create a new FireFly local dev stack`,
 	Args:  cobra.MaximumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := log.WithVerbosity(context.Background(), verbose)
 		ctx = log.WithLogger(ctx, logger)
+		ctx = docker.WithDryRun(ctx, initOptions.DryRun)
 		var stackName string
 		stackManager := stacks.NewStackManager(ctx)
 
+		if initOptions.Minimal {
+			if err := applyMinimalPreset(cmd, args); err != nil {
+				return err
+			}
+			if len(args) < 2 {
+				args = append(args, "1")
+			}
+		}
+
 		if err := validateDatabaseProvider(initOptions.DatabaseProvider); err != nil {
 			return err
 		}
@@ -66,8 +84,64 @@ var initCmd = &cobra.Command{
 		if err := validateIPFSMode(initOptions.IPFSMode); err != nil {
 			return err
 		}
+		if err := validateMessageQueue(initOptions.MessageQueue); err != nil {
+			return err
+		}
+		if err := docker.ValidateLatencyProfile(initOptions.LatencyProfile); err != nil {
+			return err
+		}
+		if err := validateNamePrefix(initOptions.NamePrefix); err != nil {
+			return err
+		}
+		if _, err := docker.ParseLabels(initOptions.Labels); err != nil {
+			return err
+		}
+		if err := validateBindAddress(initOptions.BindAddress); err != nil {
+			return err
+		}
+		if err := docker.ValidateTimezone(initOptions.Timezone); err != nil {
+			return err
+		}
+		if _, err := docker.ParseClockSkew(initOptions.ClockSkew); err != nil {
+			return err
+		}
+		if err := docker.ValidateDNS(initOptions.DNS); err != nil {
+			return err
+		}
+		if err := docker.ValidateExtraHosts(initOptions.ExtraHosts); err != nil {
+			return err
+		}
+		if initOptions.PrivateTransactions && initOptions.BlockchainNodeProvider != types.BlockchainNodeProviderBesu.String() && initOptions.BlockchainNodeProvider != types.BlockchainNodeProviderQuorum.String() {
+			return fmt.Errorf("--private-tx is only supported with the besu and quorum blockchain nodes")
+		}
+		if initOptions.RPCProxyEnabled {
+			if initOptions.BlockchainNodeProvider != types.BlockchainNodeProviderRemoteRPC.String() {
+				return fmt.Errorf("--rpc-proxy is only supported with the remote-rpc blockchain node")
+			}
+			if err := rpcproxy.Validate(initOptions.RemoteNodeURL, &initOptions.RPCProxy); err != nil {
+				return err
+			}
+		}
+		if err := validateGenesisOptions(initOptions.GenesisPath, initOptions.ChainDataPath, initOptions.BlockchainNodeProvider); err != nil {
+			return err
+		}
+		if initOptions.FabricTopologyPath != "" && initOptions.BlockchainProvider != types.BlockchainProviderFabric.String() {
+			return fmt.Errorf("--fabric-topology is only supported with the fabric blockchain provider")
+		}
+		if initOptions.APIAuth && initOptions.MemberAPITokens {
+			return fmt.Errorf("--api-auth and --member-api-tokens cannot be used together")
+		}
+		if initOptions.APIAuth && initOptions.SandboxEnabled {
+			return fmt.Errorf("the sandbox does not support API authentication. use --sandbox-enabled=false with --api-auth")
+		}
+		if initOptions.MemberAPITokens && initOptions.SandboxEnabled {
+			return fmt.Errorf("the sandbox does not support API authentication. use --sandbox-enabled=false with --member-api-tokens")
+		}
+		if cmd.Flags().Changed("seed") && !initOptions.Deterministic {
+			return fmt.Errorf("--seed can only be used with --deterministic")
+		}
 
-		fmt.Println("initializing new FireFly stack...")
+		fmt.Println(climsgs.T(climsgs.MsgInitializing))
 
 		if len(args) > 0 {
 			stackName = args[0]
@@ -76,8 +150,8 @@ var initCmd = &cobra.Command{
 				return err
 			}
 		} else {
-			stackName, _ = prompt("stack name: ", validateStackName)
-			fmt.Println("You selected " + stackName)
+			stackName, _ = prompt(climsgs.T(climsgs.MsgPromptStackName), validateStackName)
+			fmt.Println(climsgs.T(climsgs.MsgStackSelected, stackName))
 		}
 
 		var memberCountInput string
@@ -87,7 +161,7 @@ var initCmd = &cobra.Command{
 				return err
 			}
 		} else {
-			memberCountInput, _ = prompt("number of members: ", validateCount)
+			memberCountInput, _ = prompt(climsgs.T(climsgs.MsgPromptMemberCount), validateCount)
 		}
 		memberCount, _ := strconv.Atoi(memberCountInput)
 
@@ -95,9 +169,9 @@ var initCmd = &cobra.Command{
 		initOptions.NodeNames = make([]string, 0, memberCount)
 		if promptNames {
 			for i := 0; i < memberCount; i++ {
-				name, _ := prompt(fmt.Sprintf("name for org %d: ", i), validateFFName)
+				name, _ := prompt(climsgs.T(climsgs.MsgPromptOrgName, i), validateFFName)
 				initOptions.OrgNames = append(initOptions.OrgNames, name)
-				name, _ = prompt(fmt.Sprintf("name for node %d: ", i), validateFFName)
+				name, _ = prompt(climsgs.T(climsgs.MsgPromptNodeName, i), validateFFName)
 				initOptions.NodeNames = append(initOptions.NodeNames, name)
 			}
 		} else {
@@ -107,32 +181,96 @@ var initCmd = &cobra.Command{
 			}
 		}
 
+		if initOptions.DryRun {
+			return stackManager.InitStackDryRun(stackName, memberCount, &initOptions)
+		}
+
 		if err := stackManager.InitStack(stackName, memberCount, &initOptions); err != nil {
 			return err
 		}
 
-		fmt.Printf("Stack '%s' created!\nTo start your new stack run:\n\n%s start %s\n", stackName, rootCmd.Use, stackName)
-		fmt.Printf("\nYour docker compose file for this stack can be found at: %s\n\n", filepath.Join(stackManager.Stack.StackDir, "docker-compose.yml"))
+		if err := stackManager.PrintTimingSummary("init"); err != nil {
+			return err
+		}
+		if ip := net.ParseIP(initOptions.BindAddress); !ip.IsLoopback() {
+			fmt.Printf("WARNING: the FireFly API and sandbox of each member are published on %s and can be reached from other machines on your network\n", initOptions.BindAddress)
+			if stackManager.Stack.APIAuthToken == "" && !initOptions.MemberAPITokens {
+				fmt.Printf("WARNING: the FireFly API has no authentication. Use --api-auth to require a password\n")
+			}
+		}
+		if len(stackManager.Stack.ClockSkew) > 0 {
+			fmt.Printf("WARNING: the clock skew does not apply to FireFly core, the blockchain connector or IPFS, which read the clock without libc\n")
+		}
+		for _, advisory := range stackManager.CheckResources() {
+			fmt.Printf("WARNING: %s\n", advisory)
+		}
+		if stackManager.Stack.APIAuthToken != "" {
+			fmt.Printf("The FireFly API requires basic auth with username '%s' and password '%s'\n\n", constants.APIAuthUsername, stackManager.Stack.APIAuthToken)
+		}
+		if initOptions.MemberAPITokens {
+			fmt.Printf("The FireFly API of each member requires basic auth with username '%s' and the member's token. Run '%s auth token %s --member <index>' to show a token\n\n", constants.APIAuthUsername, rootCmd.Use, stackName)
+		}
+		fmt.Print(climsgs.T(climsgs.MsgStackCreated, stackName, rootCmd.Use, stackName))
+		fmt.Printf("\n%s\n\n", climsgs.T(climsgs.MsgComposeFileLocation, filepath.Join(stackManager.Stack.StackDir, "docker-compose.yml")))
 		return nil
 	},
 }
 
 func validateStackName(stackName string) error {
 	if strings.TrimSpace(stackName) == "" {
-		return errors.New("stack name must not be empty")
+		return errcodes.New(errcodes.InvalidStackName, "stack name must not be empty")
 	}
 
 	if stackNameInvalidRegex.Find([]byte(stackName)) != nil {
-		return fmt.Errorf("stack name may not contain any character matching the regex: %s", stackNameInvalidRegex)
+		return errcodes.New(errcodes.InvalidStackName, "stack name may not contain any character matching the regex: %s", stackNameInvalidRegex)
 	}
 
 	if exists, err := stacks.CheckExists(stackName); exists {
-		return fmt.Errorf("stack '%s' already exists", stackName)
+		return errcodes.New(errcodes.StackAlreadyExists, "stack '%s' already exists", stackName)
 	} else {
 		return err
 	}
 }
 
+func validateNamePrefix(prefix string) error {
+	if prefix != "" && stackNameInvalidRegex.Find([]byte(prefix)) != nil {
+		return fmt.Errorf("name prefix may not contain any character matching the regex: %s", stackNameInvalidRegex)
+	}
+	return nil
+}
+
+func validateBindAddress(address string) error {
+	if net.ParseIP(address) == nil {
+		return fmt.Errorf("invalid bind address '%s'", address)
+	}
+	return nil
+}
+
+func validateGenesisOptions(genesisPath, chainDataPath, blockchainNodeProvider string) error {
+	if genesisPath != "" && chainDataPath != "" {
+		return fmt.Errorf("--genesis cannot be used with --chain-data, which contains its own genesis")
+	}
+	if genesisPath != "" {
+		if blockchainNodeProvider != types.BlockchainNodeProviderGeth.String() && blockchainNodeProvider != types.BlockchainNodeProviderBesu.String() {
+			return fmt.Errorf("--genesis is only supported with the geth and besu blockchain nodes")
+		}
+		if _, err := os.Stat(genesisPath); err != nil {
+			return err
+		}
+	}
+	if chainDataPath != "" {
+		if blockchainNodeProvider != types.BlockchainNodeProviderGeth.String() {
+			return fmt.Errorf("--chain-data is only supported with the geth blockchain node")
+		}
+		for _, f := range []string{ethereum.ChainDataGenesisFile, ethereum.ChainDataBlocksFile} {
+			if _, err := os.Stat(filepath.Join(chainDataPath, f)); err != nil {
+				return fmt.Errorf("chain data directory %s must contain %s", chainDataPath, f)
+			}
+		}
+	}
+	return nil
+}
+
 func validateCount(input string) error {
 	if i, err := strconv.Atoi(input); err != nil {
 		return errors.New("invalid number")
@@ -152,6 +290,12 @@ func validateFFName(input string) error {
 }
 
 func validateDatabaseProvider(input string) error {
+	switch strings.ToLower(input) {
+	case "mysql", "mariadb":
+		// MySQL and MariaDB are not offered. FireFly core only has database plugins for postgres
+		// and sqlite3, so a stack could be generated but FireFly would fail to start.
+		return fmt.Errorf("%s is not supported, because FireFly core has no database plugin for it. use --database postgres to develop against a server database", input)
+	}
 	_, err := fftypes.FFEnumParseString(context.Background(), types.DatabaseSelection, input)
 	return err
 }
@@ -175,13 +319,9 @@ func validateBlockchainProvider(providerString, nodeString string) error {
 }
 
 func validateTokensProvider(input []string, blockchainNodeProviderInput string) error {
-	tokenProviders := make([]fftypes.FFEnum, len(input))
-	for i, t := range input {
-		tp, err := fftypes.FFEnumParseString(context.Background(), types.TokenProvider, t)
-		if err != nil {
-			return err
-		}
-		tokenProviders[i] = tp
+	tokenProviders, _, err := stacks.ParseTokenProviders(context.Background(), input)
+	if err != nil {
+		return err
 	}
 
 	nodeProvider, err := fftypes.FFEnumParseString(context.Background(), types.BlockchainNodeProvider, blockchainNodeProviderInput)
@@ -209,6 +349,42 @@ func validateIPFSMode(input string) error {
 	return err
 }
 
+func validateMessageQueue(input string) error {
+	_, err := fftypes.FFEnumParseString(context.Background(), types.MessageQueue, input)
+	return err
+}
+
+// applyMinimalPreset configures a single member, gateway mode stack backed by anvil, with no
+// IPFS, data exchange, sandbox or token connectors. Flags that were set explicitly are left alone.
+func applyMinimalPreset(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 && args[1] != "1" {
+		return fmt.Errorf("a minimal stack can only have 1 member")
+	}
+	if initOptions.ExternalProcesses > 0 {
+		return fmt.Errorf("a minimal stack cannot be used with external FireFly core processes")
+	}
+	defaults := map[string]func(){
+		"multiparty":           func() { initOptions.MultipartyEnabled = false },
+		"database":             func() { initOptions.DatabaseProvider = types.DatabaseSelectionSQLite.String() },
+		"sandbox-enabled":      func() { initOptions.SandboxEnabled = false },
+		"blockchain-provider":  func() { initOptions.BlockchainProvider = types.BlockchainProviderEthereum.String() },
+		"blockchain-node":      func() { initOptions.BlockchainNodeProvider = types.BlockchainNodeProviderAnvil.String() },
+		"blockchain-connector": func() { initOptions.BlockchainConnector = types.BlockchainConnectorEvmconnect.String() },
+		"token-providers":      func() { initOptions.TokenProviders = []string{} },
+	}
+	for flag, apply := range defaults {
+		if !cmd.Flags().Changed(flag) {
+			apply()
+		}
+	}
+	if initOptions.MultipartyEnabled {
+		return fmt.Errorf("a minimal stack runs in gateway mode and cannot be used with --multiparty")
+	}
+	initOptions.DisableIPFS = true
+	initOptions.DisableDataExchange = true
+	return nil
+}
+
 func init() {
 	initCmd.Flags().IntVarP(&initOptions.FireFlyBasePort, "firefly-base-port", "p", 5000, "Mapped port base of FireFly core API (1 added for each member)")
 	initCmd.Flags().IntVarP(&initOptions.ServicesBasePort, "services-base-port", "s", 5100, "Mapped port base of services (100 added for each member)")
@@ -216,7 +392,7 @@ func init() {
 	initCmd.Flags().StringVarP(&initOptions.BlockchainConnector, "blockchain-connector", "c", "ethconnect", fmt.Sprintf("Blockchain connector to use. Options are: %v", fftypes.FFEnumValues(types.BlockchainConnector)))
 	initCmd.Flags().StringVarP(&initOptions.BlockchainProvider, "blockchain-provider", "b", "ethereum", fmt.Sprintf("Blockchain to use. Options are: %v", fftypes.FFEnumValues(types.BlockchainProvider)))
 	initCmd.Flags().StringVarP(&initOptions.BlockchainNodeProvider, "blockchain-node", "n", "geth", fmt.Sprintf("Blockchain node type to use. Options are: %v", fftypes.FFEnumValues(types.BlockchainNodeProvider)))
-	initCmd.Flags().StringArrayVarP(&initOptions.TokenProviders, "token-providers", "t", []string{"erc20_erc721"}, fmt.Sprintf("Token providers to use. Options are: %v", fftypes.FFEnumValues(types.TokenProvider)))
+	initCmd.Flags().StringArrayVarP(&initOptions.TokenProviders, "token-providers", "t", []string{"erc20_erc721"}, fmt.Sprintf("Token providers to use. Options are: %v. Use <type>:<name> to name a connector, or <type>*<count> to run several of the same type", fftypes.FFEnumValues(types.TokenProvider)))
 	initCmd.Flags().IntVarP(&initOptions.ExternalProcesses, "external", "e", 0, "Manage a number of FireFly core processes outside of the docker-compose stack - useful for development and debugging")
 	initCmd.Flags().StringVarP(&initOptions.FireFlyVersion, "release", "r", "latest", "Select the FireFly release version to use")
 	initCmd.Flags().StringVarP(&initOptions.ManifestPath, "manifest", "m", "", "Path to a manifest.json file containing the versions of each FireFly microservice to use. Overrides the --release flag.")
@@ -224,16 +400,46 @@ func init() {
 	initCmd.Flags().BoolVar(&initOptions.PrometheusEnabled, "prometheus-enabled", false, "Enables Prometheus metrics exposition and aggregation to a shared Prometheus server")
 	initCmd.Flags().BoolVar(&initOptions.SandboxEnabled, "sandbox-enabled", true, "Enables the FireFly Sandbox to be started with your FireFly stack")
 	initCmd.Flags().IntVar(&initOptions.PrometheusPort, "prometheus-port", 9090, "Port for the shared Prometheus server")
-	initCmd.Flags().StringVarP(&initOptions.ExtraCoreConfigPath, "core-config", "", "", "The path to a yaml file containing extra config for FireFly Core")
-	initCmd.Flags().StringVarP(&initOptions.ExtraConnectorConfigPath, "connector-config", "", "", "The path to a yaml file containing extra config for the blockchain connector")
+	initCmd.Flags().StringVar(&initOptions.MessageQueue, "message-queue", "none", fmt.Sprintf("Run a message broker in the stack, for apps that deliver FireFly events through a queue. Options are: %v", fftypes.FFEnumValues(types.MessageQueue)))
+	initCmd.Flags().IntVar(&initOptions.MessageQueuePort, "message-queue-port", 0, "Port for the message broker. Defaults to 4222 for NATS and 6379 for Redis")
+	initCmd.Flags().StringVarP(&initOptions.ExtraCoreConfigPath, "core-config", "", "", "The path to a yaml file containing extra config for FireFly Core. Go template placeholders such as {{ .Member.Index }}, {{ .Stack.Name }} and {{ env \"VAR\" }} are rendered for each member")
+	initCmd.Flags().StringVarP(&initOptions.ExtraConnectorConfigPath, "connector-config", "", "", "The path to a yaml file containing extra config for the blockchain connector. Go template placeholders are rendered for each member, as with --core-config")
 	initCmd.Flags().IntVarP(&initOptions.BlockPeriod, "block-period", "", -1, "Block period in seconds. Default is variable based on selected blockchain provider.")
 	initCmd.Flags().StringVarP(&initOptions.ContractAddress, "contract-address", "", "", "Do not automatically deploy a contract, instead use a pre-configured address")
 	initCmd.Flags().StringVarP(&initOptions.RemoteNodeURL, "remote-node-url", "", "", "For cases where the node is pre-existing and running remotely")
+	initCmd.Flags().BoolVar(&initOptions.RPCProxyEnabled, "rpc-proxy", false, "Send the requests to the remote node through a local proxy that caches results, and can throttle, delay and time out requests (remote-rpc only)")
+	initCmd.Flags().BoolVar(&initOptions.RPCProxy.Cache, "rpc-proxy-cache", true, "Answer requests for results that cannot change, such as receipts and old blocks, from the proxy's cache")
+	initCmd.Flags().IntVar(&initOptions.RPCProxy.RequestsPerSecond, "rpc-proxy-rate-limit", 0, "Requests per second the proxy sends to the remote node before it rejects requests with HTTP 429. 0 is unlimited")
+	initCmd.Flags().IntVar(&initOptions.RPCProxy.Budget, "rpc-proxy-budget", 0, "Requests the proxy sends to the remote node in each --rpc-proxy-budget-window, before it rejects requests with HTTP 429. 0 is unlimited")
+	initCmd.Flags().StringVar(&initOptions.RPCProxy.BudgetWindow, "rpc-proxy-budget-window", "24h", "Period that --rpc-proxy-budget applies to")
+	initCmd.Flags().StringVar(&initOptions.RPCProxy.Latency, "rpc-proxy-latency", "", "Latency, such as 250ms, that the proxy adds to each request to the remote node")
+	initCmd.Flags().Float64Var(&initOptions.RPCProxy.TimeoutPercent, "rpc-proxy-timeout-percent", 0, "Percentage of requests that the proxy holds for --rpc-proxy-timeout and then fails with HTTP 504")
+	initCmd.Flags().StringVar(&initOptions.RPCProxy.Timeout, "rpc-proxy-timeout", "30s", "How long the proxy holds a request that it times out")
 	initCmd.Flags().Int64VarP(&initOptions.ChainID, "chain-id", "", 2021, "The chain ID (Ethereum only) - also used as the network ID")
 	initCmd.Flags().IntVarP(&initOptions.RequestTimeout, "request-timeout", "", 0, "Custom request timeout (in seconds) - useful for registration to public chains")
 	initCmd.Flags().StringVarP(&initOptions.ReleaseChannel, "channel", "", "stable", fmt.Sprintf("Select the FireFly release channel to use. Options are: %v", fftypes.FFEnumValues(types.ReleaseChannelSelection)))
 	initCmd.Flags().BoolVarP(&initOptions.MultipartyEnabled, "multiparty", "", true, "Enable or disable multiparty mode")
 	initCmd.Flags().StringVarP(&initOptions.IPFSMode, "ipfs-mode", "", "private", fmt.Sprintf("Set the mode in which IFPS operates. Options are: %v", fftypes.FFEnumValues(types.IPFSMode)))
 
+	initCmd.Flags().StringVar(&initOptions.NamePrefix, "name-prefix", "", "Prefix for the names of the containers, volumes and network of the stack. Defaults to the stack name")
+	initCmd.Flags().StringArrayVar(&initOptions.Labels, "label", []string{}, "Docker label in the format key=value to attach to every container, volume and network of the stack. May be repeated")
+	initCmd.Flags().StringVar(&initOptions.BindAddress, "bind-address", "127.0.0.1", "Address to publish the FireFly API and sandbox of each member on. Use 0.0.0.0 to make them reachable from your LAN. Other services are always published on 127.0.0.1")
+	initCmd.Flags().StringArrayVar(&initOptions.DNS, "dns", []string{}, "Custom DNS server for every container of the stack. May be repeated")
+	initCmd.Flags().StringArrayVar(&initOptions.ExtraHosts, "extra-host", []string{}, "Extra /etc/hosts entry in the format hostname:ip for every container of the stack. May be repeated")
+	initCmd.Flags().BoolVar(&initOptions.APIAuth, "api-auth", false, "Generate a password and require basic auth on the FireFly API")
+	initCmd.Flags().BoolVar(&initOptions.MemberAPITokens, "member-api-tokens", false, "Generate a different token for each member and require it with basic auth on the member's FireFly API")
+	initCmd.Flags().StringVar(&initOptions.GenesisPath, "genesis", "", "Path to a genesis.json to start the chain from. Its accounts and contracts are kept, but the consensus config is replaced so the local node can seal blocks (geth and besu only)")
+	initCmd.Flags().StringVar(&initOptions.ChainDataPath, "chain-data", "", "Path to a directory written by \"ff chain export\" to import the chain from (geth only)")
+	initCmd.Flags().StringVar(&initOptions.FabricTopologyPath, "fabric-topology", "", "Path to a YAML file describing the orderer count, orgs and channels of a Fabric stack")
+	initCmd.Flags().BoolVar(&initOptions.PrivateTransactions, "private-tx", false, "Run a Tessera node for each member and enable private transactions on the blockchain node (besu and quorum only)")
+	initCmd.Flags().BoolVar(&initOptions.Minimal, "minimal", false, "Create a single member, gateway mode stack with an anvil blockchain node and no IPFS, data exchange, sandbox or token connectors")
+	initCmd.Flags().BoolVar(&initOptions.KeepFailed, "keep-failed", false, "Keep the files of a stack that fails to initialize, for debugging, instead of removing them")
+	initCmd.Flags().BoolVar(&initOptions.Deterministic, "deterministic", false, "Derive the generated keys, tokens and identifiers from --seed, so the stack is the same each time it is created. The keys are predictable, so only use this for tests and demos")
+	initCmd.Flags().Int64Var(&initOptions.Seed, "seed", 0, "The seed for --deterministic")
+	initCmd.Flags().BoolVar(&initOptions.DryRun, "dry-run", false, "Print the docker-compose.yml and generated config files without creating the stack")
+	initCmd.Flags().StringVar(&initOptions.Timezone, "timezone", "", "IANA timezone, such as Europe/London, to set in every container of the stack. Defaults to UTC")
+	initCmd.Flags().StringArrayVar(&initOptions.ClockSkew, "clock-skew", []string{}, "Skew the clock of a member by a number of seconds, in the format <member index>=<seconds>. Only affects the member's data exchange, token connectors and sandbox. May be repeated")
+	initCmd.Flags().StringVar(&initOptions.LatencyProfile, "latency-profile", "", fmt.Sprintf("Simulate network latency between members, as if they were in different regions. Options are: %v", docker.LatencyProfileNames()))
+
 	rootCmd.AddCommand(initCmd)
 }
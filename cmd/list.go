@@ -21,13 +21,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
 	"github.com/hyperledger/firefly-cli/internal/stacks"
 )
 
 var listCommand = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"ls"},
-	Short:   "list stacks",
+	Short:   climsgs.T(climsgs.MsgHelpList),
 	Long:    `List stacks`,
 	Args:    cobra.MaximumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
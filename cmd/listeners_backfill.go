@@ -0,0 +1,73 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var listenersBackfillMember int
+var listenersBackfillNamespace string
+var listenersBackfillListener string
+var listenersBackfillFromBlock string
+
+// listenersBackfillCmd represents the "listeners backfill" command
+var listenersBackfillCmd = &cobra.Command{
+	Use:   "backfill <stack_name>",
+	Short: "Replay historical events for an existing contract listener",
+	Long: `Replay historical events for an existing contract listener
+
+The listener in the member's blockchain connector is rewound to the given block,
+so its events are delivered to FireFly again. The FireFly listener and its
+subscriptions are kept, and events FireFly has already recorded are skipped.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return docker.CheckDockerConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		if listenersBackfillListener == "" {
+			return fmt.Errorf("--listener must be set")
+		}
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		listener, err := stackManager.BackfillListener(listenersBackfillMember, listenersBackfillNamespace, listenersBackfillListener, listenersBackfillFromBlock)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("replaying events for listener %s from block %s\n", listener.ID, listenersBackfillFromBlock)
+		return nil
+	},
+}
+
+func init() {
+	listenersBackfillCmd.Flags().IntVarP(&listenersBackfillMember, "member", "m", 0, "Index of the member that owns the listener")
+	listenersBackfillCmd.Flags().StringVarP(&listenersBackfillNamespace, "namespace", "n", "default", "Namespace of the listener")
+	listenersBackfillCmd.Flags().StringVarP(&listenersBackfillListener, "listener", "l", "", "ID or name of the FireFly contract listener")
+	listenersBackfillCmd.Flags().StringVar(&listenersBackfillFromBlock, "from-block", "0", "Block number to replay events from")
+	listenersCmd.AddCommand(listenersBackfillCmd)
+}
@@ -17,8 +17,10 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
 	"github.com/hyperledger/firefly-cli/internal/docker"
 	"github.com/hyperledger/firefly-cli/internal/log"
 	"github.com/hyperledger/firefly-cli/internal/stacks"
@@ -29,12 +31,14 @@ var follow bool
 
 // logsCmd represents the logs command
 var logsCmd = &cobra.Command{
-	Use:   "logs <stack_name>",
-	Short: "View log output from a stack",
+	Use:   "logs <stack_name> [service...]",
+	Short: climsgs.T(climsgs.MsgHelpLogs),
 	Long: `View log output from a stack.
 
 The most recent logs can be viewed, or you can follow the
-output with the -f flag.`,
+output with the -f flag. If service names are given, only
+the logs of those services are shown.`,
+	ValidArgsFunction: completeStackServices(false),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := log.WithVerbosity(context.Background(), verbose)
 		ctx = log.WithLogger(ctx, logger)
@@ -44,7 +48,7 @@ output with the -f flag.`,
 
 		stackManager := stacks.NewStackManager(ctx)
 		if len(args) == 0 {
-			return fmt.Errorf("no stack specified")
+			return errors.New(climsgs.T(climsgs.MsgNoStackSpecified))
 		}
 		stackName := args[0]
 
@@ -59,14 +63,15 @@ output with the -f flag.`,
 
 		if stackHasRunBefore {
 			fmt.Println("getting logs... ")
-			commandLine := []string{}
+			commandLine := docker.AllProfilesArgs()
 			if fancyFeatures {
 				commandLine = append(commandLine, "--ansi", "always")
 			}
-			commandLine = append(commandLine, "-p", stackName, "logs")
+			commandLine = append(commandLine, "-p", stackManager.Stack.ResourcePrefix(), "logs")
 			if follow {
 				commandLine = append(commandLine, "-f")
 			}
+			commandLine = append(commandLine, args[1:]...)
 			docker.RunDockerComposeCommand(ctx, stackManager.Stack.RuntimeDir, commandLine...)
 		} else {
 			fmt.Println("no logs found - stack has not been started")
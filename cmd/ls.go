@@ -21,12 +21,13 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
 	"github.com/hyperledger/firefly-cli/internal/stacks"
 )
 
 var lsCmd = &cobra.Command{
 	Use:   "ls",
-	Short: "list stacks",
+	Short: climsgs.T(climsgs.MsgHelpList),
 	Long:  `List stacks`,
 	Args:  cobra.MaximumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
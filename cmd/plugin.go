@@ -0,0 +1,66 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/plugins"
+	"github.com/spf13/cobra"
+)
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Work with FireFly CLI plugins",
+	Long: `Work with FireFly CLI plugins
+
+A plugin is any executable named ff-<name>, either in ~/.firefly/plugins or on
+your PATH. It is run with "ff <name> [args...]". The FIREFLY_STACKS_DIR and
+FIREFLY_CLI environment variables are set for the plugin, so it can read stack
+state or call back into the CLI, for example with "$FIREFLY_CLI env <stack>".`,
+}
+
+// runPluginIfFound runs the plugin with the given name if it does not clash with a built in command,
+// and then exits with the plugin's exit code. If there is no such plugin, it returns.
+func runPluginIfFound(name string, args []string) {
+	if strings.HasPrefix(name, "-") {
+		return
+	}
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return
+		}
+	}
+	if name == "help" || name == "completion" {
+		return
+	}
+	plugin, err := plugins.Find(name)
+	if err != nil || plugin == nil {
+		return
+	}
+	exitCode, err := plugins.Run(plugin, args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to run plugin '%s': %s\n", name, err)
+	}
+	os.Exit(exitCode)
+}
+
+func init() {
+	rootCmd.AddCommand(pluginCmd)
+}
@@ -0,0 +1,51 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/plugins"
+	"github.com/spf13/cobra"
+)
+
+var pluginInstallCmd = &cobra.Command{
+	Use:   "install <path_or_url>",
+	Short: "Install a plugin",
+	Long: `Install a plugin by copying an executable from a local path or a URL into
+~/.firefly/plugins. The executable must be named ff-<name>.`,
+	Example: `  ff plugin install ./bin/ff-seed
+  ff plugin install https://example.com/releases/ff-seed`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plugin, err := plugins.Install(args[0])
+		if err != nil {
+			return err
+		}
+		for _, c := range rootCmd.Commands() {
+			if c.Name() == plugin.Name || c.HasAlias(plugin.Name) {
+				fmt.Printf("WARNING: plugin '%s' has the same name as a built in command, so it will not be run\n", plugin.Name)
+			}
+		}
+		fmt.Printf("installed plugin '%s' to %s\nrun it with: %s %s\n", plugin.Name, plugin.Path, rootCmd.Use, plugin.Name)
+		return nil
+	},
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginInstallCmd)
+}
@@ -0,0 +1,48 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/plugins"
+	"github.com/spf13/cobra"
+)
+
+var pluginListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the installed plugins",
+	Long:    `List the installed plugins`,
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, err := plugins.List()
+		if err != nil {
+			return err
+		}
+		fmt.Print("FireFly CLI Plugins:\n\n")
+		for _, p := range list {
+			fmt.Printf("%s\t%s\n", p.Name, p.Path)
+		}
+		fmt.Print("\n")
+		return nil
+	},
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginListCmd)
+}
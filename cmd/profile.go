@@ -0,0 +1,88 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var profileDuration time.Duration
+var profileInterval time.Duration
+var profileJSON bool
+
+var profileCmd = &cobra.Command{
+	Use:   "profile <stack_name>",
+	Short: "Sample the resource usage of a running stack",
+	Long: `Sample the CPU, memory, network and disk usage of each container in a
+running stack over a window of time, and report the heaviest services first.
+
+The full report is also saved as JSON in the profiles directory of the stack,
+so runs on different machines or with different providers can be compared.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return docker.CheckDockerConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		if profileInterval <= 0 {
+			return fmt.Errorf("interval must be greater than zero")
+		}
+		if !profileJSON {
+			fmt.Printf("profiling stack '%s' for %s...\n", stackName, profileDuration)
+		}
+		report, err := stackManager.ProfileStack(profileDuration, profileInterval)
+		if err != nil {
+			return err
+		}
+		filename, err := stackManager.WriteProfileReport(report)
+		if err != nil {
+			return err
+		}
+		if profileJSON {
+			b, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s\n", string(b))
+			return nil
+		}
+		stacks.PrintProfileReport(report)
+		fmt.Printf("The full report can be found at: %s\n\n", filename)
+		return nil
+	},
+}
+
+func init() {
+	profileCmd.Flags().DurationVarP(&profileDuration, "duration", "d", 60*time.Second, "How long to sample resource usage for")
+	profileCmd.Flags().DurationVarP(&profileInterval, "interval", "i", 5*time.Second, "Time between samples")
+	profileCmd.Flags().BoolVar(&profileJSON, "json", false, "Print the report as JSON instead of a table")
+	rootCmd.AddCommand(profileCmd)
+}
@@ -18,10 +18,13 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
 )
 
 func prompt(promptText string, validate func(string) error) (string, error) {
@@ -44,16 +47,17 @@ func prompt(promptText string, validate func(string) error) (string, error) {
 func confirm(promptText string) error {
 	reader := bufio.NewReader(os.Stdin)
 	for {
-		fmt.Printf("%s [y/N] ", promptText)
+		fmt.Printf("%s %s ", promptText, climsgs.T(climsgs.MsgConfirmChoices))
 		if str, err := reader.ReadString('\n'); err != nil {
 			return err
 		} else {
 			str = strings.ToLower(strings.TrimSpace(str))
-			if str == "y" || str == "yes" {
-				return nil
-			} else {
-				return fmt.Errorf("confirmation declined with response: '%s'", str)
+			for _, yes := range strings.Split(climsgs.T(climsgs.MsgConfirmYes), ",") {
+				if str == yes {
+					return nil
+				}
 			}
+			return errors.New(climsgs.T(climsgs.MsgConfirmDeclined, str))
 		}
 	}
 }
@@ -72,11 +76,11 @@ func selectMenu(promptText string, options []string) (string, error) {
 			str = strings.TrimSpace(str)
 			index, err := strconv.Atoi(str)
 			if err != nil {
-				printError(fmt.Errorf("'%s' is not a valid option", str))
+				printError(errors.New(climsgs.T(climsgs.MsgInvalidOption, str)))
 				continue
 			}
 			if index < 1 || index > len(options) {
-				printError(fmt.Errorf("'%s' is not a valid option", str))
+				printError(errors.New(climsgs.T(climsgs.MsgInvalidOption, str)))
 				continue
 			}
 			return options[index-1], nil
@@ -86,8 +90,8 @@ func selectMenu(promptText string, options []string) (string, error) {
 
 func printError(err error) {
 	if fancyFeatures {
-		fmt.Printf("\u001b[31mError: %s\u001b[0m\n", err.Error())
+		fmt.Printf("\u001b[31m%s\u001b[0m\n", climsgs.T(climsgs.MsgError, err.Error()))
 	} else {
-		fmt.Printf("Error: %s\n", err.Error())
+		fmt.Println(climsgs.T(climsgs.MsgError, err.Error()))
 	}
 }
@@ -0,0 +1,77 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/errcodes"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+const protectedOverrideFlag = "i-know-what-im-doing"
+
+var unprotect bool
+
+var protectCmd = &cobra.Command{
+	Use:   "protect <stack_name>",
+	Short: "Protect a stack from being removed, reset or upgraded by accident",
+	Long: `Protect a stack from being removed, reset or upgraded by accident
+
+Once a stack is protected, the remove, reset and upgrade commands will refuse
+to run against it unless the --i-know-what-im-doing flag is set. Use --off to
+remove the protection again.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		if err := stackManager.SetProtected(!unprotect); err != nil {
+			return err
+		}
+		if unprotect {
+			fmt.Printf("stack '%s' is no longer protected\n", stackName)
+		} else {
+			fmt.Printf("stack '%s' is now protected\n", stackName)
+		}
+		return nil
+	},
+}
+
+func checkProtected(stack *types.Stack) error {
+	if stack.Protected && !iKnowWhatImDoing {
+		return errcodes.New(errcodes.StackProtected, "stack '%s' is protected. to run this command anyway, set the --%s flag, or remove the protection with '%s protect %s --off'", stack.Name, protectedOverrideFlag, rootCmd.Use, stack.Name)
+	}
+	return nil
+}
+
+func addProtectedOverrideFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&iKnowWhatImDoing, protectedOverrideFlag, false, "Allow this command to run against a protected stack")
+}
+
+func init() {
+	protectCmd.Flags().BoolVar(&unprotect, "off", false, "Remove the protection from the stack")
+	rootCmd.AddCommand(protectCmd)
+}
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/briandowns/spinner"
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
 	"github.com/hyperledger/firefly-cli/internal/log"
 	"github.com/hyperledger/firefly-cli/internal/stacks"
 	"github.com/hyperledger/firefly-cli/pkg/types"
@@ -32,10 +33,12 @@ var pullOptions types.PullOptions
 
 var pullCmd = &cobra.Command{
 	Use:   "pull <stack_name>",
-	Short: "Pull a stack",
+	Short: climsgs.T(climsgs.MsgHelpPull),
 	Long: `Pull a stack
 
-Pull the images for a stack .
+Pull the images for a stack. Each image is retried with a growing delay if it fails,
+and the images that could not be pulled are listed at the end. Images pinned by digest
+that are already present are skipped, so the command can be re-run after a failure.
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var spin *spinner.Spinner
@@ -51,7 +54,7 @@ Pull the images for a stack .
 
 		stackManager := stacks.NewStackManager(ctx)
 		if len(args) == 0 {
-			return errors.New("no stack specified")
+			return errors.New(climsgs.T(climsgs.MsgNoStackSpecified))
 		}
 		stackName := args[0]
 
@@ -72,7 +75,7 @@ Pull the images for a stack .
 }
 
 func init() {
-	pullCmd.Flags().IntVarP(&pullOptions.Retries, "retries", "r", 0, "Retry attempts to perform on image pull failure")
+	pullCmd.Flags().IntVarP(&pullOptions.Retries, "retries", "r", 3, "Retry attempts to perform on image pull failure")
 
 	rootCmd.AddCommand(pullCmd)
 }
@@ -0,0 +1,89 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var refreshOptions types.RefreshOptions
+
+var refreshCmd = &cobra.Command{
+	Use:   "refresh <stack_name>",
+	Short: "Update a running stack to the latest release and images, and roll back if it breaks",
+	Long: `Update a running stack to the latest release and images, and roll back if it breaks
+
+A stack created from a release channel is moved to the newest release of that
+channel. Each image tag in the stack's manifest is then resolved to its latest
+digest, and the new images are pulled. The services with new images are
+recreated, and FireFly core migrates its database as it starts. The stack then has to pass a
+smoke test: every service healthy, the FireFly API of each member responding
+with its org and node registered, and its WebSocket replaying recorded events.
+If it fails, the previous images are restored along with the FireFly core
+databases, which are dumped before the refresh.
+
+A stack that is already up to date is left alone, so the command is safe to
+run from cron, for example to follow the nightly builds of a stack created
+with --channel head:
+
+  0 3 * * * ff refresh shared-dev >> ~/ff-refresh.log 2>&1
+
+A protected stack also needs --i-know-what-im-doing.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return docker.CheckDockerConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		if err := checkProtected(stackManager.Stack); err != nil {
+			return err
+		}
+		changes, err := stackManager.Refresh(&refreshOptions)
+		for _, change := range changes {
+			fmt.Printf("  %s\n", change)
+		}
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 && !refreshOptions.Force {
+			fmt.Printf("stack '%s' is up to date\n", stackName)
+			return nil
+		}
+		fmt.Printf("stack '%s' was refreshed and passed its smoke test\n", stackName)
+		return nil
+	},
+}
+
+func init() {
+	refreshCmd.Flags().BoolVar(&refreshOptions.Force, "force", false, "Recreate the services and run the smoke test even if there are no new images")
+	refreshCmd.Flags().BoolVar(&refreshOptions.NoRollback, "no-rollback", false, "Leave the stack on the new images if it fails the smoke test, to debug it")
+	addProtectedOverrideFlag(refreshCmd)
+	rootCmd.AddCommand(refreshCmd)
+}
@@ -0,0 +1,76 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var refreshImagesCmd = &cobra.Command{
+	Use:   "refresh-images <stack_name>",
+	Short: "Resolve the stack's image tags to their latest digests",
+	Long: `Resolve the stack's image tags to their latest digests.
+	Stacks created with --channel head record the digest of each nightly image at init,
+	so the stack keeps running the same builds until this command bumps them.
+	Restart the stack afterwards to run the new images.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return docker.CheckDockerConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackManager := stacks.NewStackManager(ctx)
+		if len(args) == 0 {
+			return errors.New(climsgs.T(climsgs.MsgNoStackSpecified))
+		}
+		stackName := args[0]
+
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		if err := checkProtected(stackManager.Stack); err != nil {
+			return err
+		}
+		changes, err := stackManager.RefreshImages()
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			fmt.Printf("all images for stack '%s' are up to date\n", stackName)
+			return nil
+		}
+		fmt.Printf("updated images for stack '%s':\n", stackName)
+		for _, change := range changes {
+			fmt.Printf("  %s\n", change)
+		}
+		fmt.Printf("\nTo run the new images restart your stack:\n\n%s restart %s\n\n", rootCmd.Use, stackName)
+		return nil
+	},
+}
+
+func init() {
+	addProtectedOverrideFlag(refreshImagesCmd)
+	rootCmd.AddCommand(refreshImagesCmd)
+}
@@ -18,10 +18,12 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
 	"github.com/hyperledger/firefly-cli/internal/constants"
 	"github.com/hyperledger/firefly-cli/internal/docker"
 	"github.com/hyperledger/firefly-cli/internal/log"
@@ -30,9 +32,10 @@ import (
 )
 
 var removeCmd = &cobra.Command{
-	Use:     "remove <stack_name>",
-	Aliases: []string{"rm"},
-	Short:   "Completely remove a stack",
+	Use:         "remove <stack_name>",
+	Aliases:     []string{"rm"},
+	Annotations: map[string]string{explicitStackAnnotation: ""},
+	Short:       climsgs.T(climsgs.MsgHelpRemove),
 	Long: `Completely remove a stack
 
 This command will completely delete a stack, including all of its data
@@ -45,17 +48,20 @@ and configuration.`,
 		}
 		stackManager := stacks.NewStackManager(ctx)
 		if len(args) == 0 {
-			return fmt.Errorf("no stack specified")
+			return errors.New(climsgs.T(climsgs.MsgNoStackSpecified))
 		}
 		stackName := args[0]
 
 		if err := stackManager.LoadStack(stackName); err != nil {
 			return err
 		}
+		if err := checkProtected(stackManager.Stack); err != nil {
+			return err
+		}
 
 		if !force {
-			fmt.Println("WARNING: This will completely remove your stack and all of its data. Are you sure this is what you want to do?")
-			if err := confirm(fmt.Sprintf("completely delete FireFly stack '%s'", stackName)); err != nil {
+			fmt.Println(climsgs.T(climsgs.MsgRemoveWarning))
+			if err := confirm(climsgs.T(climsgs.MsgRemoveConfirm, stackName)); err != nil {
 				cancel()
 			}
 		}
@@ -63,7 +69,7 @@ and configuration.`,
 		if err := stackManager.LoadStack(stackName); err != nil {
 			return err
 		}
-		fmt.Printf("deleting FireFly stack '%s'... ", stackName)
+		fmt.Print(climsgs.T(climsgs.MsgRemoving, stackName))
 		if err := stackManager.StopStack(); err != nil {
 			return err
 		}
@@ -71,12 +77,13 @@ and configuration.`,
 			return err
 		}
 		os.RemoveAll(filepath.Join(constants.StacksDir, stackName))
-		fmt.Println("done")
+		fmt.Println(climsgs.T(climsgs.MsgDone))
 		return nil
 	},
 }
 
 func init() {
 	removeCmd.Flags().BoolVarP(&force, "force", "f", false, "Remove the stack without prompting for confirmation")
+	addProtectedOverrideFlag(removeCmd)
 	rootCmd.AddCommand(removeCmd)
 }
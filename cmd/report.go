@@ -0,0 +1,74 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var reportOutput string
+var reportOptions types.ReportOptions
+
+var reportCmd = &cobra.Command{
+	Use:   "report <stack_name>",
+	Short: "Collect the config, logs and versions of a stack into a zip for a bug report",
+	Long: `Collect the config, logs and versions of a stack into a zip for a bug report.
+
+The archive holds the config files of the stack, the docker inspect output and recent
+logs of each container, the versions of the CLI, FireFly and docker, and details of
+the host. Passwords, tokens and private keys are redacted, but check the archive before
+sharing it. Docker is not required to be working, so a report can still be made for a
+broken environment.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		if reportOutput == "" {
+			reportOutput = fmt.Sprintf("%s-report-%s.zip", stackName, time.Now().Format("20060102-150405"))
+		}
+		if !strings.HasSuffix(strings.ToLower(reportOutput), ".zip") {
+			return fmt.Errorf("the report file name must end in .zip")
+		}
+		info := getVersionInfo()
+		reportOptions.CLIVersion = strings.TrimSpace(fmt.Sprintf("%s %s", info.Version, info.Commit))
+		fmt.Printf("collecting report for stack '%s'... ", stackName)
+		if err := stackManager.CreateReport(reportOutput, &reportOptions); err != nil {
+			return err
+		}
+		fmt.Printf("done\n\nThe report has been written to: %s\n\n", reportOutput)
+		return nil
+	},
+}
+
+func init() {
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "The zip file to write. Defaults to <stack_name>-report-<time>.zip")
+	reportCmd.Flags().IntVar(&reportOptions.LogLines, "log-lines", 500, "Number of recent log lines to collect from each container")
+	rootCmd.AddCommand(reportCmd)
+}
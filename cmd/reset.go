@@ -18,8 +18,10 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
 	"github.com/hyperledger/firefly-cli/internal/docker"
 	"github.com/hyperledger/firefly-cli/internal/log"
 	"github.com/hyperledger/firefly-cli/internal/stacks"
@@ -27,8 +29,9 @@ import (
 )
 
 var resetCmd = &cobra.Command{
-	Use:   "reset <stack_name>",
-	Short: "Clear all data in a stack",
+	Use:         "reset <stack_name>",
+	Annotations: map[string]string{explicitStackAnnotation: ""},
+	Short:       climsgs.T(climsgs.MsgHelpReset),
 	Long: `Clear all data in a stack
 
 This command clears all data in a stack, but leaves the stack configuration.
@@ -45,33 +48,36 @@ Note: this will also stop the stack if it is running.
 
 		stackManager := stacks.NewStackManager(ctx)
 		if len(args) == 0 {
-			return fmt.Errorf("no stack specified")
+			return errors.New(climsgs.T(climsgs.MsgNoStackSpecified))
 		}
 		stackName := args[0]
 
 		if err := stackManager.LoadStack(stackName); err != nil {
 			return err
 		}
+		if err := checkProtected(stackManager.Stack); err != nil {
+			return err
+		}
 
 		if stackManager.IsOldFileStructure {
 			return fmt.Errorf("the FireFly stack '%s' was created with an older version of the CLI and resetting the stack is not supported. If you want to start fresh, please remove and recreate the stack", stackName)
 		}
 
 		if !force {
-			fmt.Println("WARNING: This will completely remove all transactions and data from your FireFly stack. Are you sure you want to do that?")
-			if err := confirm(fmt.Sprintf("reset all data in FireFly stack '%s'", stackName)); err != nil {
+			fmt.Println(climsgs.T(climsgs.MsgResetWarning))
+			if err := confirm(climsgs.T(climsgs.MsgResetConfirm, stackName)); err != nil {
 				cancel()
 			}
 		}
 
-		fmt.Printf("resetting FireFly stack '%s'... ", stackName)
+		fmt.Print(climsgs.T(climsgs.MsgResetting, stackName))
 		if err := stackManager.StopStack(); err != nil {
 			return err
 		}
 		if err := stackManager.ResetStack(); err != nil {
 			return err
 		}
-		fmt.Printf("done\n\nYour stack has been reset. To start your stack run:\n\n%s start %s\n\n", rootCmd.Use, stackName)
+		fmt.Printf("%s\n\n%s\n\n", climsgs.T(climsgs.MsgDone), climsgs.T(climsgs.MsgStackReset, rootCmd.Use, stackName))
 
 		return nil
 	},
@@ -79,5 +85,6 @@ Note: this will also stop the stack if it is running.
 
 func init() {
 	resetCmd.Flags().BoolVarP(&force, "force", "f", false, "Reset the stack without prompting for confirmation")
+	addProtectedOverrideFlag(resetCmd)
 	rootCmd.AddCommand(resetCmd)
 }
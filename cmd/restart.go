@@ -0,0 +1,76 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var restartNoDeps bool
+
+// restartCmd represents the restart command
+var restartCmd = &cobra.Command{
+	Use:   "restart <stack_name> [service...]",
+	Short: climsgs.T(climsgs.MsgHelpRestart),
+	Long: `Restart a stack, or only some of its services
+
+If service names are given, only those services are restarted and everything
+else is left running. Services that depend on them are restarted too, unless
+--no-deps is set. A service name like "firefly_core" applies to every member,
+while "firefly_core_1" applies only to that member.`,
+	Example: `  ff restart dev
+  ff restart dev firefly_core_1 evmconnect_1`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeStackServices(true),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return docker.CheckDockerConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+
+		fmt.Printf("restarting stack '%s'... ", stackName)
+		services, err := stackManager.RestartServices(args[1:], restartNoDeps)
+		if err != nil {
+			return err
+		}
+		if len(services) > 0 {
+			fmt.Printf("done\nrestarted: %s\n", strings.Join(services, ", "))
+		} else {
+			fmt.Print("done\n")
+		}
+		return nil
+	},
+}
+
+func init() {
+	restartCmd.Flags().BoolVar(&restartNoDeps, "no-deps", false, "Do not restart services that depend on the named services")
+	rootCmd.AddCommand(restartCmd)
+}
@@ -26,6 +26,7 @@ import (
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
 
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
 	"github.com/hyperledger/firefly-cli/internal/log"
 )
 
@@ -34,8 +35,10 @@ var ansi string
 var fancyFeatures bool
 var verbose bool
 var force bool
+var iKnowWhatImDoing bool
+var logFile string
 var logger log.Logger = &log.StdoutLogger{
-	LogLevel: log.Debug,
+	LogLevel: log.Info,
 }
 
 func GetFireflyAsciiArt() string {
@@ -53,7 +56,7 @@ func GetFireflyAsciiArt() string {
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "ff",
-	Short: "FireFly CLI is a developer tool used to manage local development stacks",
+	Short: climsgs.T(climsgs.MsgHelpRoot),
 	Long: GetFireflyAsciiArt() + `
 FireFly CLI is a developer tool used to manage local development stacks
 	
@@ -63,7 +66,7 @@ commands to manage the lifecycle of stacks.
 
 To get started run: ff init
 	`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		if ansi == "always" {
 			fancyFeatures = true
 		} else if ansi == "auto" && (isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())) {
@@ -71,6 +74,13 @@ To get started run: ff init
 		} else {
 			fancyFeatures = false
 		}
+		if verbose {
+			logger.SetLogLevel(log.Debug)
+		}
+		if logFile != "" {
+			return log.OpenLogFile(logFile)
+		}
+		return nil
 	},
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
@@ -82,7 +92,17 @@ To get started run: ff init
 func Execute() {
 	rootCmd.PersistentFlags().StringVarP(&ansi, "ansi", "", "auto", "control when to print ANSI control characters (\"never\"|\"always\"|\"auto\")")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose log output")
-	cobra.CheckErr(rootCmd.Execute())
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "append a debug log of this run, including the full output of every docker command, to the given file")
+	if len(os.Args) > 1 {
+		runPluginIfFound(os.Args[1], os.Args[2:])
+	}
+	registerCompletions(rootCmd)
+	defaultToCurrentStack(rootCmd)
+	if err := rootCmd.Execute(); err != nil {
+		log.LogFile().Error(err)
+		printErrorHint(err)
+		cobra.CheckErr(err)
+	}
 }
 
 func init() {
@@ -90,7 +110,7 @@ func init() {
 }
 
 func cancel() {
-	fmt.Println("canceled")
+	fmt.Println(climsgs.T(climsgs.MsgCanceled))
 	os.Exit(1)
 }
 
@@ -20,9 +20,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/briandowns/spinner"
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
 	"github.com/hyperledger/firefly-cli/internal/docker"
 	"github.com/hyperledger/firefly-cli/internal/log"
 	"github.com/hyperledger/firefly-cli/internal/stacks"
@@ -31,13 +34,22 @@ import (
 )
 
 var startOptions types.StartOptions
+var externalCores string
 
 var startCmd = &cobra.Command{
 	Use:   "start <stack_name>",
-	Short: "Start a stack",
+	Short: climsgs.T(climsgs.MsgHelpStart),
 	Long: `Start a stack
 
 This command will start a stack and run it in the background.
+
+Once a stack has been started for the first time, --profile can be used to
+start only some parts of it. For example, "--profile core,blockchain" starts
+FireFly and the blockchain without the token connectors or sandbox.
+
+--external-core runs the FireFly core of some members as local processes,
+for example under a debugger, with everything else in containers. The stack
+waits for each of those cores to come up before it registers them.
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var spin *spinner.Spinner
@@ -46,14 +58,22 @@ This command will start a stack and run it in the background.
 		}
 		ctx := log.WithVerbosity(context.Background(), verbose)
 		ctx = log.WithLogger(ctx, logger)
+		ctx = docker.WithDryRun(ctx, startOptions.DryRun)
 
-		if err := docker.CheckDockerConfig(); err != nil {
+		if err := docker.ValidateProfiles(startOptions.Profiles); err != nil {
 			return err
 		}
 
+		if !startOptions.DryRun {
+			if err := docker.CheckDockerConfig(); err != nil {
+				return err
+			}
+		}
+
+		startOptions.CLIVersion = getVersionInfo().Version
 		stackManager := stacks.NewStackManager(ctx)
 		if len(args) == 0 {
-			return errors.New("no stack specified")
+			return errors.New(climsgs.T(climsgs.MsgNoStackSpecified))
 		}
 		stackName := args[0]
 
@@ -61,10 +81,24 @@ This command will start a stack and run it in the background.
 			return err
 		}
 
+		if startOptions.DryRun {
+			return stackManager.StartStackDryRun()
+		}
+
+		if cmd.Flags().Changed("external-core") {
+			memberIndices, err := parseMemberIndices(externalCores)
+			if err != nil {
+				return err
+			}
+			if err := stackManager.SetExternalCores(memberIndices); err != nil {
+				return err
+			}
+		}
+
 		if runBefore, err := stackManager.Stack.HasRunBefore(); err != nil {
 			return err
 		} else if !runBefore {
-			fmt.Println("this will take a few seconds longer since this is the first time you're running this stack...")
+			fmt.Println(climsgs.T(climsgs.MsgFirstStart))
 		}
 
 		if spin != nil {
@@ -82,22 +116,52 @@ This command will start a stack and run it in the background.
 			fmt.Printf("%s\n\n", message)
 		}
 		for _, member := range stackManager.Stack.Members {
-			fmt.Printf("Web UI for member '%v': http://127.0.0.1:%v/ui\n", member.ID, member.ExposedFireflyPort)
+			fmt.Println(climsgs.T(climsgs.MsgWebUI, member.ID, member.ExposedFireflyPort))
 			if stackManager.Stack.SandboxEnabled {
-				fmt.Printf("Sandbox UI for member '%v': http://127.0.0.1:%v\n\n", member.ID, member.ExposedSandboxPort)
+				fmt.Printf("%s\n\n", climsgs.T(climsgs.MsgSandboxUI, member.ID, member.ExposedSandboxPort))
+			}
+		}
+		for _, member := range stackManager.Stack.Members {
+			if member.External {
+				fmt.Printf("The FireFly core of member %s runs outside the stack. Start it with: firefly -f %s\n", member.ID, stackManager.ExternalCoreConfigFile(member))
+				fmt.Printf("Run '%s env %s --member %s' to print its settings\n\n", rootCmd.Use, stackName, member.ID)
 			}
 		}
 
 		if stackManager.Stack.PrometheusEnabled {
-			fmt.Printf("Web UI for shared Prometheus: http://127.0.0.1:%v\n", stackManager.Stack.ExposedPrometheusPort)
+			fmt.Println(climsgs.T(climsgs.MsgPrometheusUI, stackManager.Stack.ExposedPrometheusPort))
 		}
 
-		fmt.Printf("\nTo see logs for your stack run:\n\n%s logs %s\n\n", rootCmd.Use, stackName)
+		fmt.Print("\n")
+		if err := stackManager.PrintTimingSummary("start"); err != nil {
+			return err
+		}
+		fmt.Printf("%s\n\n", climsgs.T(climsgs.MsgSeeLogs, rootCmd.Use, stackName))
 		return nil
 	},
 }
 
+// parseMemberIndices parses a comma separated list of member indices, which may be empty
+func parseMemberIndices(input string) ([]int, error) {
+	indices := []int{}
+	for _, s := range strings.Split(input, ",") {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		index, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a member index", s)
+		}
+		indices = append(indices, index)
+	}
+	return indices, nil
+}
+
 func init() {
 	startCmd.Flags().BoolVarP(&startOptions.NoRollback, "no-rollback", "b", false, "Do not automatically rollback changes if first time setup fails")
+	startCmd.Flags().BoolVar(&startOptions.SkipCompatibilityCheck, "skip-compatibility-check", false, "Start the stack even if the versions in its manifest are known not to work together")
+	startCmd.Flags().BoolVar(&startOptions.DryRun, "dry-run", false, "Print the docker-compose.yml and the docker commands that would be run without running them")
+	startCmd.Flags().StringVar(&externalCores, "external-core", "", "Indices of the members whose FireFly core runs as a local process outside the stack, for example 0,2. Pass an empty list to run every core in the stack again")
+	startCmd.Flags().StringSliceVar(&startOptions.Profiles, "profile", []string{}, fmt.Sprintf("Only start the services in these compose profiles. Options are: %s", strings.Join(docker.AllProfiles, ", ")))
 	rootCmd.AddCommand(startCmd)
 }
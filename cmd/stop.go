@@ -18,8 +18,10 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
 	"github.com/hyperledger/firefly-cli/internal/docker"
 	"github.com/hyperledger/firefly-cli/internal/log"
 	"github.com/hyperledger/firefly-cli/internal/stacks"
@@ -29,7 +31,7 @@ import (
 // stopCmd represents the stop command
 var stopCmd = &cobra.Command{
 	Use:   "stop <stack_name>",
-	Short: "Stop a stack",
+	Short: climsgs.T(climsgs.MsgHelpStop),
 	Long:  `Stop a stack`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := log.WithVerbosity(context.Background(), verbose)
@@ -39,7 +41,7 @@ var stopCmd = &cobra.Command{
 		}
 		stackManager := stacks.NewStackManager(ctx)
 		if len(args) == 0 {
-			return fmt.Errorf("no stack specified")
+			return errors.New(climsgs.T(climsgs.MsgNoStackSpecified))
 		}
 		stackName := args[0]
 
@@ -47,11 +49,11 @@ var stopCmd = &cobra.Command{
 			return err
 		}
 
-		fmt.Printf("stopping stack '%s'... ", stackName)
+		fmt.Print(climsgs.T(climsgs.MsgStopping, stackName))
 		if err := stackManager.StopStack(); err != nil {
 			return err
 		}
-		fmt.Print("done\n")
+		fmt.Println(climsgs.T(climsgs.MsgDone))
 		return nil
 	},
 }
@@ -0,0 +1,104 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var topInterval time.Duration
+var topLogLines int
+
+var topCmd = &cobra.Command{
+	Use:   "top <stack_name>",
+	Short: "Show a live dashboard of a running stack",
+	Long: `Show a live dashboard of a running stack, with the state, health, restarts,
+CPU and memory of each service, the number of messages and transactions on each
+FireFly node, and the most recent log lines across all services.
+
+Press Ctrl+C to exit. When the output is not a terminal, a single snapshot is printed.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return docker.CheckDockerConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		if topInterval <= 0 {
+			return fmt.Errorf("interval must be greater than zero")
+		}
+
+		if !isatty.IsTerminal(os.Stdout.Fd()) {
+			snapshot, err := stackManager.TopSnapshot(topLogLines)
+			if err != nil {
+				return err
+			}
+			stacks.RenderTop(os.Stdout, snapshot, 0, 0, false)
+			return nil
+		}
+
+		ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+		defer cancel()
+		// Draw on the alternate screen, so the terminal is left as it was on exit
+		fmt.Print("\u001b[?1049h\u001b[?25l")
+		defer fmt.Print("\u001b[?25h\u001b[?1049l")
+		for {
+			width, height, err := term.GetSize(int(os.Stdout.Fd()))
+			if err != nil {
+				width, height = 120, 40
+			}
+			frame := &bytes.Buffer{}
+			fmt.Fprintf(frame, "ff top - refreshing every %s, press Ctrl+C to exit\n", topInterval)
+			if snapshot, err := stackManager.TopSnapshot(topLogLines); err != nil {
+				// Keep refreshing, as the stack may be restarting
+				fmt.Fprintf(frame, "\nError: %s\n", err)
+			} else {
+				stacks.RenderTop(frame, snapshot, width, height-1, fancyFeatures)
+			}
+			fmt.Print("\u001b[H\u001b[2J")
+			fmt.Print(frame.String())
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(topInterval):
+			}
+		}
+	},
+}
+
+func init() {
+	topCmd.Flags().DurationVarP(&topInterval, "interval", "i", 2*time.Second, "Time between refreshes")
+	topCmd.Flags().IntVar(&topLogLines, "log-lines", 10, "Number of recent log lines to show")
+	rootCmd.AddCommand(topCmd)
+}
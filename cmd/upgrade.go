@@ -18,16 +18,19 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
 	"github.com/hyperledger/firefly-cli/internal/log"
 	"github.com/hyperledger/firefly-cli/internal/stacks"
 	"github.com/spf13/cobra"
 )
 
 var upgradeCmd = &cobra.Command{
-	Use:   "upgrade <stack_name>",
-	Short: "Upgrade a stack",
+	Use:         "upgrade <stack_name>",
+	Annotations: map[string]string{explicitStackAnnotation: ""},
+	Short:       climsgs.T(climsgs.MsgHelpUpgrade),
 	Long: `Upgrade a stack by pulling newer images.
 	This operation will restart the stack if running.
 	If certain containers were pinned to a specific image at init,
@@ -37,13 +40,16 @@ var upgradeCmd = &cobra.Command{
 		ctx = log.WithLogger(ctx, logger)
 		stackManager := stacks.NewStackManager(ctx)
 		if len(args) == 0 {
-			return fmt.Errorf("no stack specified")
+			return errors.New(climsgs.T(climsgs.MsgNoStackSpecified))
 		}
 		stackName := args[0]
 
 		if err := stackManager.LoadStack(stackName); err != nil {
 			return err
 		}
+		if err := checkProtected(stackManager.Stack); err != nil {
+			return err
+		}
 		fmt.Printf("upgrading stack '%s'... ", stackName)
 		if err := stackManager.UpgradeStack(); err != nil {
 			return err
@@ -54,5 +60,6 @@ var upgradeCmd = &cobra.Command{
 }
 
 func init() {
+	addProtectedOverrideFlag(upgradeCmd)
 	rootCmd.AddCommand(upgradeCmd)
 }
@@ -0,0 +1,138 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var clearCurrentStack bool
+
+var useCmd = &cobra.Command{
+	Use:   "use [stack_name]",
+	Short: "Set the stack that commands use when no stack name is given",
+	Long: `Set the stack that commands use when no stack name is given
+
+Once a current stack is set, commands such as "ff logs", "ff info" and
+"ff deploy ethereum" can be run without a stack name. Commands that delete
+the data of a stack, such as "ff remove" and "ff reset", still need it. A file named .firefly
+in the working directory, or one of its parents, that contains the name of a
+stack takes precedence, so each project can have its own stack.
+
+Without a stack name, prints the current stack and where it was set.`,
+	Example: `  ff use dev
+  ff logs
+  echo dev > .firefly
+  ff use --clear`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeStackName,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if clearCurrentStack {
+			if err := stacks.ClearCurrentStack(); err != nil {
+				return err
+			}
+			fmt.Println("cleared the current stack")
+			return nil
+		}
+		if len(args) == 1 {
+			if err := stacks.SetCurrentStack(args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("now using stack '%s'\n", args[0])
+			if name, source, err := stacks.CurrentStack(); err == nil && name != args[0] {
+				fmt.Printf("commands run in this directory still use stack '%s', which is set in %s\n", name, source)
+			}
+			return nil
+		}
+		name, source, err := stacks.CurrentStack()
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			fmt.Printf("no current stack. set one with \"ff use <stack_name>\" or a %s file\n", constants.WorkspaceFileName)
+			return nil
+		}
+		fmt.Printf("%s (set in %s)\n", name, source)
+		return nil
+	},
+}
+
+// explicitStackAnnotation marks a command that deletes or replaces the data of a stack. These always
+// need the stack name, so they can never act on the current stack by accident.
+const explicitStackAnnotation = "explicit_stack_name"
+
+// defaultToCurrentStack makes every command whose first argument is a stack fall back to the current
+// stack when the stack name is left out. The positional arguments in the command's usage tell whether
+// it was: the name is missing if there are fewer arguments than required, or if the first argument
+// is not a stack and there is room for one more, which a command with variadic arguments always has.
+func defaultToCurrentStack(cmd *cobra.Command) {
+	fields := strings.Fields(cmd.Use)
+	_, explicit := cmd.Annotations[explicitStackAnnotation]
+	if len(fields) > 1 && fields[1] == "<stack_name>" && cmd.RunE != nil && !explicit {
+		required, optional, variadic := 0, 0, false
+		for _, field := range fields[1:] {
+			switch {
+			case strings.HasPrefix(field, "<"):
+				required++
+			case strings.HasPrefix(field, "["):
+				optional++
+				variadic = variadic || strings.Contains(field, "...")
+			}
+		}
+		withStack := func(args []string) []string {
+			return withCurrentStack(args, required, optional, variadic)
+		}
+		if validateArgs := cmd.Args; validateArgs != nil {
+			cmd.Args = func(cmd *cobra.Command, args []string) error {
+				return validateArgs(cmd, withStack(args))
+			}
+		}
+		runE := cmd.RunE
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			return runE(cmd, withStack(args))
+		}
+	}
+	for _, child := range cmd.Commands() {
+		defaultToCurrentStack(child)
+	}
+}
+
+func withCurrentStack(args []string, required, optional int, variadic bool) []string {
+	if len(args) >= required {
+		if !variadic && len(args) >= required+optional {
+			return args
+		}
+		if exists, _ := stacks.CheckExists(args[0]); exists {
+			return args
+		}
+	}
+	name, _, err := stacks.CurrentStack()
+	if err != nil || name == "" {
+		return args
+	}
+	return append([]string{name}, args...)
+}
+
+func init() {
+	useCmd.Flags().BoolVar(&clearCurrentStack, "clear", false, "Forget the stack set with \"ff use\"")
+	rootCmd.AddCommand(useCmd)
+}
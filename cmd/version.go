@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"runtime/debug"
 
+	"github.com/hyperledger/firefly-cli/internal/climsgs"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 )
@@ -40,27 +41,32 @@ type Info struct {
 	License string `json:"License,omitempty" yaml:"License,omitempty"`
 }
 
+func getVersionInfo() *Info {
+	info := &Info{
+		Version: BuildVersionOverride,
+		Date:    BuildDate,
+		Commit:  BuildCommit,
+		License: "Apache-2.0",
+	}
+
+	// Where you are using go install, we will get good version information usefully from Go
+	// When we're in go-releaser in a Github action, we will have the version passed in explicitly
+	if info.Version == "" {
+		buildInfo, ok := debug.ReadBuildInfo()
+		if ok {
+			info.Version = buildInfo.Main.Version
+		}
+	}
+	return info
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
-	Short: "Prints the version info",
+	Short: climsgs.T(climsgs.MsgHelpVersion),
 	Long:  "Prints the version info of the CLI binary",
 	RunE: func(cmd *cobra.Command, args []string) error {
 
-		info := &Info{
-			Version: BuildVersionOverride,
-			Date:    BuildDate,
-			Commit:  BuildCommit,
-			License: "Apache-2.0",
-		}
-
-		// Where you are using go install, we will get good version information usefully from Go
-		// When we're in go-releaser in a Github action, we will have the version passed in explicitly
-		if info.Version == "" {
-			buildInfo, ok := debug.ReadBuildInfo()
-			if ok {
-				info.Version = buildInfo.Main.Version
-			}
-		}
+		info := getVersionInfo()
 
 		if shortened {
 			fmt.Println(info.Version)
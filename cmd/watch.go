@@ -0,0 +1,97 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/stacks"
+	"github.com/spf13/cobra"
+)
+
+var watchPaths []string
+var watchInterval time.Duration
+
+var watchCmd = &cobra.Command{
+	Use:   "watch <stack_name>",
+	Short: "Restart services when their config files change",
+	Long: `Watch the config files of a stack and restart the services that use them when
+they change. This covers the FireFly core config of each member, the blockchain
+connector and data exchange configs, and any other file of the stack that is
+mounted into a container. Connector and data exchange configs are copied into
+their volumes again before the restart.
+
+Other files or directories can be watched with --path <path>=<service>[,<service>...].
+
+Press Ctrl+C to stop watching.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return docker.CheckDockerConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := log.WithVerbosity(context.Background(), verbose)
+		ctx = log.WithLogger(ctx, logger)
+		stackName := args[0]
+		if watchInterval <= 0 {
+			return fmt.Errorf("interval must be greater than zero")
+		}
+		extraPaths, err := parseWatchPaths(watchPaths)
+		if err != nil {
+			return err
+		}
+		stackManager := stacks.NewStackManager(ctx)
+		if err := stackManager.LoadStack(stackName); err != nil {
+			return err
+		}
+		ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+		defer cancel()
+		return stackManager.Watch(ctx, extraPaths, watchInterval)
+	},
+}
+
+func parseWatchPaths(input []string) (map[string][]string, error) {
+	paths := make(map[string][]string, len(input))
+	for _, p := range input {
+		parts := strings.SplitN(p, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid path '%s'. paths must be in the format <path>=<service>[,<service>...]", p)
+		}
+		absPath, err := filepath.Abs(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(absPath); err != nil {
+			return nil, err
+		}
+		paths[absPath] = append(paths[absPath], strings.Split(parts[1], ",")...)
+	}
+	return paths, nil
+}
+
+func init() {
+	watchCmd.Flags().StringArrayVar(&watchPaths, "path", []string{}, "Extra file or directory to watch, and the services to restart when it changes, in the format <path>=<service>[,<service>...]. May be repeated")
+	watchCmd.Flags().DurationVarP(&watchInterval, "interval", "i", time.Second, "How often to check for changes")
+	rootCmd.AddCommand(watchCmd)
+}
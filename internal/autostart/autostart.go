@@ -0,0 +1,218 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autostart
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/template"
+)
+
+// Service is the information needed to start a stack from the service manager of the OS
+type Service struct {
+	Stack      string
+	Executable string
+	Path       string
+	Home       string
+	LogFile    string
+}
+
+// waitForDocker is run before the stack is started, as the docker daemon may still be starting after a reboot
+const waitForDocker = "until docker info >/dev/null 2>&1; do sleep 2; done"
+
+var systemdTemplate = template.Must(template.New("systemd").Funcs(template.FuncMap{"escape": systemdEscape}).Parse(`[Unit]
+Description=FireFly stack {{ .Stack }}
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+Environment="PATH={{ escape .Path }}" "HOME={{ escape .Home }}"
+TimeoutStartSec=15min
+ExecStartPre=/bin/sh -c "` + waitForDocker + `"
+ExecStart="{{ escape .Executable }}" start {{ .Stack }}
+ExecStop="{{ escape .Executable }}" stop {{ .Stack }}
+
+[Install]
+WantedBy=default.target
+`))
+
+var launchdTemplate = template.Must(template.New("launchd").Funcs(template.FuncMap{"xml": template.HTMLEscapeString, "shell": shellQuote}).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>{{ xml .Label }}</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>/bin/sh</string>
+		<string>-c</string>
+		<string>{{ xml "` + waitForDocker + `" }}; exec {{ xml (shell .Executable) }} start {{ xml (shell .Stack) }}</string>
+	</array>
+	<key>EnvironmentVariables</key>
+	<dict>
+		<key>PATH</key>
+		<string>{{ xml .Path }}</string>
+		<key>HOME</key>
+		<string>{{ xml .Home }}</string>
+	</dict>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>StandardOutPath</key>
+	<string>{{ xml .LogFile }}</string>
+	<key>StandardErrorPath</key>
+	<string>{{ xml .LogFile }}</string>
+</dict>
+</plist>
+`))
+
+// Enable installs a service that starts the stack when the user logs in, or at boot where the OS allows it,
+// and returns the path of the file that was installed
+func Enable(service *Service) (string, error) {
+	filename, err := serviceFile(service.Stack)
+	if err != nil {
+		return "", err
+	}
+	var content string
+	if runtime.GOOS == "darwin" {
+		content, err = launchdPlist(service)
+	} else {
+		content, err = systemdUnit(service)
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return "", err
+	}
+	if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		return "", err
+	}
+	if runtime.GOOS == "darwin" {
+		err = run("launchctl", "load", "-w", filename)
+	} else {
+		if err = run("systemctl", "--user", "daemon-reload"); err == nil {
+			err = run("systemctl", "--user", "enable", filepath.Base(filename))
+		}
+	}
+	if err != nil {
+		// Don't leave a file behind that looks like autostart is enabled
+		os.Remove(filename)
+		return "", err
+	}
+	return filename, nil
+}
+
+// IsSupported returns whether a service can be installed on this OS
+func IsSupported() bool {
+	return runtime.GOOS == "darwin" || runtime.GOOS == "linux"
+}
+
+// Disable removes the service for the stack. The stack itself keeps running.
+func Disable(stackName string) error {
+	filename, err := serviceFile(stackName)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return fmt.Errorf("autostart is not enabled for stack '%s'", stackName)
+	}
+	if runtime.GOOS == "darwin" {
+		if err := run("launchctl", "unload", "-w", filename); err != nil {
+			return err
+		}
+		return os.Remove(filename)
+	}
+	if err := run("systemctl", "--user", "disable", filepath.Base(filename)); err != nil {
+		return err
+	}
+	if err := os.Remove(filename); err != nil {
+		return err
+	}
+	return run("systemctl", "--user", "daemon-reload")
+}
+
+// IsEnabled returns whether a service is installed for the stack, and the path of its file
+func IsEnabled(stackName string) (bool, string, error) {
+	filename, err := serviceFile(stackName)
+	if err != nil {
+		return false, "", err
+	}
+	_, err = os.Stat(filename)
+	return err == nil, filename, nil
+}
+
+func serviceFile(stackName string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(home, "Library", "LaunchAgents", launchdLabel(stackName)+".plist"), nil
+	case "linux":
+		return filepath.Join(home, ".config", "systemd", "user", fmt.Sprintf("firefly-%s.service", stackName)), nil
+	default:
+		return "", fmt.Errorf("autostart services are not supported on %s", runtime.GOOS)
+	}
+}
+
+func launchdLabel(stackName string) string {
+	return "org.hyperledger.firefly." + stackName
+}
+
+func systemdUnit(service *Service) (string, error) {
+	buf := &bytes.Buffer{}
+	err := systemdTemplate.Execute(buf, service)
+	return buf.String(), err
+}
+
+func launchdPlist(service *Service) (string, error) {
+	buf := &bytes.Buffer{}
+	err := launchdTemplate.Execute(buf, struct {
+		*Service
+		Label string
+	}{service, launchdLabel(service.Stack)})
+	return buf.String(), err
+}
+
+// systemdEscape escapes the specifiers and quotes that systemd would otherwise interpret in a quoted value
+func systemdEscape(s string) string {
+	s = strings.ReplaceAll(s, "%", "%%")
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
+
+// shellQuote quotes a value for /bin/sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func run(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %s %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
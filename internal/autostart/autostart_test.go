@@ -0,0 +1,53 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autostart
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testService = &Service{
+	Stack:      "demo",
+	Executable: "/usr/local/bin/ff",
+	Path:       "/usr/local/bin:/usr/bin:/bin",
+	Home:       "/home/dev",
+	LogFile:    "/home/dev/.firefly/stacks/demo/autostart.log",
+}
+
+func TestSystemdUnit(t *testing.T) {
+	unit, err := systemdUnit(testService)
+	assert.NoError(t, err)
+	assert.Contains(t, unit, `Environment="PATH=/usr/local/bin:/usr/bin:/bin" "HOME=/home/dev"`)
+	assert.Contains(t, unit, `ExecStartPre=/bin/sh -c "until docker info >/dev/null 2>&1; do sleep 2; done"`)
+	assert.Contains(t, unit, `ExecStart="/usr/local/bin/ff" start demo`)
+	assert.Contains(t, unit, `ExecStop="/usr/local/bin/ff" stop demo`)
+	assert.Contains(t, unit, "WantedBy=default.target")
+}
+
+func TestLaunchdPlist(t *testing.T) {
+	plist, err := launchdPlist(&Service{Stack: "demo", Executable: "/Users/dev/it's & co/ff", Path: "/usr/bin", Home: "/Users/dev", LogFile: "/tmp/demo.log"})
+	assert.NoError(t, err)
+	assert.Contains(t, plist, "<string>org.hyperledger.firefly.demo</string>")
+	assert.Contains(t, plist, "<string>until docker info &gt;/dev/null 2&gt;&amp;1; do sleep 2; done; exec &#39;/Users/dev/it&#39;\\&#39;&#39;s &amp; co/ff&#39; start &#39;demo&#39;</string>")
+	assert.Contains(t, plist, "<key>RunAtLoad</key>")
+}
+
+func TestSystemdEscape(t *testing.T) {
+	assert.Equal(t, `100%% \"done\" \\`, systemdEscape(`100% "done" \`))
+}
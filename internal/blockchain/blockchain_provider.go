@@ -38,4 +38,7 @@ type IBlockchainProvider interface {
 	GetConnectorName() string
 	GetConnectorURL(org *types.Organization) string
 	GetConnectorExternalURL(org *types.Organization) string
+	ListEventStreams(member *types.Organization) ([]*types.EventStream, error)
+	ResetEventStreamListener(member *types.Organization, streamID, listenerID, fromBlock string) error
+	ExportChain(outputDir string) error
 }
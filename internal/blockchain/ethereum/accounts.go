@@ -18,22 +18,143 @@ package ethereum
 
 import (
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/random"
+	"github.com/hyperledger/firefly-cli/pkg/types"
 	"github.com/hyperledger/firefly-signer/pkg/keystorev3"
 	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
 )
 
-func CreateWalletFile(outputDirectory, prefix, password string) (*secp256k1.KeyPair, string, error) {
+// The scrypt parameters of keystorev3.NewWalletFileStandard
+const (
+	scryptN     = 1 << 10
+	scryptR     = 8
+	scryptP     = 1
+	scryptDKLen = 32
+)
+
+type walletFile struct {
+	Address string       `json:"address"`
+	ID      string       `json:"id"`
+	Version int          `json:"version"`
+	Crypto  walletCrypto `json:"crypto"`
+}
+
+type walletCrypto struct {
+	Cipher       string `json:"cipher"`
+	CipherText   string `json:"ciphertext"`
+	CipherParams struct {
+		IV string `json:"iv"`
+	} `json:"cipherparams"`
+	KDF       string `json:"kdf"`
+	MAC       string `json:"mac"`
+	KDFParams struct {
+		DKLen int    `json:"dklen"`
+		N     int    `json:"n"`
+		P     int    `json:"p"`
+		R     int    `json:"r"`
+		Salt  string `json:"salt"`
+	} `json:"kdfparams"`
+}
+
+func CreateWalletFile(stack *types.Stack, outputDirectory, prefix, password string) (*secp256k1.KeyPair, string, error) {
 	keyPair, err := secp256k1.GenerateSecp256k1KeyPair()
 	if err != nil {
 		return nil, "", err
 	}
-	wallet := keystorev3.NewWalletFileStandard(password, keyPair)
+	return writeWalletFile(stack, outputDirectory, prefix, password, keyPair)
+}
+
+// CreateWalletFileFromKey writes a wallet file for an existing hex encoded private key
+func CreateWalletFileFromKey(stack *types.Stack, outputDirectory, prefix, password, privateKey string) (*secp256k1.KeyPair, string, error) {
+	keyPair, err := ParsePrivateKey(privateKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return writeWalletFile(stack, outputDirectory, prefix, password, keyPair)
+}
+
+// ParsePrivateKey returns the key pair of a hex encoded private key
+func ParsePrivateKey(privateKey string) (*secp256k1.KeyPair, error) {
+	keyBytes, err := hex.DecodeString(strings.TrimPrefix(privateKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %s", err)
+	}
+	return secp256k1.NewSecp256k1KeyPair(keyBytes)
+}
+
+// newWalletFile encrypts a key into a version 3 keystore file, the same as keystorev3.NewWalletFileStandard
+// does, except that the salt, IV and ID are read from r. keystorev3 always reads them from crypto/rand.
+func newWalletFile(r io.Reader, password string, keyPair *secp256k1.KeyPair) ([]byte, error) {
+	salt := make([]byte, 32)
+	iv := make([]byte, aes.BlockSize)
+	id := make([]byte, 16)
+	for _, b := range [][]byte{salt, iv, id} {
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+	}
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+
+	derivedKey, err := scrypt.Key([]byte(password), salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+	// The first half of the derived key encrypts the private key, and the second half is used for the MAC
+	block, err := aes.NewCipher(derivedKey[:16])
+	if err != nil {
+		return nil, err
+	}
+	privateKey := keyPair.PrivateKeyBytes()
+	cipherText := make([]byte, len(privateKey))
+	cipher.NewCTR(block, iv).XORKeyStream(cipherText, privateKey)
+	mac := sha3.NewLegacyKeccak256()
+	mac.Write(derivedKey[16:])
+	mac.Write(cipherText)
+
+	wallet := &walletFile{
+		Address: keyPair.Address.String()[2:],
+		ID:      fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16]),
+		Version: 3,
+	}
+	wallet.Crypto.Cipher = "aes-128-ctr"
+	wallet.Crypto.CipherText = hex.EncodeToString(cipherText)
+	wallet.Crypto.CipherParams.IV = hex.EncodeToString(iv)
+	wallet.Crypto.KDF = "scrypt"
+	wallet.Crypto.MAC = hex.EncodeToString(mac.Sum(nil))
+	wallet.Crypto.KDFParams.DKLen = scryptDKLen
+	wallet.Crypto.KDFParams.N = scryptN
+	wallet.Crypto.KDFParams.P = scryptP
+	wallet.Crypto.KDFParams.R = scryptR
+	wallet.Crypto.KDFParams.Salt = hex.EncodeToString(salt)
+	return json.Marshal(wallet)
+}
+
+func writeWalletFile(stack *types.Stack, outputDirectory, prefix, password string, keyPair *secp256k1.KeyPair) (*secp256k1.KeyPair, string, error) {
+	var wallet []byte
+	if stack == nil || stack.Seed == nil {
+		wallet = keystorev3.NewWalletFileStandard(password, keyPair).JSON()
+	} else {
+		// On a deterministic stack the salt, IV and ID of the keystore file are derived from the seed
+		var err error
+		if wallet, err = newWalletFile(random.Reader(stack, fmt.Sprintf("keystore/%s", keyPair.Address.String())), password, keyPair); err != nil {
+			return nil, "", err
+		}
+	}
 
 	if err := os.MkdirAll(outputDirectory, 0755); err != nil {
 		return nil, "", err
@@ -45,8 +166,7 @@ func CreateWalletFile(outputDirectory, prefix, password string) (*secp256k1.KeyP
 	} else {
 		filename = filepath.Join(outputDirectory, keyPair.Address.String()[2:])
 	}
-	err = ioutil.WriteFile(filename, wallet.JSON(), 0755)
-	if err != nil {
+	if err := ioutil.WriteFile(filename, wallet, 0755); err != nil {
 		return nil, "", err
 	}
 	return keyPair, filename, nil
@@ -0,0 +1,54 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/hyperledger/firefly-signer/pkg/keystorev3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWalletFileFromKey(t *testing.T) {
+	seed := int64(42)
+	stack := &types.Stack{Seed: &seed}
+	privateKey := "0x8bc8e4ac1da2f5b4bc1f9d5d8a8de64a0eb0e5f2fa3d8e1e1b8b1f54d4c5a1b2"
+
+	keyPair, filename, err := CreateWalletFileFromKey(stack, t.TempDir(), "", "correcthorse", privateKey)
+	assert.NoError(t, err)
+	b, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	wallet, err := keystorev3.ReadWalletFile(b, []byte("correcthorse"))
+	assert.NoError(t, err)
+	assert.Equal(t, keyPair.PrivateKeyBytes(), wallet.KeyPair().PrivateKeyBytes())
+	_, err = keystorev3.ReadWalletFile(b, []byte("wrong"))
+	assert.Error(t, err)
+
+	// The same seed writes the same file, and without a seed the salt differs
+	_, again, err := CreateWalletFileFromKey(stack, t.TempDir(), "", "correcthorse", privateKey)
+	assert.NoError(t, err)
+	b2, err := ioutil.ReadFile(again)
+	assert.NoError(t, err)
+	assert.Equal(t, b, b2)
+	_, unseeded, err := CreateWalletFileFromKey(&types.Stack{}, t.TempDir(), "", "correcthorse", privateKey)
+	assert.NoError(t, err)
+	b3, err := ioutil.ReadFile(unseeded)
+	assert.NoError(t, err)
+	assert.NotEqual(t, b, b3)
+}
@@ -0,0 +1,293 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anvil
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum"
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/connector"
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/connector/ethconnect"
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/connector/evmconnect"
+	"github.com/hyperledger/firefly-cli/internal/core"
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+)
+
+var anvilImage = "ghcr.io/foundry-rs/foundry:latest"
+
+// 1,000,000 ETH in wei
+var accountBalance = "0xd3c21bcecceda1000000"
+
+// AnvilProvider runs a single anvil dev chain. Anvil mines instantly and starts in a couple of seconds,
+// which makes it a good fit for small stacks where fast startup matters more than a realistic network.
+type AnvilProvider struct {
+	ctx       context.Context
+	stack     *types.Stack
+	connector connector.Connector
+}
+
+func NewAnvilProvider(ctx context.Context, stack *types.Stack) *AnvilProvider {
+	var connector connector.Connector
+	switch stack.BlockchainConnector {
+	case types.BlockchainConnectorEthconnect:
+		connector = ethconnect.NewEthconnect(ctx)
+	case types.BlockchainConnectorEvmconnect:
+		connector = evmconnect.NewEvmconnect(ctx)
+	}
+
+	return &AnvilProvider{
+		ctx:       ctx,
+		stack:     stack,
+		connector: connector,
+	}
+}
+
+func (p *AnvilProvider) WriteConfig(options *types.InitOptions) error {
+	initDir := p.stack.InitDir
+	for i, member := range p.stack.Members {
+		// Generate the connector config for each member
+		connectorConfigPath := filepath.Join(initDir, "config", fmt.Sprintf("%s_%v.yaml", p.connector.Name(), i))
+		extraConnectorConfig, err := core.ReadExtraConfig(options.ExtraConnectorConfigPath, p.stack, member)
+		if err != nil {
+			return err
+		}
+		if err := p.connector.GenerateConfig(member, "anvil").WriteConfig(connectorConfigPath, extraConnectorConfig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *AnvilProvider) FirstTimeSetup() error {
+	contractsDir := path.Join(p.stack.RuntimeDir, "contracts")
+	if err := os.MkdirAll(contractsDir, 0755); err != nil {
+		return err
+	}
+
+	for i := range p.stack.Members {
+		// Copy connector config to each member's volume
+		connectorConfigPath := filepath.Join(p.stack.StackDir, "runtime", "config", fmt.Sprintf("%s_%v.yaml", p.connector.Name(), i))
+		connectorConfigVolumeName := fmt.Sprintf("%s_%s_config_%v", p.stack.ResourcePrefix(), p.connector.Name(), i)
+		docker.CopyFileToVolume(p.ctx, connectorConfigVolumeName, connectorConfigPath, "config.yaml")
+	}
+	return nil
+}
+
+func (p *AnvilProvider) PreStart() error {
+	return nil
+}
+
+func (p *AnvilProvider) PostStart(firstTimeSetup bool) error {
+	if !firstTimeSetup {
+		// Balances are kept in the anvil state file across restarts
+		return nil
+	}
+	l := log.LoggerFromContext(p.ctx)
+	for _, account := range p.stack.State.Accounts {
+		address := account.(*ethereum.Account).Address
+		l.Info(fmt.Sprintf("funding account %s", address))
+		if err := p.fundAccount(address); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *AnvilProvider) fundAccount(address string) error {
+	l := log.LoggerFromContext(p.ctx)
+	verbose := log.VerbosityFromContext(p.ctx)
+	anvilClient := NewAnvilClient(fmt.Sprintf("http://127.0.0.1:%v", p.stack.ExposedBlockchainPort))
+	retries := 10
+	for {
+		if err := anvilClient.SetBalance(address, accountBalance); err != nil {
+			if verbose {
+				l.Debug(err.Error())
+			}
+			if retries == 0 {
+				return fmt.Errorf("unable to fund account %s", address)
+			}
+			time.Sleep(time.Second * 1)
+			retries--
+		} else {
+			break
+		}
+	}
+	return nil
+}
+
+func (p *AnvilProvider) DeployFireFlyContract() (*types.ContractDeploymentResult, error) {
+	contract, err := ethereum.ReadFireFlyContract(p.ctx, p.stack)
+	if err != nil {
+		return nil, err
+	}
+	return p.connector.DeployContract(contract, "FireFly", p.stack.Members[0], nil)
+}
+
+func (p *AnvilProvider) GetDockerServiceDefinitions() []*docker.ServiceDefinition {
+	// Auto impersonation lets the connectors send transactions from the member accounts without anvil holding the keys
+	entrypoint := []string{"anvil", "--host", "0.0.0.0", "--port", "8545", "--chain-id", fmt.Sprint(p.stack.ChainID()), "--auto-impersonate", "--state", "/data/state.json"}
+	if p.stack.BlockPeriod > 0 {
+		entrypoint = append(entrypoint, "--block-time", fmt.Sprint(p.stack.BlockPeriod))
+	}
+
+	serviceDefinitions := make([]*docker.ServiceDefinition, 1)
+	serviceDefinitions[0] = &docker.ServiceDefinition{
+		ServiceName: "anvil",
+		Service: &docker.Service{
+			Image:         anvilImage,
+			ContainerName: fmt.Sprintf("%s_anvil", p.stack.ResourcePrefix()),
+			EntryPoint:    entrypoint,
+			Volumes:       []string{"anvil:/data"},
+			Logging:       docker.StandardLogOptions,
+			Ports:         []string{fmt.Sprintf("%d:8545", p.stack.ExposedBlockchainPort)},
+			HealthCheck: &docker.HealthCheck{
+				Test:     []string{"CMD", "cast", "block-number", "--rpc-url", "http://localhost:8545"},
+				Interval: "5s",
+				Timeout:  "3s",
+				Retries:  12,
+			},
+		},
+		VolumeNames: []string{"anvil"},
+	}
+	serviceDefinitions = append(serviceDefinitions, p.connector.GetServiceDefinitions(p.stack, map[string]string{"anvil": "service_healthy"})...)
+	return serviceDefinitions
+}
+
+func (p *AnvilProvider) GetBlockchainPluginConfig(stack *types.Stack, m *types.Organization) (blockchainConfig *types.BlockchainConfig) {
+	var connectorURL string
+	if m.External {
+		connectorURL = p.GetConnectorExternalURL(m)
+	} else {
+		connectorURL = p.GetConnectorURL(m)
+	}
+
+	blockchainConfig = &types.BlockchainConfig{
+		Type: "ethereum",
+		Ethereum: &types.EthereumConfig{
+			Ethconnect: &types.EthconnectConfig{
+				URL:   connectorURL,
+				Topic: m.ID,
+			},
+		},
+	}
+	return
+}
+
+func (p *AnvilProvider) GetOrgConfig(stack *types.Stack, m *types.Organization) (orgConfig *types.OrgConfig) {
+	account := m.Account.(*ethereum.Account)
+	orgConfig = &types.OrgConfig{
+		Name: m.OrgName,
+		Key:  account.Address,
+	}
+	return
+}
+
+func (p *AnvilProvider) Reset() error {
+	return nil
+}
+
+func (p *AnvilProvider) GetContracts(filename string, extraArgs []string) ([]string, error) {
+	contracts, err := ethereum.ReadContractJSON(filename)
+	if err != nil {
+		return []string{}, err
+	}
+	contractNames := make([]string, len(contracts.Contracts))
+	i := 0
+	for contractName := range contracts.Contracts {
+		contractNames[i] = contractName
+		i++
+	}
+	return contractNames, err
+}
+
+func (p *AnvilProvider) DeployContract(filename, contractName, instanceName string, member *types.Organization, extraArgs []string) (*types.ContractDeploymentResult, error) {
+	contracts, err := ethereum.ReadContractJSON(filename)
+	if err != nil {
+		return nil, err
+	}
+	return p.connector.DeployContract(contracts.Contracts[contractName], instanceName, member, extraArgs)
+}
+
+func (p *AnvilProvider) CreateAccount(args []string) (interface{}, error) {
+	var keyPair *secp256k1.KeyPair
+	var err error
+	if len(args) > 2 && args[2] != "" {
+		// An existing private key to import, rather than generating a new one
+		keyPair, err = ethereum.ParsePrivateKey(args[2])
+	} else {
+		keyPair, err = secp256k1.GenerateSecp256k1KeyPair()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	stackHasRunBefore, err := p.stack.HasRunBefore()
+	if err != nil {
+		return nil, err
+	}
+	if stackHasRunBefore {
+		if err := p.fundAccount(keyPair.Address.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ethereum.Account{
+		Address:    keyPair.Address.String(),
+		PrivateKey: hex.EncodeToString(keyPair.PrivateKey.Serialize()),
+	}, nil
+}
+
+func (p *AnvilProvider) ParseAccount(account interface{}) interface{} {
+	accountMap := account.(map[string]interface{})
+	return &ethereum.Account{
+		Address:    accountMap["address"].(string),
+		PrivateKey: accountMap["privateKey"].(string),
+	}
+}
+
+func (p *AnvilProvider) GetConnectorName() string {
+	return p.connector.Name()
+}
+
+func (p *AnvilProvider) GetConnectorURL(org *types.Organization) string {
+	return fmt.Sprintf("http://%s_%s:%v", p.connector.Name(), org.ID, p.connector.Port())
+}
+
+func (p *AnvilProvider) GetConnectorExternalURL(org *types.Organization) string {
+	return fmt.Sprintf("http://127.0.0.1:%v", org.ExposedConnectorPort)
+}
+
+func (p *AnvilProvider) ListEventStreams(member *types.Organization) ([]*types.EventStream, error) {
+	return p.connector.ListEventStreams(member)
+}
+
+func (p *AnvilProvider) ResetEventStreamListener(member *types.Organization, streamID, listenerID, fromBlock string) error {
+	return p.connector.ResetEventStreamListener(member, streamID, listenerID, fromBlock)
+}
+
+func (p *AnvilProvider) ExportChain(outputDir string) error {
+	return fmt.Errorf("chain export is not supported for anvil")
+}
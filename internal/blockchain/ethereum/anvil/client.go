@@ -0,0 +1,94 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anvil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+type AnvilClient struct {
+	rpcUrl string
+}
+
+type JSONRPCRequest struct {
+	JsonRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type JSONRPCResponse struct {
+	JsonRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Error   *JSONRPCError `json:"error,omitempty"`
+	Result  interface{}   `json:"result,omitempty"`
+}
+
+type JSONRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func NewAnvilClient(rpcUrl string) *AnvilClient {
+	return &AnvilClient{
+		rpcUrl: rpcUrl,
+	}
+}
+
+// SetBalance sets the balance of an account in wei, given as a hex string
+func (a *AnvilClient) SetBalance(address string, balance string) error {
+	requestBody, err := json.Marshal(&JSONRPCRequest{
+		JsonRPC: "2.0",
+		ID:      0,
+		Method:  "anvil_setBalance",
+		Params:  []interface{}{address, balance},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", a.rpcUrl, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("%s [%d] %s", req.URL, resp.StatusCode, responseBody)
+	}
+	var rpcResponse *JSONRPCResponse
+	err = json.Unmarshal(responseBody, &rpcResponse)
+	if err != nil {
+		return err
+	}
+	if rpcResponse.Error != nil {
+		return fmt.Errorf(rpcResponse.Error.Message)
+	}
+	return nil
+}
@@ -29,9 +29,12 @@ import (
 	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/connector/ethconnect"
 	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/connector/evmconnect"
 	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/ethsigner"
-	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/tessera"
+	"github.com/hyperledger/firefly-cli/internal/core"
 	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/random"
 	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/otiai10/copy"
 )
 
 var besuImage = "hyperledger/besu:22.4"
@@ -65,12 +68,16 @@ func (p *BesuProvider) WriteConfig(options *types.InitOptions) error {
 		return err
 	}
 
-	initDir := filepath.Join(constants.StacksDir, p.stack.Name, "init")
+	initDir := p.stack.InitDir
 	for i, member := range p.stack.Members {
 
 		// Generate the connector config for each member
 		connectorConfigPath := filepath.Join(initDir, "config", fmt.Sprintf("%s_%v.yaml", p.connector.Name(), i))
-		if err := p.connector.GenerateConfig(member, "ethsigner").WriteConfig(connectorConfigPath, options.ExtraConnectorConfigPath); err != nil {
+		extraConnectorConfig, err := core.ReadExtraConfig(options.ExtraConnectorConfigPath, p.stack, member)
+		if err != nil {
+			return err
+		}
+		if err := p.connector.GenerateConfig(member, "ethsigner").WriteConfig(connectorConfigPath, extraConnectorConfig); err != nil {
 			return nil
 		}
 
@@ -78,22 +85,28 @@ func (p *BesuProvider) WriteConfig(options *types.InitOptions) error {
 
 	// Create genesis.json
 	// Generate node key
-	nodeAddress, nodeKey := ethereum.GenerateAddressAndPrivateKey()
+	nodeAddress, nodeKey := ethereum.GenerateAddressAndPrivateKey(random.Reader(p.stack, "blockchain/nodeKey"))
 	// Write the node key to disk
 	if err := ioutil.WriteFile(filepath.Join(initDir, "blockchain", "nodeKey"), []byte(nodeKey), 0755); err != nil {
 		return err
 	}
 	// Drop the 0x on the front of the address here because that's what is expected in the genesis.json
 	genesis := CreateGenesis([]string{nodeAddress[2:]}, options.BlockPeriod, p.stack.ChainID())
-	if err := genesis.WriteGenesisJson(filepath.Join(initDir, "blockchain", "genesis.json")); err != nil {
+	if err := ethereum.WriteStackGenesis(genesis, options, filepath.Join(initDir, "blockchain")); err != nil {
 		return err
 	}
 
+	if p.stack.PrivateTransactions {
+		if err := tessera.WriteConfig(p.stack, filepath.Join(initDir, "blockchain"), "orion"); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (p *BesuProvider) FirstTimeSetup() error {
-	besuVolumeName := fmt.Sprintf("%s_besu", p.stack.Name)
+	besuVolumeName := fmt.Sprintf("%s_besu", p.stack.ResourcePrefix())
 	blockchainDir := filepath.Join(p.stack.RuntimeDir, "blockchain")
 	contractsDir := filepath.Join(p.stack.RuntimeDir, "contracts")
 
@@ -112,7 +125,7 @@ func (p *BesuProvider) FirstTimeSetup() error {
 	for i := range p.stack.Members {
 		// Copy connector config to each member's volume
 		connectorConfigPath := filepath.Join(p.stack.StackDir, "runtime", "config", fmt.Sprintf("%s_%v.yaml", p.connector.Name(), i))
-		connectorConfigVolumeName := fmt.Sprintf("%s_%s_config_%v", p.stack.Name, p.connector.Name(), i)
+		connectorConfigVolumeName := fmt.Sprintf("%s_%s_config_%v", p.stack.ResourcePrefix(), p.connector.Name(), i)
 		docker.CopyFileToVolume(p.ctx, connectorConfigVolumeName, connectorConfigPath, "config.yaml")
 	}
 
@@ -126,6 +139,17 @@ func (p *BesuProvider) FirstTimeSetup() error {
 		return err
 	}
 
+	if p.stack.PrivateTransactions {
+		if err := tessera.CopyConfigToVolumes(p.ctx, p.stack, blockchainDir); err != nil {
+			return err
+		}
+		// Besu needs the public key of the Tessera node it is paired with
+		tesseraPublicKey := filepath.Join(blockchainDir, tessera.ServiceName(p.stack.Members[0]), "tm.pub")
+		if err := docker.CopyFileToVolume(p.ctx, besuVolumeName, tesseraPublicKey, "tessera.pub"); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -154,26 +178,48 @@ func (p *BesuProvider) GetDockerServiceDefinitions() []*docker.ServiceDefinition
 			addresses = addresses + ","
 		}
 	}
-	besuCommand := fmt.Sprintf(`--genesis-file=/data/genesis.json --network-id %d --rpc-http-enabled --rpc-http-api=ETH,NET,CLIQUE --host-allowlist="*" --rpc-http-cors-origins="all" --sync-mode=FULL --discovery-enabled=false --node-private-key-file=/data/nodeKey --min-gas-price=0`, p.stack.ChainID())
+	rpcAPIs := "ETH,NET,CLIQUE"
+	if p.stack.PrivateTransactions {
+		rpcAPIs = "ETH,NET,CLIQUE,PRIV,EEA"
+	}
+	besuCommand := fmt.Sprintf(`--genesis-file=/data/genesis.json --network-id %d --rpc-http-enabled --rpc-http-api=%s --host-allowlist="*" --rpc-http-cors-origins="all" --sync-mode=FULL --discovery-enabled=false --node-private-key-file=/data/nodeKey --min-gas-price=0`, p.stack.ChainID(), rpcAPIs)
+	var dependsOn map[string]map[string]string
+	if p.stack.PrivateTransactions {
+		// The node is paired with the Tessera node of the first member
+		tesseraService := tessera.ServiceName(p.stack.Members[0])
+		besuCommand += fmt.Sprintf(" --privacy-enabled --privacy-url=http://%s:%d --privacy-public-key-file=/data/tessera.pub --privacy-flexible-groups-enabled", tesseraService, tessera.Q2TPort)
+		dependsOn = map[string]map[string]string{tesseraService: {"condition": "service_healthy"}}
+	}
 
 	serviceDefinitions := make([]*docker.ServiceDefinition, 2)
 	serviceDefinitions[0] = &docker.ServiceDefinition{
 		ServiceName: "besu",
 		Service: &docker.Service{
 			Image:         besuImage,
-			ContainerName: fmt.Sprintf("%s_besu", p.stack.Name),
+			ContainerName: fmt.Sprintf("%s_besu", p.stack.ResourcePrefix()),
 			User:          "root",
 			Command:       besuCommand,
 			Volumes: []string{
 				"besu:/data",
 			},
-			Logging: docker.StandardLogOptions,
+			Logging:   docker.StandardLogOptions,
+			DependsOn: dependsOn,
+			// The besu image has no HTTP client, so check that the RPC port accepts connections
+			HealthCheck: &docker.HealthCheck{
+				Test:     []string{"CMD", "bash", "-c", "exec 3<>/dev/tcp/127.0.0.1/8545"},
+				Interval: "5s",
+				Timeout:  "3s",
+				Retries:  24,
+			},
 		},
 
 		VolumeNames: []string{"besu"},
 	}
 	serviceDefinitions[1] = p.signer.GetDockerServiceDefinition("http://besu:8545")
 	serviceDefinitions = append(serviceDefinitions, p.connector.GetServiceDefinitions(p.stack, map[string]string{"ethsigner": "service_healthy"})...)
+	if p.stack.PrivateTransactions {
+		serviceDefinitions = append(serviceDefinitions, tessera.GetServiceDefinitions(p.stack)...)
+	}
 	return serviceDefinitions
 }
 
@@ -255,3 +301,50 @@ func (p *BesuProvider) GetConnectorURL(org *types.Organization) string {
 func (p *BesuProvider) GetConnectorExternalURL(org *types.Organization) string {
 	return fmt.Sprintf("http://127.0.0.1:%v", org.ExposedConnectorPort)
 }
+
+func (p *BesuProvider) ListEventStreams(member *types.Organization) ([]*types.EventStream, error) {
+	return p.connector.ListEventStreams(member)
+}
+
+func (p *BesuProvider) ResetEventStreamListener(member *types.Organization, streamID, listenerID, fromBlock string) error {
+	return p.connector.ResetEventStreamListener(member, streamID, listenerID, fromBlock)
+}
+
+// ExportChain stops the besu node, exports its blocks along with the genesis they were
+// created from and the key of the node that seals them, and then starts the node again
+func (p *BesuProvider) ExportChain(outputDir string) error {
+	containerName := fmt.Sprintf("%s_besu", p.stack.ResourcePrefix())
+	blockchainDir := filepath.Join(p.stack.RuntimeDir, "blockchain")
+	for _, f := range []string{ethereum.ChainDataGenesisFile, ethereum.ChainDataNodeKeyFile} {
+		if err := copy.Copy(filepath.Join(blockchainDir, f), filepath.Join(outputDir, f)); err != nil {
+			return err
+		}
+	}
+
+	if err := docker.RunDockerCommand(p.ctx, p.stack.StackDir, "stop", containerName); err != nil {
+		return err
+	}
+	// The besu database lives in the container rather than a volume, so take a copy of it to export from
+	tmpDir, err := ioutil.TempDir("", "firefly-besu-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	exportErr := docker.RunDockerCommand(p.ctx, p.stack.StackDir, "cp", fmt.Sprintf("%s:/opt/besu/database", containerName), tmpDir)
+	if err := docker.RunDockerCommand(p.ctx, p.stack.StackDir, "start", containerName); err != nil {
+		return err
+	}
+	if exportErr != nil {
+		return exportErr
+	}
+	if err := copy.Copy(filepath.Join(outputDir, ethereum.ChainDataGenesisFile), filepath.Join(tmpDir, "genesis.json")); err != nil {
+		return err
+	}
+	if err := docker.RunDockerCommand(p.ctx, p.stack.StackDir, "run", "--rm", "--user", "root",
+		"-v", fmt.Sprintf("%s:/data", tmpDir),
+		besuImage, "--data-path=/data", "--genesis-file=/data/genesis.json",
+		"blocks", "export", "--to=/data/chain.rlp"); err != nil {
+		return err
+	}
+	return copy.Copy(filepath.Join(tmpDir, ethereum.ChainDataBlocksFile), filepath.Join(outputDir, ethereum.ChainDataBlocksFile))
+}
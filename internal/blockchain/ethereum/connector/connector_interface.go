@@ -28,8 +28,10 @@ type Connector interface {
 	GenerateConfig(member *types.Organization, blockchainServiceName string) Config
 	Name() string
 	Port() int
+	ListEventStreams(member *types.Organization) ([]*types.EventStream, error)
+	ResetEventStreamListener(member *types.Organization, streamID, listenerID, fromBlock string) error
 }
 
 type Config interface {
-	WriteConfig(filename string, extraConnectorConfigPath string) error
+	WriteConfig(filename string, extraConnectorConfig []byte) error
 }
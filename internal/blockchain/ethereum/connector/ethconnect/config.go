@@ -57,13 +57,13 @@ type HTTP struct {
 	Port int `yaml:"port,omitempty"`
 }
 
-func (e *Config) WriteConfig(filename string, extraConnectorConfigPath string) error {
+func (e *Config) WriteConfig(filename string, extraConnectorConfig []byte) error {
 	configYamlBytes, _ := yaml.Marshal(e)
 	if err := ioutil.WriteFile(filepath.Join(filename), configYamlBytes, 0755); err != nil {
 		return err
 	}
-	if extraConnectorConfigPath != "" {
-		c, err := conflate.FromFiles(filename, extraConnectorConfigPath)
+	if len(extraConnectorConfig) > 0 {
+		c, err := conflate.FromData(configYamlBytes, extraConnectorConfig)
 		if err != nil {
 			return err
 		}
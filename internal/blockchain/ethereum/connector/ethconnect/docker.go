@@ -34,7 +34,7 @@ func (e *Ethconnect) GetServiceDefinitions(s *types.Stack, dependentServices map
 			ServiceName: "ethconnect_" + member.ID,
 			Service: &docker.Service{
 				Image:         s.VersionManifest.Ethconnect.GetDockerImageString(),
-				ContainerName: fmt.Sprintf("%s_ethconnect_%v", s.Name, i),
+				ContainerName: fmt.Sprintf("%s_ethconnect_%v", s.ResourcePrefix(), i),
 				Command:       "server -f ./config/config.yaml -d 2",
 				DependsOn:     dependsOn,
 				Ports:         []string{fmt.Sprintf("%d:8080", member.ExposedConnectorPort)},
@@ -44,6 +44,12 @@ func (e *Ethconnect) GetServiceDefinitions(s *types.Stack, dependentServices map
 					fmt.Sprintf("ethconnect_events_%s:/ethconnect/events", member.ID),
 				},
 				Logging: docker.StandardLogOptions,
+				HealthCheck: &docker.HealthCheck{
+					Test:     []string{"CMD", "wget", "-q", "-O", "-", "http://localhost:8080/status"},
+					Interval: "5s",
+					Timeout:  "3s",
+					Retries:  12,
+				},
 			},
 			VolumeNames: []string{
 				fmt.Sprintf("ethconnect_config_%v", member.ID),
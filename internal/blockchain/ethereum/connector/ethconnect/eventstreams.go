@@ -0,0 +1,73 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethconnect
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/core"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+type ethconnectEventStream struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+type ethconnectSubscription struct {
+	ID        string `json:"id"`
+	Name      string `json:"name,omitempty"`
+	Stream    string `json:"stream"`
+	FromBlock string `json:"fromBlock,omitempty"`
+}
+
+func (e *Ethconnect) ListEventStreams(member *types.Organization) ([]*types.EventStream, error) {
+	ethconnectURL := fmt.Sprintf("http://127.0.0.1:%v", member.ExposedConnectorPort)
+	var streams []*ethconnectEventStream
+	if err := core.RequestWithRetry(e.ctx, "GET", fmt.Sprintf("%s/eventstreams", ethconnectURL), nil, &streams); err != nil {
+		return nil, err
+	}
+	var subs []*ethconnectSubscription
+	if err := core.RequestWithRetry(e.ctx, "GET", fmt.Sprintf("%s/subscriptions", ethconnectURL), nil, &subs); err != nil {
+		return nil, err
+	}
+
+	result := make([]*types.EventStream, len(streams))
+	for i, stream := range streams {
+		result[i] = &types.EventStream{
+			ID:        stream.ID,
+			Name:      stream.Name,
+			Listeners: []*types.EventStreamListener{},
+		}
+		for _, sub := range subs {
+			if sub.Stream == stream.ID {
+				result[i].Listeners = append(result[i].Listeners, &types.EventStreamListener{
+					ID:        sub.ID,
+					Name:      sub.Name,
+					FromBlock: sub.FromBlock,
+				})
+			}
+		}
+	}
+	return result, nil
+}
+
+func (e *Ethconnect) ResetEventStreamListener(member *types.Organization, streamID, listenerID, fromBlock string) error {
+	ethconnectURL := fmt.Sprintf("http://127.0.0.1:%v", member.ExposedConnectorPort)
+	body := map[string]string{"fromBlock": fromBlock}
+	return core.RequestWithRetry(e.ctx, "POST", fmt.Sprintf("%s/subscriptions/%s/reset", ethconnectURL, listenerID), body, nil)
+}
@@ -22,6 +22,7 @@ import (
 	"path/filepath"
 
 	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/connector"
+	"github.com/hyperledger/firefly-cli/internal/docker"
 	"github.com/hyperledger/firefly-cli/pkg/types"
 	"github.com/miracl/conflate"
 	"gopkg.in/yaml.v3"
@@ -73,13 +74,13 @@ type GasOracleConfig struct {
 	Mode string `yaml:"mode,omitempty"`
 }
 
-func (e *Config) WriteConfig(filename string, extraEvmconnectConfigPath string) error {
+func (e *Config) WriteConfig(filename string, extraConnectorConfig []byte) error {
 	configYamlBytes, _ := yaml.Marshal(e)
 	if err := ioutil.WriteFile(filepath.Join(filename), configYamlBytes, 0755); err != nil {
 		return err
 	}
-	if extraEvmconnectConfigPath != "" {
-		c, err := conflate.FromFiles(filename, extraEvmconnectConfigPath)
+	if len(extraConnectorConfig) > 0 {
+		c, err := conflate.FromData(configYamlBytes, extraConnectorConfig)
 		if err != nil {
 			return err
 		}
@@ -133,9 +134,5 @@ func (e *Evmconnect) GenerateConfig(org *types.Organization, blockchainServiceNa
 }
 
 func getCoreURL(org *types.Organization) string {
-	host := fmt.Sprintf("firefly_core_%v", org.ID)
-	if org.External {
-		host = "host.docker.internal"
-	}
-	return fmt.Sprintf("http://%s:%v", host, org.ExposedFireflyPort)
+	return fmt.Sprintf("http://%s:%v", docker.CoreHost(org), org.ExposedFireflyPort)
 }
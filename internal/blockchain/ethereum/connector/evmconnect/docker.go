@@ -30,11 +30,16 @@ func (e *Evmconnect) GetServiceDefinitions(s *types.Stack, dependentServices map
 	}
 	serviceDefinitions := make([]*docker.ServiceDefinition, len(s.Members))
 	for i, member := range s.Members {
+		var extraHosts []string
+		if member.External {
+			// The connector calls back to FireFly core, which runs on the docker host for this member
+			extraHosts = []string{docker.HostGateway}
+		}
 		serviceDefinitions[i] = &docker.ServiceDefinition{
 			ServiceName: "evmconnect_" + member.ID,
 			Service: &docker.Service{
 				Image:         s.VersionManifest.Evmconnect.GetDockerImageString(),
-				ContainerName: fmt.Sprintf("%s_evmconnect_%v", s.Name, i),
+				ContainerName: fmt.Sprintf("%s_evmconnect_%v", s.ResourcePrefix(), i),
 				Command:       "-f /evmconnect/config/config.yaml",
 				DependsOn:     dependsOn,
 				Ports:         []string{fmt.Sprintf("%d:%v", member.ExposedConnectorPort, e.Port())},
@@ -42,7 +47,14 @@ func (e *Evmconnect) GetServiceDefinitions(s *types.Stack, dependentServices map
 					fmt.Sprintf("evmconnect_config_%s:/evmconnect/config", member.ID),
 					fmt.Sprintf("evmconnect_leveldb_%s:/evmconnect/leveldb", member.ID),
 				},
-				Logging: docker.StandardLogOptions,
+				Logging:    docker.StandardLogOptions,
+				ExtraHosts: extraHosts,
+				HealthCheck: &docker.HealthCheck{
+					Test:     []string{"CMD", "wget", "-q", "-O", "-", fmt.Sprintf("http://localhost:%v/eventstreams", e.Port())},
+					Interval: "5s",
+					Timeout:  "3s",
+					Retries:  12,
+				},
 			},
 			VolumeNames: []string{
 				fmt.Sprintf("evmconnect_config_%s", member.ID),
@@ -0,0 +1,72 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evmconnect
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/core"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+type evmconnectEventStream struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+type evmconnectListener struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name,omitempty"`
+	FromBlock  string      `json:"fromBlock,omitempty"`
+	Checkpoint interface{} `json:"checkpoint,omitempty"`
+}
+
+func (e *Evmconnect) ListEventStreams(member *types.Organization) ([]*types.EventStream, error) {
+	evmconnectURL := fmt.Sprintf("http://127.0.0.1:%v", member.ExposedConnectorPort)
+	var streams []*evmconnectEventStream
+	if err := core.RequestWithRetry(e.ctx, "GET", fmt.Sprintf("%s/eventstreams", evmconnectURL), nil, &streams); err != nil {
+		return nil, err
+	}
+
+	result := make([]*types.EventStream, len(streams))
+	for i, stream := range streams {
+		var listeners []*evmconnectListener
+		if err := core.RequestWithRetry(e.ctx, "GET", fmt.Sprintf("%s/eventstreams/%s/listeners", evmconnectURL, stream.ID), nil, &listeners); err != nil {
+			return nil, err
+		}
+		result[i] = &types.EventStream{
+			ID:        stream.ID,
+			Name:      stream.Name,
+			Listeners: make([]*types.EventStreamListener, len(listeners)),
+		}
+		for j, l := range listeners {
+			result[i].Listeners[j] = &types.EventStreamListener{
+				ID:         l.ID,
+				Name:       l.Name,
+				FromBlock:  l.FromBlock,
+				Checkpoint: l.Checkpoint,
+			}
+		}
+	}
+	return result, nil
+}
+
+func (e *Evmconnect) ResetEventStreamListener(member *types.Organization, streamID, listenerID, fromBlock string) error {
+	evmconnectURL := fmt.Sprintf("http://127.0.0.1:%v", member.ExposedConnectorPort)
+	body := map[string]string{"fromBlock": fromBlock}
+	return core.RequestWithRetry(e.ctx, "POST", fmt.Sprintf("%s/eventstreams/%s/listeners/%s/reset", evmconnectURL, streamID, listenerID), body, nil)
+}
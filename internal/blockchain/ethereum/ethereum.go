@@ -21,6 +21,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"path/filepath"
 
 	secp256k1 "github.com/btcsuite/btcd/btcec"
@@ -36,8 +37,11 @@ type Account struct {
 	PrivateKey string `json:"privateKey"`
 }
 
-func GenerateAddressAndPrivateKey() (address string, privateKey string) {
-	newPrivateKey, _ := secp256k1.NewPrivateKey(secp256k1.S256())
+// GenerateAddressAndPrivateKey generates a private key from the bytes of r, and returns it with its address
+func GenerateAddressAndPrivateKey(r io.Reader) (address string, privateKey string) {
+	keyBytes := make([]byte, 32)
+	_, _ = io.ReadFull(r, keyBytes)
+	newPrivateKey, _ := secp256k1.PrivKeyFromBytes(secp256k1.S256(), keyBytes)
 	privateKeyBytes := newPrivateKey.Serialize()
 	encodedPrivateKey := "0x" + hex.EncodeToString(privateKeyBytes)
 	// Remove the "04" Suffix byte when computing the address. This byte indicates that it is an uncompressed public key.
@@ -56,7 +60,7 @@ func ReadFireFlyContract(ctx context.Context, s *types.Stack) (*ethtypes.Compile
 	var containerName string
 	for _, member := range s.Members {
 		if !member.External {
-			containerName = fmt.Sprintf("%s_firefly_core_%s", s.Name, member.ID)
+			containerName = fmt.Sprintf("%s_firefly_core_%s", s.ResourcePrefix(), member.ID)
 			break
 		}
 	}
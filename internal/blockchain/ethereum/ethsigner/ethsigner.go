@@ -28,9 +28,9 @@ import (
 	"strings"
 
 	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum"
-	"github.com/hyperledger/firefly-cli/internal/constants"
 	"github.com/hyperledger/firefly-cli/internal/docker"
 	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
 )
 
 // TODO: Probably randomize this and make it different per member?
@@ -53,7 +53,7 @@ func NewEthSignerProvider(ctx context.Context, stack *types.Stack) *EthSignerPro
 func (p *EthSignerProvider) WriteConfig(options *types.InitOptions, rpcURL string) error {
 
 	// Write the password that will be used to encrypt the private key
-	initDir := filepath.Join(constants.StacksDir, p.stack.Name, "init")
+	initDir := p.stack.InitDir
 	blockchainDirectory := filepath.Join(initDir, "blockchain")
 	if err := os.MkdirAll(blockchainDirectory, 0755); err != nil {
 		return err
@@ -71,7 +71,7 @@ func (p *EthSignerProvider) WriteConfig(options *types.InitOptions, rpcURL strin
 }
 
 func (p *EthSignerProvider) FirstTimeSetup() error {
-	ethsignerVolumeName := fmt.Sprintf("%s_ethsigner", p.stack.Name)
+	ethsignerVolumeName := fmt.Sprintf("%s_ethsigner", p.stack.ResourcePrefix())
 	blockchainDir := filepath.Join(p.stack.RuntimeDir, "blockchain")
 	contractsDir := filepath.Join(p.stack.RuntimeDir, "contracts")
 
@@ -85,7 +85,7 @@ func (p *EthSignerProvider) FirstTimeSetup() error {
 
 	// Copy the signer config to the volume
 	signerConfigPath := filepath.Join(p.stack.StackDir, "runtime", "config", "ethsigner.yaml")
-	signerConfigVolumeName := fmt.Sprintf("%s_ethsigner_config", p.stack.Name)
+	signerConfigVolumeName := fmt.Sprintf("%s_ethsigner_config", p.stack.ResourcePrefix())
 	docker.CopyFileToVolume(p.ctx, signerConfigVolumeName, signerConfigPath, "firefly.ffsigner")
 
 	// Copy the wallet files all members to the blockchain volume
@@ -143,7 +143,7 @@ func (p *EthSignerProvider) GetDockerServiceDefinition(rpcURL string) *docker.Se
 		ServiceName: "ethsigner",
 		Service: &docker.Service{
 			Image:         p.stack.VersionManifest.Signer.GetDockerImageString(),
-			ContainerName: fmt.Sprintf("%s_ethsigner", p.stack.Name),
+			ContainerName: fmt.Sprintf("%s_ethsigner", p.stack.ResourcePrefix()),
 			User:          "root",
 			Command:       p.getCommand(rpcURL),
 			Volumes: []string{
@@ -176,7 +176,7 @@ func (p *EthSignerProvider) GetDockerServiceDefinition(rpcURL string) *docker.Se
 }
 
 func (p *EthSignerProvider) CreateAccount(args []string) (interface{}, error) {
-	ethsignerVolumeName := fmt.Sprintf("%s_ethsigner", p.stack.Name)
+	ethsignerVolumeName := fmt.Sprintf("%s_ethsigner", p.stack.ResourcePrefix())
 	var directory string
 	stackHasRunBefore, err := p.stack.HasRunBefore()
 	if err != nil {
@@ -189,7 +189,14 @@ func (p *EthSignerProvider) CreateAccount(args []string) (interface{}, error) {
 	}
 
 	outputDirectory := filepath.Join(directory, "blockchain", "keystore")
-	keyPair, walletFilePath, err := ethereum.CreateWalletFile(outputDirectory, "", keyPassword)
+	var keyPair *secp256k1.KeyPair
+	var walletFilePath string
+	if len(args) > 2 && args[2] != "" {
+		// An existing private key to import, rather than generating a new one
+		keyPair, walletFilePath, err = ethereum.CreateWalletFileFromKey(p.stack, outputDirectory, "", keyPassword, args[2])
+	} else {
+		keyPair, walletFilePath, err = ethereum.CreateWalletFile(p.stack, outputDirectory, "", keyPassword)
+	}
 	if err != nil {
 		return nil, err
 	}
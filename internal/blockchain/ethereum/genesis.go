@@ -0,0 +1,147 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/otiai10/copy"
+)
+
+// The files in a chain data directory, as written by "ff chain export" and read by "ff init --chain-data"
+const (
+	ChainDataGenesisFile  = "genesis.json"
+	ChainDataBlocksFile   = "chain.rlp"
+	ChainDataAccountsFile = "accounts.json"
+	ChainDataNodeKeyFile  = "nodeKey"
+)
+
+// ReadGenesis reads a genesis file as generic JSON, so that any fields the CLI doesn't know about are kept
+func ReadGenesis(filename string) (map[string]interface{}, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var genesis map[string]interface{}
+	if err := json.Unmarshal(b, &genesis); err != nil {
+		return nil, fmt.Errorf("failed to parse genesis file %s: %s", filename, err)
+	}
+	return genesis, nil
+}
+
+// GetGenesisChainID returns the chain ID set in the config of a genesis file, if there is one
+func GetGenesisChainID(genesis map[string]interface{}) (int64, bool) {
+	config, ok := genesis["config"].(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	chainID, ok := config["chainId"].(float64)
+	return int64(chainID), ok
+}
+
+// ReadChainDataAccounts returns the accounts that were exported with a chain, so the stack
+// importing it can sign with the same keys as the stack that created it
+func ReadChainDataAccounts(chainDataDir string) ([]*Account, error) {
+	b, err := ioutil.ReadFile(filepath.Join(chainDataDir, ChainDataAccountsFile))
+	if err != nil {
+		return nil, err
+	}
+	var accounts []*Account
+	if err := json.Unmarshal(b, &accounts); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// WriteStackGenesis writes the genesis file for a new stack. A chain data directory is used as is,
+// because its blocks only import on top of the exact genesis they were exported with. A user supplied
+// genesis keeps its accounts, contracts and fork config, but takes the consensus config and signers
+// of the generated genesis, so the local node is able to seal blocks.
+func WriteStackGenesis(generated interface{}, options *types.InitOptions, blockchainDir string) error {
+	filename := filepath.Join(blockchainDir, "genesis.json")
+	switch {
+	case options.ChainDataPath != "":
+		if err := copy.Copy(filepath.Join(options.ChainDataPath, ChainDataGenesisFile), filename); err != nil {
+			return err
+		}
+		return copy.Copy(filepath.Join(options.ChainDataPath, ChainDataBlocksFile), filepath.Join(blockchainDir, ChainDataBlocksFile))
+	case options.GenesisPath != "":
+		userGenesis, err := ReadGenesis(options.GenesisPath)
+		if err != nil {
+			return err
+		}
+		merged, err := mergeGenesis(userGenesis, generated)
+		if err != nil {
+			return err
+		}
+		return writeJSON(filename, merged)
+	default:
+		return writeJSON(filename, generated)
+	}
+}
+
+func mergeGenesis(userGenesis map[string]interface{}, generated interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(generated)
+	if err != nil {
+		return nil, err
+	}
+	var generatedGenesis map[string]interface{}
+	if err := json.Unmarshal(b, &generatedGenesis); err != nil {
+		return nil, err
+	}
+
+	config, ok := userGenesis["config"].(map[string]interface{})
+	if !ok {
+		config = map[string]interface{}{}
+		userGenesis["config"] = config
+	}
+	generatedConfig := generatedGenesis["config"].(map[string]interface{})
+	delete(config, "ethash")
+	config["clique"] = generatedConfig["clique"]
+	config["chainId"] = generatedConfig["chainId"]
+	userGenesis["extraData"] = generatedGenesis["extraData"]
+	userGenesis["difficulty"] = generatedGenesis["difficulty"]
+
+	alloc, ok := userGenesis["alloc"].(map[string]interface{})
+	if !ok {
+		alloc = map[string]interface{}{}
+		userGenesis["alloc"] = alloc
+	}
+	existing := map[string]bool{}
+	for address := range alloc {
+		existing[strings.ToLower(strings.TrimPrefix(address, "0x"))] = true
+	}
+	for address, a := range generatedGenesis["alloc"].(map[string]interface{}) {
+		if !existing[strings.ToLower(strings.TrimPrefix(address, "0x"))] {
+			alloc[address] = a
+		}
+	}
+	return userGenesis, nil
+}
+
+func writeJSON(filename string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", " ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, b, 0755)
+}
@@ -30,10 +30,13 @@ import (
 	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/connector"
 	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/connector/ethconnect"
 	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/connector/evmconnect"
-	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/internal/core"
 	"github.com/hyperledger/firefly-cli/internal/docker"
 	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/random"
 	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/hyperledger/firefly-signer/pkg/secp256k1"
+	"github.com/otiai10/copy"
 )
 
 var gethImage = "ethereum/client-go:release-1.10"
@@ -64,11 +67,15 @@ func NewGethProvider(ctx context.Context, stack *types.Stack) *GethProvider {
 }
 
 func (p *GethProvider) WriteConfig(options *types.InitOptions) error {
-	initDir := filepath.Join(constants.StacksDir, p.stack.Name, "init")
+	initDir := p.stack.InitDir
 	for i, member := range p.stack.Members {
 		// Generate the connector config for each member
 		connectorConfigPath := filepath.Join(initDir, "config", fmt.Sprintf("%s_%v.yaml", p.connector.Name(), i))
-		if err := p.connector.GenerateConfig(member, "geth").WriteConfig(connectorConfigPath, options.ExtraConnectorConfigPath); err != nil {
+		extraConnectorConfig, err := core.ReadExtraConfig(options.ExtraConnectorConfigPath, p.stack, member)
+		if err != nil {
+			return err
+		}
+		if err := p.connector.GenerateConfig(member, "geth").WriteConfig(connectorConfigPath, extraConnectorConfig); err != nil {
 			return nil
 		}
 	}
@@ -81,7 +88,7 @@ func (p *GethProvider) WriteConfig(options *types.InitOptions) error {
 		addresses[i] = address[2:]
 	}
 	genesis := CreateGenesis(addresses, options.BlockPeriod, p.stack.ChainID())
-	if err := genesis.WriteGenesisJson(filepath.Join(initDir, "blockchain", "genesis.json")); err != nil {
+	if err := ethereum.WriteStackGenesis(genesis, options, filepath.Join(initDir, "blockchain")); err != nil {
 		return err
 	}
 
@@ -89,7 +96,7 @@ func (p *GethProvider) WriteConfig(options *types.InitOptions) error {
 }
 
 func (p *GethProvider) FirstTimeSetup() error {
-	gethVolumeName := fmt.Sprintf("%s_geth", p.stack.Name)
+	gethVolumeName := fmt.Sprintf("%s_geth", p.stack.ResourcePrefix())
 	blockchainDir := path.Join(p.stack.RuntimeDir, "blockchain")
 	contractsDir := path.Join(p.stack.RuntimeDir, "contracts")
 
@@ -100,7 +107,7 @@ func (p *GethProvider) FirstTimeSetup() error {
 	for i := range p.stack.Members {
 		// Copy connector config to each member's volume
 		connectorConfigPath := filepath.Join(p.stack.StackDir, "runtime", "config", fmt.Sprintf("%s_%v.yaml", p.connector.Name(), i))
-		connectorConfigVolumeName := fmt.Sprintf("%s_%s_config_%v", p.stack.Name, p.connector.Name(), i)
+		connectorConfigVolumeName := fmt.Sprintf("%s_%s_config_%v", p.stack.ResourcePrefix(), p.connector.Name(), i)
 		docker.CopyFileToVolume(p.ctx, connectorConfigVolumeName, connectorConfigPath, "config.yaml")
 	}
 
@@ -120,6 +127,17 @@ func (p *GethProvider) FirstTimeSetup() error {
 		return err
 	}
 
+	// Import the blocks of an exported chain, if the stack was created from one
+	blocksFile := path.Join(blockchainDir, ethereum.ChainDataBlocksFile)
+	if _, err := os.Stat(blocksFile); err == nil {
+		if err := docker.CopyFileToVolume(p.ctx, gethVolumeName, blocksFile, ethereum.ChainDataBlocksFile); err != nil {
+			return err
+		}
+		if err := docker.RunDockerCommand(p.ctx, p.stack.StackDir, "run", "--rm", "-v", fmt.Sprintf("%s:/data", gethVolumeName), gethImage, "--datadir", "/data", "import", path.Join("/data", ethereum.ChainDataBlocksFile)); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -179,15 +197,21 @@ func (p *GethProvider) GetDockerServiceDefinitions() []*docker.ServiceDefinition
 		ServiceName: "geth",
 		Service: &docker.Service{
 			Image:         gethImage,
-			ContainerName: fmt.Sprintf("%s_geth", p.stack.Name),
+			ContainerName: fmt.Sprintf("%s_geth", p.stack.ResourcePrefix()),
 			Command:       gethCommand,
 			Volumes:       []string{"geth:/data"},
 			Logging:       docker.StandardLogOptions,
 			Ports:         []string{fmt.Sprintf("%d:8545", p.stack.ExposedBlockchainPort)},
+			HealthCheck: &docker.HealthCheck{
+				Test:     []string{"CMD", "wget", "-q", "-O", "-", "--header", "Content-Type: application/json", "--post-data", `{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":1}`, "http://localhost:8545"},
+				Interval: "5s",
+				Timeout:  "3s",
+				Retries:  12,
+			},
 		},
 		VolumeNames: []string{"geth"},
 	}
-	serviceDefinitions = append(serviceDefinitions, p.connector.GetServiceDefinitions(p.stack, map[string]string{"geth": "service_started"})...)
+	serviceDefinitions = append(serviceDefinitions, p.connector.GetServiceDefinitions(p.stack, map[string]string{"geth": "service_healthy"})...)
 	return serviceDefinitions
 }
 
@@ -247,7 +271,7 @@ func (p *GethProvider) DeployContract(filename, contractName, instanceName strin
 }
 
 func (p *GethProvider) CreateAccount(args []string) (interface{}, error) {
-	gethVolumeName := fmt.Sprintf("%s_geth", p.stack.Name)
+	gethVolumeName := fmt.Sprintf("%s_geth", p.stack.ResourcePrefix())
 	var directory string
 	stackHasRunBefore, err := p.stack.HasRunBefore()
 	if err != nil {
@@ -259,9 +283,16 @@ func (p *GethProvider) CreateAccount(args []string) (interface{}, error) {
 		directory = p.stack.InitDir
 	}
 
-	prefix := strconv.FormatInt(time.Now().UnixNano(), 10)
+	prefix := strconv.FormatInt(random.Now(p.stack).UnixNano(), 10)
 	outputDirectory := filepath.Join(directory, "blockchain", "keystore")
-	keyPair, walletFilePath, err := ethereum.CreateWalletFile(outputDirectory, prefix, keyPassword)
+	var keyPair *secp256k1.KeyPair
+	var walletFilePath string
+	if len(args) > 2 && args[2] != "" {
+		// An existing private key to import, rather than generating a new one
+		keyPair, walletFilePath, err = ethereum.CreateWalletFileFromKey(p.stack, outputDirectory, prefix, keyPassword, args[2])
+	} else {
+		keyPair, walletFilePath, err = ethereum.CreateWalletFile(p.stack, outputDirectory, prefix, keyPassword)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -300,3 +331,32 @@ func (p *GethProvider) GetConnectorURL(org *types.Organization) string {
 func (p *GethProvider) GetConnectorExternalURL(org *types.Organization) string {
 	return fmt.Sprintf("http://127.0.0.1:%v", org.ExposedConnectorPort)
 }
+
+func (p *GethProvider) ListEventStreams(member *types.Organization) ([]*types.EventStream, error) {
+	return p.connector.ListEventStreams(member)
+}
+
+func (p *GethProvider) ResetEventStreamListener(member *types.Organization, streamID, listenerID, fromBlock string) error {
+	return p.connector.ResetEventStreamListener(member, streamID, listenerID, fromBlock)
+}
+
+// ExportChain stops the geth node, exports its blocks along with the genesis they were
+// created from, and then starts the node again
+func (p *GethProvider) ExportChain(outputDir string) error {
+	containerName := fmt.Sprintf("%s_geth", p.stack.ResourcePrefix())
+	gethVolumeName := fmt.Sprintf("%s_geth", p.stack.ResourcePrefix())
+	if err := docker.RunDockerCommand(p.ctx, p.stack.StackDir, "stop", containerName); err != nil {
+		return err
+	}
+	exportErr := docker.RunDockerCommand(p.ctx, p.stack.StackDir, "run", "--rm",
+		"-v", fmt.Sprintf("%s:/data", gethVolumeName),
+		"-v", fmt.Sprintf("%s:/export", outputDir),
+		gethImage, "--datadir", "/data", "export", path.Join("/export", ethereum.ChainDataBlocksFile))
+	if err := docker.RunDockerCommand(p.ctx, p.stack.StackDir, "start", containerName); err != nil {
+		return err
+	}
+	if exportErr != nil {
+		return exportErr
+	}
+	return copy.Copy(filepath.Join(p.stack.RuntimeDir, "blockchain", "genesis.json"), filepath.Join(outputDir, ethereum.ChainDataGenesisFile))
+}
@@ -0,0 +1,141 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quorum
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+type Genesis struct {
+	Config     *GenesisConfig    `json:"config"`
+	Nonce      string            `json:"nonce"`
+	Timestamp  string            `json:"timestamp"`
+	ExtraData  string            `json:"extraData"`
+	GasLimit   string            `json:"gasLimit"`
+	Difficulty string            `json:"difficulty"`
+	MixHash    string            `json:"mixHash"`
+	Coinbase   string            `json:"coinbase"`
+	Alloc      map[string]*Alloc `json:"alloc"`
+	Number     string            `json:"number"`
+	GasUsed    string            `json:"gasUsed"`
+	ParentHash string            `json:"parentHash"`
+}
+
+type GenesisConfig struct {
+	ChainId             int64       `json:"chainId"`
+	HomesteadBlock      int         `json:"homesteadBlock"`
+	Eip150Block         int         `json:"eip150Block"`
+	Eip155Block         int         `json:"eip155Block"`
+	Eip158Block         int         `json:"eip158Block"`
+	ByzantiumBlock      int         `json:"byzantiumBlock"`
+	ConstantinopleBlock int         `json:"constantinopleBlock"`
+	PetersburgBlock     int         `json:"petersburgBlock"`
+	IstanbulBlock       int         `json:"istanbulBlock"`
+	IsQuorum            bool        `json:"isQuorum"`
+	TxnSizeLimit        int         `json:"txnSizeLimit"`
+	QBFT                *QBFTConfig `json:"qbft"`
+}
+
+type QBFTConfig struct {
+	EpochLength           int `json:"epochlength"`
+	BlockPeriodSeconds    int `json:"blockperiodseconds"`
+	RequestTimeoutSeconds int `json:"requesttimeoutseconds"`
+	Policy                int `json:"policy"`
+	Ceil2Nby3Block        int `json:"ceil2Nby3Block"`
+}
+
+type Alloc struct {
+	Balance string `json:"balance"`
+}
+
+// The mix hash that marks a block as sealed by Istanbul BFT
+const istanbulMixHash = "0x63746963616c2062797a616e74696e65206661756c7420746f6c6572616e6365"
+
+// CreateGenesis returns a genesis for a QBFT chain sealed by the given validators
+func CreateGenesis(validators []string, blockPeriod int, chainID int64) *Genesis {
+	if blockPeriod == -1 {
+		blockPeriod = 5
+	}
+	alloc := make(map[string]*Alloc)
+	for _, address := range validators {
+		alloc[address] = &Alloc{
+			Balance: "0x200000000000000000000000000000000000000000000000000000000000000",
+		}
+	}
+	return &Genesis{
+		Config: &GenesisConfig{
+			ChainId:      chainID,
+			IsQuorum:     true,
+			TxnSizeLimit: 64,
+			QBFT: &QBFTConfig{
+				EpochLength:           30000,
+				BlockPeriodSeconds:    blockPeriod,
+				RequestTimeoutSeconds: 10,
+			},
+		},
+		Coinbase:   "0x0000000000000000000000000000000000000000",
+		Difficulty: "0x1",
+		ExtraData:  qbftExtraData(validators),
+		GasLimit:   "0xffffffff",
+		MixHash:    istanbulMixHash,
+		Nonce:      "0x0",
+		Timestamp:  "0x5c51a607",
+		Alloc:      alloc,
+		Number:     "0x0",
+		GasUsed:    "0x0",
+		ParentHash: "0x0000000000000000000000000000000000000000000000000000000000000000",
+	}
+}
+
+// qbftExtraData returns the RLP encoding of the QBFT extra data of the genesis block, which is
+// a list of 32 bytes of vanity, the validators, an empty vote, round zero and no committed seals
+func qbftExtraData(validators []string) string {
+	validatorList := []byte{}
+	for _, address := range validators {
+		b, _ := hex.DecodeString(strings.TrimPrefix(address, "0x"))
+		validatorList = append(validatorList, rlpBytes(b)...)
+	}
+	extraData := rlpBytes(make([]byte, 32))
+	extraData = append(extraData, rlpList(validatorList)...)
+	extraData = append(extraData, rlpList(nil)...)
+	extraData = append(extraData, rlpBytes(nil)...)
+	extraData = append(extraData, rlpList(nil)...)
+	return "0x" + hex.EncodeToString(rlpList(extraData))
+}
+
+func rlpBytes(b []byte) []byte {
+	if len(b) == 1 && b[0] < 0x80 {
+		return b
+	}
+	return append(rlpLength(len(b), 0x80), b...)
+}
+
+func rlpList(payload []byte) []byte {
+	return append(rlpLength(len(payload), 0xc0), payload...)
+}
+
+func rlpLength(length int, offset byte) []byte {
+	if length < 56 {
+		return []byte{offset + byte(length)}
+	}
+	lengthBytes := []byte{}
+	for l := length; l > 0; l >>= 8 {
+		lengthBytes = append([]byte{byte(l)}, lengthBytes...)
+	}
+	return append([]byte{offset + 55 + byte(len(lengthBytes))}, lengthBytes...)
+}
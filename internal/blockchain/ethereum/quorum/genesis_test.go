@@ -0,0 +1,44 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quorum
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateGenesisSingleValidator(t *testing.T) {
+	validator := "8b4cd5d4bb5b4e4b22d4aa44d2e6b5b4e7e9d0c1"
+	genesis := CreateGenesis([]string{validator}, -1, 2021)
+	assert.Equal(t, "0xf83aa0"+strings.Repeat("00", 32)+"d594"+validator+"c080c0", genesis.ExtraData)
+	assert.True(t, genesis.Config.IsQuorum)
+	assert.Equal(t, 5, genesis.Config.QBFT.BlockPeriodSeconds)
+	assert.Equal(t, int64(2021), genesis.Config.ChainId)
+	assert.Equal(t, istanbulMixHash, genesis.MixHash)
+	assert.Contains(t, genesis.Alloc, validator)
+}
+
+func TestCreateGenesisManyValidators(t *testing.T) {
+	// Three validators make a list longer than 55 bytes, which RLP encodes with a length prefix
+	validators := []string{strings.Repeat("11", 20), strings.Repeat("22", 20), strings.Repeat("33", 20)}
+	genesis := CreateGenesis(validators, 2, 2021)
+	validatorList := "f83f" + "94" + validators[0] + "94" + validators[1] + "94" + validators[2]
+	assert.Equal(t, "0xf865a0"+strings.Repeat("00", 32)+validatorList+"c080c0", genesis.ExtraData)
+	assert.Equal(t, 2, genesis.Config.QBFT.BlockPeriodSeconds)
+}
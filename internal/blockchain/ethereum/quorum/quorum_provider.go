@@ -0,0 +1,319 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quorum
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum"
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/connector"
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/connector/ethconnect"
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/connector/evmconnect"
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/ethsigner"
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/tessera"
+	"github.com/hyperledger/firefly-cli/internal/core"
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/random"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/otiai10/copy"
+)
+
+var quorumImage = "quorumengineering/quorum:22.7.6"
+
+const quorumRPCURL = "http://quorum:8545"
+
+type QuorumProvider struct {
+	ctx       context.Context
+	stack     *types.Stack
+	signer    *ethsigner.EthSignerProvider
+	connector connector.Connector
+}
+
+func NewQuorumProvider(ctx context.Context, stack *types.Stack) *QuorumProvider {
+	var connector connector.Connector
+	switch stack.BlockchainConnector {
+	case types.BlockchainConnectorEthconnect:
+		connector = ethconnect.NewEthconnect(ctx)
+	case types.BlockchainConnectorEvmconnect:
+		connector = evmconnect.NewEvmconnect(ctx)
+	}
+
+	return &QuorumProvider{
+		ctx:       ctx,
+		stack:     stack,
+		connector: connector,
+		signer:    ethsigner.NewEthSignerProvider(ctx, stack),
+	}
+}
+
+func (p *QuorumProvider) WriteConfig(options *types.InitOptions) error {
+	if err := p.signer.WriteConfig(options, quorumRPCURL); err != nil {
+		return err
+	}
+
+	initDir := p.stack.InitDir
+	for i, member := range p.stack.Members {
+		// Generate the connector config for each member
+		connectorConfigPath := filepath.Join(initDir, "config", fmt.Sprintf("%s_%v.yaml", p.connector.Name(), i))
+		extraConnectorConfig, err := core.ReadExtraConfig(options.ExtraConnectorConfigPath, p.stack, member)
+		if err != nil {
+			return err
+		}
+		if err := p.connector.GenerateConfig(member, "ethsigner").WriteConfig(connectorConfigPath, extraConnectorConfig); err != nil {
+			return nil
+		}
+	}
+
+	// Generate the key of the single QBFT validator, which GoQuorum expects without the 0x prefix
+	nodeAddress, nodeKey := ethereum.GenerateAddressAndPrivateKey(random.Reader(p.stack, "blockchain/nodeKey"))
+	if err := ioutil.WriteFile(filepath.Join(initDir, "blockchain", ethereum.ChainDataNodeKeyFile), []byte(strings.TrimPrefix(nodeKey, "0x")), 0755); err != nil {
+		return err
+	}
+	genesis := CreateGenesis([]string{nodeAddress[2:]}, options.BlockPeriod, p.stack.ChainID())
+	if err := ethereum.WriteStackGenesis(genesis, options, filepath.Join(initDir, "blockchain")); err != nil {
+		return err
+	}
+
+	if p.stack.PrivateTransactions {
+		if err := tessera.WriteConfig(p.stack, filepath.Join(initDir, "blockchain"), ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *QuorumProvider) FirstTimeSetup() error {
+	quorumVolumeName := fmt.Sprintf("%s_quorum", p.stack.ResourcePrefix())
+	blockchainDir := filepath.Join(p.stack.RuntimeDir, "blockchain")
+	contractsDir := filepath.Join(p.stack.RuntimeDir, "contracts")
+
+	if err := p.signer.FirstTimeSetup(); err != nil {
+		return err
+	}
+
+	if err := docker.CreateVolume(p.ctx, quorumVolumeName); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(contractsDir, 0755); err != nil {
+		return err
+	}
+
+	for i := range p.stack.Members {
+		// Copy connector config to each member's volume
+		connectorConfigPath := filepath.Join(p.stack.StackDir, "runtime", "config", fmt.Sprintf("%s_%v.yaml", p.connector.Name(), i))
+		connectorConfigVolumeName := fmt.Sprintf("%s_%s_config_%v", p.stack.ResourcePrefix(), p.connector.Name(), i)
+		docker.CopyFileToVolume(p.ctx, connectorConfigVolumeName, connectorConfigPath, "config.yaml")
+	}
+
+	// Copy the genesis block information and the validator key
+	for _, f := range []string{"genesis.json", ethereum.ChainDataNodeKeyFile} {
+		if err := docker.CopyFileToVolume(p.ctx, quorumVolumeName, path.Join(blockchainDir, f), f); err != nil {
+			return err
+		}
+	}
+
+	// Initialize the genesis block
+	if err := docker.RunDockerCommand(p.ctx, p.stack.StackDir, "run", "--rm", "-v", fmt.Sprintf("%s:/data", quorumVolumeName), quorumImage, "--datadir", "/data", "init", "/data/genesis.json"); err != nil {
+		return err
+	}
+
+	if p.stack.PrivateTransactions {
+		if err := tessera.CopyConfigToVolumes(p.ctx, p.stack, blockchainDir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *QuorumProvider) PreStart() error {
+	return nil
+}
+
+func (p *QuorumProvider) PostStart(firstTimeSetup bool) error {
+	return nil
+}
+
+func (p *QuorumProvider) DeployFireFlyContract() (*types.ContractDeploymentResult, error) {
+	contract, err := ethereum.ReadFireFlyContract(p.ctx, p.stack)
+	if err != nil {
+		return nil, err
+	}
+	return p.connector.DeployContract(contract, "FireFly", p.stack.Members[0], nil)
+}
+
+func (p *QuorumProvider) GetDockerServiceDefinitions() []*docker.ServiceDefinition {
+	quorumCommand := fmt.Sprintf(`--datadir /data --nodekey /data/%s --networkid %d --syncmode full --nodiscover --port 30311 --mine --miner.threads 1 --miner.gasprice 0 --emitcheckpoints --http --http.addr "0.0.0.0" --http.port 8545 --http.corsdomain "*" --http.vhosts "*" --http.api admin,eth,net,web3,txpool,debug,istanbul,quorumExtension --verbosity 3`, ethereum.ChainDataNodeKeyFile, p.stack.ChainID())
+	environment := map[string]interface{}{}
+	var dependsOn map[string]map[string]string
+	if p.stack.PrivateTransactions {
+		// The node is paired with the Tessera node of the first member
+		tesseraService := tessera.ServiceName(p.stack.Members[0])
+		quorumCommand += fmt.Sprintf(" --ptm.url http://%s:%d --ptm.timeout 5", tesseraService, tessera.Q2TPort)
+		dependsOn = map[string]map[string]string{tesseraService: {"condition": "service_healthy"}}
+	} else {
+		// GoQuorum refuses to start without a private transaction manager unless told to ignore it
+		environment["PRIVATE_CONFIG"] = "ignore"
+	}
+
+	serviceDefinitions := make([]*docker.ServiceDefinition, 2)
+	serviceDefinitions[0] = &docker.ServiceDefinition{
+		ServiceName: "quorum",
+		Service: &docker.Service{
+			Image:         quorumImage,
+			ContainerName: fmt.Sprintf("%s_quorum", p.stack.ResourcePrefix()),
+			Command:       quorumCommand,
+			Environment:   environment,
+			Volumes:       []string{"quorum:/data"},
+			Logging:       docker.StandardLogOptions,
+			DependsOn:     dependsOn,
+			HealthCheck: &docker.HealthCheck{
+				Test:     []string{"CMD", "wget", "-q", "-O", "-", "--header", "Content-Type: application/json", "--post-data", `{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":1}`, "http://localhost:8545"},
+				Interval: "5s",
+				Timeout:  "3s",
+				Retries:  24,
+			},
+		},
+		VolumeNames: []string{"quorum"},
+	}
+	serviceDefinitions[1] = p.signer.GetDockerServiceDefinition(quorumRPCURL)
+	serviceDefinitions = append(serviceDefinitions, p.connector.GetServiceDefinitions(p.stack, map[string]string{"ethsigner": "service_healthy"})...)
+	if p.stack.PrivateTransactions {
+		serviceDefinitions = append(serviceDefinitions, tessera.GetServiceDefinitions(p.stack)...)
+	}
+	return serviceDefinitions
+}
+
+func (p *QuorumProvider) GetBlockchainPluginConfig(stack *types.Stack, m *types.Organization) (blockchainConfig *types.BlockchainConfig) {
+	var connectorURL string
+	if m.External {
+		connectorURL = p.GetConnectorExternalURL(m)
+	} else {
+		connectorURL = p.GetConnectorURL(m)
+	}
+
+	blockchainConfig = &types.BlockchainConfig{
+		Type: "ethereum",
+		Ethereum: &types.EthereumConfig{
+			Ethconnect: &types.EthconnectConfig{
+				URL:   connectorURL,
+				Topic: m.ID,
+			},
+		},
+	}
+	return
+}
+
+func (p *QuorumProvider) GetOrgConfig(stack *types.Stack, m *types.Organization) (orgConfig *types.OrgConfig) {
+	account := m.Account.(*ethereum.Account)
+	orgConfig = &types.OrgConfig{
+		Name: m.OrgName,
+		Key:  account.Address,
+	}
+	return
+}
+
+func (p *QuorumProvider) Reset() error {
+	return nil
+}
+
+func (p *QuorumProvider) GetContracts(filename string, extraArgs []string) ([]string, error) {
+	contracts, err := ethereum.ReadContractJSON(filename)
+	if err != nil {
+		return []string{}, err
+	}
+	contractNames := make([]string, len(contracts.Contracts))
+	i := 0
+	for contractName := range contracts.Contracts {
+		contractNames[i] = contractName
+		i++
+	}
+	return contractNames, err
+}
+
+func (p *QuorumProvider) DeployContract(filename, contractName, instanceName string, member *types.Organization, extraArgs []string) (*types.ContractDeploymentResult, error) {
+	contracts, err := ethereum.ReadContractJSON(filename)
+	if err != nil {
+		return nil, err
+	}
+	return p.connector.DeployContract(contracts.Contracts[contractName], instanceName, member, extraArgs)
+}
+
+func (p *QuorumProvider) CreateAccount(args []string) (interface{}, error) {
+	return p.signer.CreateAccount(args)
+}
+
+func (p *QuorumProvider) ParseAccount(account interface{}) interface{} {
+	accountMap := account.(map[string]interface{})
+	return &ethereum.Account{
+		Address:    accountMap["address"].(string),
+		PrivateKey: accountMap["privateKey"].(string),
+	}
+}
+
+func (p *QuorumProvider) GetConnectorName() string {
+	return p.connector.Name()
+}
+
+func (p *QuorumProvider) GetConnectorURL(org *types.Organization) string {
+	return fmt.Sprintf("http://%s_%s:%v", p.connector.Name(), org.ID, p.connector.Port())
+}
+
+func (p *QuorumProvider) GetConnectorExternalURL(org *types.Organization) string {
+	return fmt.Sprintf("http://127.0.0.1:%v", org.ExposedConnectorPort)
+}
+
+func (p *QuorumProvider) ListEventStreams(member *types.Organization) ([]*types.EventStream, error) {
+	return p.connector.ListEventStreams(member)
+}
+
+func (p *QuorumProvider) ResetEventStreamListener(member *types.Organization, streamID, listenerID, fromBlock string) error {
+	return p.connector.ResetEventStreamListener(member, streamID, listenerID, fromBlock)
+}
+
+// ExportChain stops the quorum node, exports its blocks along with the genesis they were
+// created from and the key of the validator that seals them, and then starts the node again
+func (p *QuorumProvider) ExportChain(outputDir string) error {
+	containerName := fmt.Sprintf("%s_quorum", p.stack.ResourcePrefix())
+	quorumVolumeName := fmt.Sprintf("%s_quorum", p.stack.ResourcePrefix())
+	blockchainDir := filepath.Join(p.stack.RuntimeDir, "blockchain")
+	for _, f := range []string{ethereum.ChainDataGenesisFile, ethereum.ChainDataNodeKeyFile} {
+		if err := copy.Copy(filepath.Join(blockchainDir, f), filepath.Join(outputDir, f)); err != nil {
+			return err
+		}
+	}
+
+	if err := docker.RunDockerCommand(p.ctx, p.stack.StackDir, "stop", containerName); err != nil {
+		return err
+	}
+	exportErr := docker.RunDockerCommand(p.ctx, p.stack.StackDir, "run", "--rm",
+		"-v", fmt.Sprintf("%s:/data", quorumVolumeName),
+		"-v", fmt.Sprintf("%s:/export", outputDir),
+		quorumImage, "--datadir", "/data", "export", path.Join("/export", ethereum.ChainDataBlocksFile))
+	if err := docker.RunDockerCommand(p.ctx, p.stack.StackDir, "start", containerName); err != nil {
+		return err
+	}
+	return exportErr
+}
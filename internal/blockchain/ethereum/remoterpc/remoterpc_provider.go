@@ -26,7 +26,8 @@ import (
 	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/connector/ethconnect"
 	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/connector/evmconnect"
 	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/ethsigner"
-	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/rpcproxy"
+	"github.com/hyperledger/firefly-cli/internal/core"
 	"github.com/hyperledger/firefly-cli/internal/docker"
 	"github.com/hyperledger/firefly-cli/pkg/types"
 )
@@ -56,18 +57,36 @@ func NewRemoteRPCProvider(ctx context.Context, stack *types.Stack) *RemoteRPCPro
 }
 
 func (p *RemoteRPCProvider) WriteConfig(options *types.InitOptions) error {
-	initDir := filepath.Join(constants.StacksDir, p.stack.Name, "init")
+	initDir := p.stack.InitDir
 	for i, member := range p.stack.Members {
 
 		// Generate the connector config for each member
 		connectorConfigPath := filepath.Join(initDir, "config", fmt.Sprintf("%s_%v.yaml", p.connector.Name(), i))
-		if err := p.connector.GenerateConfig(member, "ethsigner").WriteConfig(connectorConfigPath, options.ExtraConnectorConfigPath); err != nil {
+		extraConnectorConfig, err := core.ReadExtraConfig(options.ExtraConnectorConfigPath, p.stack, member)
+		if err != nil {
+			return err
+		}
+		if err := p.connector.GenerateConfig(member, "ethsigner").WriteConfig(connectorConfigPath, extraConnectorConfig); err != nil {
 			return err
 		}
 
 	}
 
-	return p.signer.WriteConfig(options, options.RemoteNodeURL)
+	if p.stack.RPCProxy != nil {
+		if err := rpcproxy.WriteConfig(p.stack, filepath.Join(initDir, "config", rpcproxy.ServiceName)); err != nil {
+			return err
+		}
+	}
+
+	return p.signer.WriteConfig(options, p.rpcURL())
+}
+
+// rpcURL returns the URL the signer sends requests to, which is the proxy if the stack has one
+func (p *RemoteRPCProvider) rpcURL() string {
+	if p.stack.RPCProxy != nil {
+		return rpcproxy.URL
+	}
+	return p.stack.RemoteNodeURL
 }
 
 func (p *RemoteRPCProvider) FirstTimeSetup() error {
@@ -78,10 +97,14 @@ func (p *RemoteRPCProvider) FirstTimeSetup() error {
 	for i := range p.stack.Members {
 		// Copy connector config to each member's volume
 		connectorConfigPath := filepath.Join(p.stack.StackDir, "runtime", "config", fmt.Sprintf("%s_%v.yaml", p.connector.Name(), i))
-		connectorConfigVolumeName := fmt.Sprintf("%s_%s_config_%v", p.stack.Name, p.connector.Name(), i)
+		connectorConfigVolumeName := fmt.Sprintf("%s_%s_config_%v", p.stack.ResourcePrefix(), p.connector.Name(), i)
 		docker.CopyFileToVolume(p.ctx, connectorConfigVolumeName, connectorConfigPath, "config.yaml")
 	}
 
+	if p.stack.RPCProxy != nil {
+		return rpcproxy.CopyConfigToVolume(p.ctx, p.stack, filepath.Join(p.stack.RuntimeDir, "config", rpcproxy.ServiceName))
+	}
+
 	return nil
 }
 
@@ -98,8 +121,11 @@ func (p *RemoteRPCProvider) DeployFireFlyContract() (*types.ContractDeploymentRe
 }
 
 func (p *RemoteRPCProvider) GetDockerServiceDefinitions() []*docker.ServiceDefinition {
-	defs := []*docker.ServiceDefinition{
-		p.signer.GetDockerServiceDefinition(p.stack.RemoteNodeURL),
+	signer := p.signer.GetDockerServiceDefinition(p.rpcURL())
+	defs := []*docker.ServiceDefinition{signer}
+	if p.stack.RPCProxy != nil {
+		signer.Service.DependsOn = map[string]map[string]string{rpcproxy.ServiceName: {"condition": "service_healthy"}}
+		defs = append(defs, rpcproxy.GetServiceDefinition(p.stack))
 	}
 	defs = append(defs, p.connector.GetServiceDefinitions(p.stack, map[string]string{"ethsigner": "service_healthy"})...)
 	return defs
@@ -183,3 +209,15 @@ func (p *RemoteRPCProvider) ParseAccount(account interface{}) interface{} {
 		PrivateKey: accountMap["privateKey"].(string),
 	}
 }
+
+func (p *RemoteRPCProvider) ListEventStreams(member *types.Organization) ([]*types.EventStream, error) {
+	return p.connector.ListEventStreams(member)
+}
+
+func (p *RemoteRPCProvider) ResetEventStreamListener(member *types.Organization, streamID, listenerID, fromBlock string) error {
+	return p.connector.ResetEventStreamListener(member, streamID, listenerID, fromBlock)
+}
+
+func (p *RemoteRPCProvider) ExportChain(outputDir string) error {
+	return fmt.Errorf("chain export is not supported for a remote node")
+}
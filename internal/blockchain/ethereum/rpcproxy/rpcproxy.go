@@ -0,0 +1,167 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcproxy
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+//go:embed nginx.conf
+var nginxConfTemplate string
+
+//go:embed rpcproxy.lua
+var proxyLua string
+
+const (
+	ServiceName = "rpcproxy"
+	port        = 8545
+)
+
+// URL is where the services of the stack reach the proxy
+var URL = fmt.Sprintf("http://%s:%d", ServiceName, port)
+
+// Validate checks the settings of the proxy, and the remote node it sends requests to
+func Validate(remoteNodeURL string, config *types.RPCProxyConfig) error {
+	u, err := url.Parse(remoteNodeURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("--rpc-proxy needs an http or https --remote-node-url")
+	}
+	if config.RequestsPerSecond < 0 || config.Budget < 0 {
+		return fmt.Errorf("the rate limit and request budget of the rpc proxy cannot be negative")
+	}
+	if config.TimeoutPercent < 0 || config.TimeoutPercent > 100 {
+		return fmt.Errorf("the rpc proxy timeout percentage must be between 0 and 100")
+	}
+	for flag, d := range map[string]string{"--rpc-proxy-budget-window": config.BudgetWindow, "--rpc-proxy-latency": config.Latency, "--rpc-proxy-timeout": config.Timeout} {
+		if _, err := parseDuration(d); err != nil {
+			return fmt.Errorf("invalid %s '%s': %s", flag, d, err)
+		}
+	}
+	return nil
+}
+
+func parseDuration(d string) (time.Duration, error) {
+	if d == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(d)
+	if err == nil && duration < 0 {
+		return 0, fmt.Errorf("duration cannot be negative")
+	}
+	return duration, err
+}
+
+func seconds(d string) float64 {
+	duration, _ := parseDuration(d)
+	return duration.Seconds()
+}
+
+// upstreamURL returns the URL nginx proxies to. It always has a path, because nginx would otherwise
+// pass on the path of the internal location it is proxying from.
+func upstreamURL(remoteNodeURL string) string {
+	u, _ := url.Parse(remoteNodeURL)
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
+
+// GenerateNginxConfig returns the nginx config of the proxy for the stack
+func GenerateNginxConfig(stack *types.Stack) ([]byte, error) {
+	config := stack.RPCProxy
+	t, err := template.New("nginx.conf").Parse(nginxConfTemplate)
+	if err != nil {
+		return nil, err
+	}
+	budgetWindow := config.BudgetWindow
+	if budgetWindow == "" {
+		budgetWindow = "24h"
+	}
+	var b bytes.Buffer
+	err = t.Execute(&b, map[string]interface{}{
+		"Port":              port,
+		"UpstreamURL":       upstreamURL(stack.RemoteNodeURL),
+		"Cache":             config.Cache,
+		"RequestsPerSecond": config.RequestsPerSecond,
+		"Budget":            config.Budget,
+		"BudgetWindow":      seconds(budgetWindow),
+		"BudgetDescription": budgetWindow,
+		"Latency":           seconds(config.Latency),
+		"TimeoutRate":       config.TimeoutPercent / 100,
+		"Timeout":           seconds(config.Timeout),
+	})
+	return b.Bytes(), err
+}
+
+// WriteConfig writes the nginx config and the Lua code of the proxy to a directory
+func WriteConfig(stack *types.Stack, configDir string) error {
+	nginxConf, err := GenerateNginxConfig(stack)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(configDir, "nginx.conf"), nginxConf, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(configDir, "rpcproxy.lua"), []byte(proxyLua), 0755)
+}
+
+// CopyConfigToVolume copies the files written by WriteConfig to the config volume of the proxy
+func CopyConfigToVolume(ctx context.Context, stack *types.Stack, configDir string) error {
+	volumeName := fmt.Sprintf("%s_%s_config", stack.ResourcePrefix(), ServiceName)
+	for _, f := range []string{"nginx.conf", "rpcproxy.lua"} {
+		if err := docker.CopyFileToVolume(ctx, volumeName, filepath.Join(configDir, f), f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func GetServiceDefinition(stack *types.Stack) *docker.ServiceDefinition {
+	return &docker.ServiceDefinition{
+		ServiceName: ServiceName,
+		Service: &docker.Service{
+			Image:         constants.RPCProxyImageName,
+			ContainerName: fmt.Sprintf("%s_%s", stack.ResourcePrefix(), ServiceName),
+			Command:       `openresty -c /etc/rpcproxy/nginx.conf -g "daemon off;"`,
+			Volumes:       []string{fmt.Sprintf("%s_config:/etc/rpcproxy", ServiceName)},
+			Logging:       docker.StandardLogOptions,
+			HealthCheck: &docker.HealthCheck{
+				Test:     []string{"CMD", "wget", "-q", "-O", "-", fmt.Sprintf("http://localhost:%d", port)},
+				Interval: "5s",
+				Timeout:  "3s",
+				Retries:  12,
+			},
+		},
+		VolumeNames: []string{fmt.Sprintf("%s_config", ServiceName)},
+	}
+}
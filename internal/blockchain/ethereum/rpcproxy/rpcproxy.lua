@@ -0,0 +1,166 @@
+-- Sits between the signer and a remote JSON-RPC node. Results that cannot change are answered
+-- from a cache, and the requests that do go upstream are throttled, counted against a budget,
+-- delayed and sometimes held until they time out, as configured with "ff init --rpc-proxy".
+
+local cjson = require "cjson.safe"
+local limit_req = require "resty.limit.req"
+
+local _M = {}
+
+local config = {}
+local cache = ngx.shared.rpcproxy_cache
+local limits = ngx.shared.rpcproxy_limits
+
+-- Methods whose results are fixed once they refer to a mined block. The value is the position of
+-- the block parameter, which has to be a block number rather than a tag like "latest".
+local cacheable = {
+    eth_chainId = 0,
+    net_version = 0,
+    eth_getBlockByHash = 0,
+    eth_getTransactionByHash = 0,
+    eth_getTransactionReceipt = 0,
+    eth_getLogs = 0,
+    eth_getBlockByNumber = 1,
+    eth_getBalance = 2,
+    eth_getCode = 2,
+    eth_call = 2,
+    eth_getStorageAt = 3,
+}
+
+function _M.configure(c)
+    config = c
+end
+
+local function is_block_number(v)
+    return type(v) == "string" and v:match("^0x%x+$") ~= nil
+end
+
+local function cache_key(req)
+    if not config.cache or type(req) ~= "table" or type(req.method) ~= "string" then
+        return nil
+    end
+    local position = cacheable[req.method]
+    if position == nil then
+        return nil
+    end
+    local params = req.params or {}
+    if type(params) ~= "table" then
+        return nil
+    end
+    if position > 0 and not is_block_number(params[position]) then
+        return nil
+    end
+    if req.method == "eth_getLogs" then
+        local filter = params[1]
+        if type(filter) ~= "table" or not (filter.blockHash or (is_block_number(filter.fromBlock) and is_block_number(filter.toBlock))) then
+            return nil
+        end
+    end
+    return req.method .. ":" .. cjson.encode(params)
+end
+
+local function is_final(method, result)
+    if result == nil or result == cjson.null then
+        return false
+    end
+    -- A transaction that is still pending has no block yet
+    if method == "eth_getTransactionByHash" and result.blockHash == cjson.null then
+        return false
+    end
+    return true
+end
+
+local function respond(status, body)
+    ngx.status = status
+    ngx.header["Content-Type"] = "application/json"
+    ngx.print(body)
+    return ngx.exit(ngx.HTTP_OK)
+end
+
+local function reject(status, req, code, message)
+    ngx.header["X-Rpc-Proxy"] = "rejected"
+    local id = cjson.null
+    if type(req) == "table" and req.id ~= nil then
+        id = req.id
+    end
+    return respond(status, cjson.encode({ jsonrpc = "2.0", id = id, error = { code = code, message = message } }))
+end
+
+local function read_body()
+    ngx.req.read_body()
+    local body = ngx.req.get_body_data()
+    if body == nil then
+        local filename = ngx.req.get_body_file()
+        if filename then
+            local f = io.open(filename, "rb")
+            body = f:read("*a")
+            f:close()
+        end
+    end
+    return body
+end
+
+function _M.handle()
+    if ngx.req.get_method() ~= "POST" then
+        return respond(200, '{"status":"ok"}')
+    end
+
+    local body = read_body() or ""
+    local req = cjson.decode(body)
+
+    local key = cache_key(req)
+    if key then
+        local result = cache:get(key)
+        if result then
+            ngx.header["X-Rpc-Proxy"] = "hit"
+            return respond(200, '{"jsonrpc":"2.0","id":' .. cjson.encode(req.id) .. ',"result":' .. result .. '}')
+        end
+    end
+
+    -- A batch costs one request for each call in it
+    local cost = 1
+    if type(req) == "table" and req[1] ~= nil then
+        cost = #req
+    end
+
+    if config.requests_per_second > 0 then
+        local limiter = limit_req.new("rpcproxy_limits", config.requests_per_second, config.requests_per_second)
+        local delay, err = limiter:incoming("rate", true)
+        if not delay then
+            if err == "rejected" then
+                return reject(429, req, -32005, string.format("rate limit of %d requests per second exceeded", config.requests_per_second))
+            end
+            ngx.log(ngx.ERR, "rate limiter failed: ", err)
+        elseif delay > 0 then
+            ngx.sleep(delay)
+        end
+    end
+
+    if config.budget > 0 then
+        local used = limits:incr("budget", cost, 0, config.budget_window)
+        if used and used > config.budget then
+            return reject(429, req, -32005, string.format("request budget of %d per %s exhausted", config.budget, config.budget_description))
+        end
+    end
+
+    if config.timeout_rate > 0 and math.random() < config.timeout_rate then
+        ngx.sleep(config.timeout)
+        return reject(504, req, -32603, "request timed out")
+    end
+
+    if config.latency > 0 then
+        ngx.sleep(config.latency)
+    end
+
+    local res = ngx.location.capture("/upstream", { method = ngx.HTTP_POST, body = body })
+    if key and res.status == 200 then
+        local response = cjson.decode(res.body)
+        if type(response) == "table" and response.error == nil and is_final(req.method, response.result) then
+            cache:set(key, cjson.encode(response.result))
+        end
+    end
+    ngx.header["X-Rpc-Proxy"] = "miss"
+    return respond(res.status, res.body)
+end
+
+return _M
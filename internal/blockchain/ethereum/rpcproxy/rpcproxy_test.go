@@ -0,0 +1,80 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcproxy
+
+import (
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	config := &types.RPCProxyConfig{BudgetWindow: "24h", Latency: "250ms", Timeout: "30s", TimeoutPercent: 5}
+	assert.NoError(t, Validate("https://mainnet.example.com/v3/key", config))
+	assert.Regexp(t, "needs an http or https --remote-node-url", Validate("", config))
+	assert.Regexp(t, "needs an http or https --remote-node-url", Validate("ws://node:8546", config))
+
+	config.Latency = "soon"
+	assert.Regexp(t, "invalid --rpc-proxy-latency 'soon'", Validate("http://node:8545", config))
+	config.Latency = "-1s"
+	assert.Regexp(t, "cannot be negative", Validate("http://node:8545", config))
+	config.Latency = ""
+	config.TimeoutPercent = 101
+	assert.Regexp(t, "between 0 and 100", Validate("http://node:8545", config))
+	config.TimeoutPercent = 0
+	config.Budget = -1
+	assert.Regexp(t, "cannot be negative", Validate("http://node:8545", config))
+}
+
+func TestGenerateNginxConfig(t *testing.T) {
+	stack := &types.Stack{
+		RemoteNodeURL: "https://mainnet.example.com",
+		RPCProxy: &types.RPCProxyConfig{
+			Cache:             true,
+			RequestsPerSecond: 10,
+			Budget:            100000,
+			BudgetWindow:      "1h",
+			Latency:           "250ms",
+			TimeoutPercent:    5,
+			Timeout:           "30s",
+		},
+	}
+	b, err := GenerateNginxConfig(stack)
+	assert.NoError(t, err)
+	conf := string(b)
+	// The upstream needs a path, or nginx passes on the path of the internal location
+	assert.Contains(t, conf, "proxy_pass https://mainnet.example.com/;")
+	assert.Contains(t, conf, "cache = true,")
+	assert.Contains(t, conf, "requests_per_second = 10,")
+	assert.Contains(t, conf, "budget = 100000,")
+	assert.Contains(t, conf, "budget_window = 3600,")
+	assert.Contains(t, conf, `budget_description = "1h",`)
+	assert.Contains(t, conf, "latency = 0.25,")
+	assert.Contains(t, conf, "timeout_rate = 0.05,")
+	assert.Contains(t, conf, "timeout = 30,")
+	assert.Contains(t, conf, "listen 8545;")
+
+	stack.RemoteNodeURL = "https://mainnet.example.com/v3/key"
+	stack.RPCProxy = &types.RPCProxyConfig{}
+	b, err = GenerateNginxConfig(stack)
+	assert.NoError(t, err)
+	conf = string(b)
+	assert.Contains(t, conf, "proxy_pass https://mainnet.example.com/v3/key;")
+	assert.Contains(t, conf, "cache = false,")
+	assert.Contains(t, conf, "budget_window = 86400,")
+}
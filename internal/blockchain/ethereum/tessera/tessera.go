@@ -0,0 +1,194 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tessera
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/random"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"golang.org/x/crypto/nacl/box"
+)
+
+var Image = "quorumengineering/tessera:22.1.7"
+
+const (
+	P2PPort = 9000
+	Q2TPort = 9101
+)
+
+type TesseraConfig struct {
+	Mode         string                 `json:"mode,omitempty"`
+	UseWhiteList bool                   `json:"useWhiteList"`
+	JDBC         *TesseraJDBCConfig     `json:"jdbc"`
+	ServerConfig []*TesseraServerConfig `json:"serverConfigs"`
+	Peers        []*TesseraPeer         `json:"peer"`
+	Keys         *TesseraKeys           `json:"keys"`
+	AlwaysSendTo []string               `json:"alwaysSendTo"`
+}
+
+type TesseraJDBCConfig struct {
+	Username         string `json:"username"`
+	Password         string `json:"password"`
+	URL              string `json:"url"`
+	AutoCreateTables bool   `json:"autoCreateTables"`
+}
+
+type TesseraServerConfig struct {
+	App               string            `json:"app"`
+	Enabled           bool              `json:"enabled"`
+	ServerAddress     string            `json:"serverAddress"`
+	CommunicationType string            `json:"communicationType"`
+	SSLConfig         map[string]string `json:"sslConfig,omitempty"`
+}
+
+type TesseraPeer struct {
+	URL string `json:"url"`
+}
+
+type TesseraKeys struct {
+	Passwords []string          `json:"passwords"`
+	KeyData   []*TesseraKeyPath `json:"keyData"`
+}
+
+type TesseraKeyPath struct {
+	PrivateKeyPath string `json:"privateKeyPath"`
+	PublicKeyPath  string `json:"publicKeyPath"`
+}
+
+// ServiceName returns the name of the Tessera service of a member
+func ServiceName(member *types.Organization) string {
+	return fmt.Sprintf("tessera_%s", member.ID)
+}
+
+// WriteConfig generates a key pair and config for the Tessera node of each member. The nodes all
+// peer with each other, and run in the given mode, which is "orion" for the flexible privacy groups
+// of Besu and empty for the default mode GoQuorum uses.
+func WriteConfig(stack *types.Stack, blockchainDir, mode string) error {
+	for _, member := range stack.Members {
+		publicKey, privateKey, err := box.GenerateKey(random.Reader(stack, fmt.Sprintf("members/%s/tessera-key", member.ID)))
+		if err != nil {
+			return err
+		}
+		member.TesseraPublicKey = base64.StdEncoding.EncodeToString(publicKey[:])
+
+		tesseraDir := filepath.Join(blockchainDir, ServiceName(member))
+		if err := os.MkdirAll(tesseraDir, 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(tesseraDir, "tm.pub"), []byte(member.TesseraPublicKey), 0755); err != nil {
+			return err
+		}
+		keyFile, _ := json.Marshal(map[string]interface{}{
+			"type": "unlocked",
+			"data": map[string]string{"bytes": base64.StdEncoding.EncodeToString(privateKey[:])},
+		})
+		if err := ioutil.WriteFile(filepath.Join(tesseraDir, "tm.key"), keyFile, 0755); err != nil {
+			return err
+		}
+
+		serviceName := ServiceName(member)
+		peers := []*TesseraPeer{}
+		for _, peer := range stack.Members {
+			if peer.ID != member.ID {
+				peers = append(peers, &TesseraPeer{URL: fmt.Sprintf("http://%s:%d", ServiceName(peer), P2PPort)})
+			}
+		}
+		config := &TesseraConfig{
+			Mode: mode,
+			JDBC: &TesseraJDBCConfig{
+				Username:         "sa",
+				URL:              "jdbc:h2:/data/db;MODE=Oracle;TRACE_LEVEL_SYSTEM_OUT=0",
+				AutoCreateTables: true,
+			},
+			ServerConfig: []*TesseraServerConfig{
+				{
+					App:               "Q2T",
+					Enabled:           true,
+					ServerAddress:     fmt.Sprintf("http://%s:%d", serviceName, Q2TPort),
+					CommunicationType: "REST",
+					SSLConfig:         map[string]string{"tls": "OFF"},
+				},
+				{
+					App:               "P2P",
+					Enabled:           true,
+					ServerAddress:     fmt.Sprintf("http://%s:%d", serviceName, P2PPort),
+					CommunicationType: "REST",
+					SSLConfig:         map[string]string{"tls": "OFF"},
+				},
+			},
+			Peers: peers,
+			Keys: &TesseraKeys{
+				Passwords: []string{},
+				KeyData:   []*TesseraKeyPath{{PrivateKeyPath: "/data/tm.key", PublicKeyPath: "/data/tm.pub"}},
+			},
+			AlwaysSendTo: []string{},
+		}
+		configBytes, _ := json.MarshalIndent(config, "", " ")
+		if err := ioutil.WriteFile(filepath.Join(tesseraDir, "config.json"), configBytes, 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CopyConfigToVolumes copies the config and keys of each member's Tessera node into its volume
+func CopyConfigToVolumes(ctx context.Context, stack *types.Stack, blockchainDir string) error {
+	for _, member := range stack.Members {
+		tesseraDir := filepath.Join(blockchainDir, ServiceName(member))
+		volumeName := fmt.Sprintf("%s_%s", stack.ResourcePrefix(), ServiceName(member))
+		for _, f := range []string{"config.json", "tm.pub", "tm.key"} {
+			if err := docker.CopyFileToVolume(ctx, volumeName, filepath.Join(tesseraDir, f), f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetServiceDefinitions returns a Tessera service for each member of the stack
+func GetServiceDefinitions(stack *types.Stack) []*docker.ServiceDefinition {
+	serviceDefinitions := []*docker.ServiceDefinition{}
+	for _, member := range stack.Members {
+		serviceName := ServiceName(member)
+		serviceDefinitions = append(serviceDefinitions, &docker.ServiceDefinition{
+			ServiceName: serviceName,
+			Service: &docker.Service{
+				Image:         Image,
+				ContainerName: fmt.Sprintf("%s_%s", stack.ResourcePrefix(), serviceName),
+				Command:       "-configfile /data/config.json",
+				Volumes:       []string{fmt.Sprintf("%s:/data", serviceName)},
+				Logging:       docker.StandardLogOptions,
+				HealthCheck: &docker.HealthCheck{
+					Test:     []string{"CMD", "wget", "-q", "-O", "-", fmt.Sprintf("http://localhost:%d/upcheck", P2PPort)},
+					Interval: "5s",
+					Timeout:  "3s",
+					Retries:  24,
+				},
+			},
+			VolumeNames: []string{serviceName},
+		})
+	}
+	return serviceDefinitions
+}
@@ -58,39 +58,43 @@ type CryptogenConfig struct {
 	PeerOrgs    []*Org `yaml:"PeerOrgs,omitempty"`
 }
 
-func WriteCryptogenConfig(memberCount int, path string) error {
+func WriteCryptogenConfig(orgs []*fabricOrg, ordererHosts []string, path string) error {
+	ordererSpecs := make([]*Spec, len(ordererHosts))
+	for i, host := range ordererHosts {
+		ordererSpecs[i] = &Spec{Hostname: host}
+	}
+	peerOrgs := make([]*Org, len(orgs))
+	for i, org := range orgs {
+		peerOrgs[i] = &Org{
+			Name:          org.Name,
+			Domain:        org.Domain,
+			EnableNodeOUs: true,
+			CA: &CA{
+				Hostname:           org.CAHost,
+				Country:            "US",
+				Province:           "North Carolina",
+				Locality:           "Raleigh",
+				OrganizationalUnit: "Hyperledger FireFly",
+			},
+			Template: &Template{
+				Count:    1,
+				Hostname: org.PeerHost,
+			},
+			Users: &Users{
+				Count: org.Users,
+			},
+		}
+	}
 	cryptogenConfig := &CryptogenConfig{
 		OrdererOrgs: []*Org{
 			{
 				Name:          "Orderer",
 				Domain:        "example.com",
 				EnableNodeOUs: true,
-				Specs: []*Spec{
-					{Hostname: "fabric_orderer"},
-				},
-			},
-		},
-		PeerOrgs: []*Org{
-			{
-				Name:          "Org1",
-				Domain:        "org1.example.com",
-				EnableNodeOUs: true,
-				CA: &CA{
-					Hostname:           "fabric_ca",
-					Country:            "US",
-					Province:           "North Carolina",
-					Locality:           "Raleigh",
-					OrganizationalUnit: "Hyperledger FireFly",
-				},
-				Template: &Template{
-					Count:    1,
-					Hostname: "fabric_peer",
-				},
-				Users: &Users{
-					Count: memberCount,
-				},
+				Specs:         ordererSpecs,
 			},
 		},
+		PeerOrgs: peerOrgs,
 	}
 
 	cryptogenConfigBytes, _ := yaml.Marshal(cryptogenConfig)
@@ -0,0 +1,89 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/errcodes"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+var enrollUserNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// EnrollUser registers a new identity with the CA of a member's org, and enrolls it. The MSP of the
+// identity is written alongside the ones generated by cryptogen, and its certificate and key are
+// added to the credential store of the org's fabconnect instances, so the identity can be used
+// as a signer straight away.
+func (p *FabricProvider) EnrollUser(member *types.Organization, user, userType string) (*Account, error) {
+	if !enrollUserNameRegex.MatchString(user) {
+		return nil, fmt.Errorf("invalid user name '%s'. user names may only contain letters, numbers, '_', '.' and '-'", user)
+	}
+	if userType != "client" && userType != "admin" {
+		return nil, fmt.Errorf("invalid identity type '%s'. must be one of: client, admin", userType)
+	}
+	hasRunBefore, err := p.stack.HasRunBefore()
+	if err != nil {
+		return nil, err
+	}
+	if !hasRunBefore {
+		return nil, errcodes.New(errcodes.StackNotStarted, "the stack must be started before users can be enrolled with the fabric CA")
+	}
+
+	org := p.memberOrg(member)
+	secretBytes := make([]byte, 16)
+	rand.Read(secretBytes)
+	secret := hex.EncodeToString(secretBytes)
+
+	orgDir := fmt.Sprintf("/etc/firefly/organizations/peerOrganizations/%s", org.Domain)
+	userMSPDir := fmt.Sprintf("%s/users/%s@%s/msp", orgDir, user, org.Domain)
+	credentialStore := fmt.Sprintf("%s/msp", orgDir)
+	script := strings.Join([]string{
+		"set -e",
+		"export FABRIC_CA_CLIENT_HOME=/tmp/ca-admin",
+		fmt.Sprintf("fabric-ca-client enroll -u http://admin:adminpw@%s:7054", org.CAHost),
+		fmt.Sprintf("fabric-ca-client register -u http://%s:7054 --id.name %s --id.secret %s --id.type %s", org.CAHost, user, secret, userType),
+		fmt.Sprintf("fabric-ca-client enroll -u http://%s:%s@%s:7054 --mspdir %s", user, secret, org.CAHost, userMSPDir),
+		fmt.Sprintf("mkdir -p %s/keystore", credentialStore),
+		fmt.Sprintf("cp %s/signcerts/cert.pem %s/%s@%s-cert.pem", userMSPDir, credentialStore, user, org.MSPID),
+		fmt.Sprintf("cp %s/keystore/* %s/keystore/", userMSPDir, credentialStore),
+	}, "\n")
+
+	p.log.Info(fmt.Sprintf("enrolling %s with %s", user, org.CAHost))
+	volumeName := fmt.Sprintf("%s_firefly_fabric", p.stack.ResourcePrefix())
+	if err := docker.RunDockerCommand(p.ctx, p.stack.RuntimeDir,
+		"run",
+		"--platform", getDockerPlatform(),
+		"--rm",
+		fmt.Sprintf("--network=%s_default", p.stack.ResourcePrefix()),
+		"-v", fmt.Sprintf("%s:/etc/firefly", volumeName),
+		FabricCAImageName,
+		"sh", "-c", script,
+	); err != nil {
+		return nil, err
+	}
+
+	return &Account{
+		Name:    user,
+		OrgName: member.OrgName,
+	}, nil
+}
@@ -23,118 +23,157 @@ import (
 	"github.com/hyperledger/firefly-cli/pkg/types"
 )
 
-func GenerateDockerServiceDefinitions(s *types.Stack) []*docker.ServiceDefinition {
-	serviceDefinitions := []*docker.ServiceDefinition{
-		// Fabric CA
-		{
-			ServiceName: "fabric_ca",
-			Service: &docker.Service{
-				Image:         FabricCAImageName,
-				ContainerName: fmt.Sprintf("%s_fabric_ca", s.Name),
-				Environment: map[string]interface{}{
-					"FABRIC_CA_HOME":                            "/etc/hyperledger/fabric-ca-server",
-					"FABRIC_CA_SERVER_CA_NAME":                  "fabric_ca",
-					"FABRIC_CA_SERVER_PORT":                     "7054",
-					"FABRIC_CA_SERVER_OPERATIONS_LISTENADDRESS": "0.0.0.0:17054",
-					"FABRIC_CA_SERVER_CA_CERTFILE":              "/etc/firefly/organizations/peerOrganizations/org1.example.com/ca/fabric_ca.org1.example.com-cert.pem",
-					"FABRIC_CA_SERVER_CA_KEYFILE":               "/etc/firefly/organizations/peerOrganizations/org1.example.com/ca/priv_sk",
-				},
-				Ports: []string{
-					"7054:7054",
-					"17054:17054",
-				},
-				Command: "sh -c 'fabric-ca-server start -b admin:adminpw'",
-				Volumes: []string{
-					"firefly_fabric:/etc/firefly",
-				},
+// GenerateDockerServiceDefinitions returns a CA and a peer for each org, and the orderers. Only the
+// services of the first org and the first orderer publish their ports on the host.
+func GenerateDockerServiceDefinitions(s *types.Stack, orgs []*fabricOrg, ordererHosts []string) []*docker.ServiceDefinition {
+	serviceDefinitions := []*docker.ServiceDefinition{}
+	for i, org := range orgs {
+		serviceDefinitions = append(serviceDefinitions, caServiceDefinition(s, org, i == 0))
+	}
+	for i, host := range ordererHosts {
+		serviceDefinitions = append(serviceDefinitions, ordererServiceDefinition(s, host, i == 0))
+	}
+	for i, org := range orgs {
+		serviceDefinitions = append(serviceDefinitions, peerServiceDefinition(s, org, i == 0))
+	}
+	return serviceDefinitions
+}
+
+// operationsHealthCheck checks the /healthz endpoint of the operations server of a Fabric CA, orderer or peer
+func operationsHealthCheck(port int) *docker.HealthCheck {
+	return &docker.HealthCheck{
+		Test:     []string{"CMD", "wget", "-q", "-O", "-", fmt.Sprintf("http://localhost:%d/healthz", port)},
+		Interval: "5s",
+		Timeout:  "3s",
+		Retries:  12,
+	}
+}
+
+func caServiceDefinition(s *types.Stack, org *fabricOrg, publishPorts bool) *docker.ServiceDefinition {
+	caDir := fmt.Sprintf("/etc/firefly/organizations/peerOrganizations/%s/ca", org.Domain)
+	serviceDefinition := &docker.ServiceDefinition{
+		ServiceName: org.CAHost,
+		Service: &docker.Service{
+			Image:         FabricCAImageName,
+			ContainerName: fmt.Sprintf("%s_%s", s.ResourcePrefix(), org.CAHost),
+			Environment: map[string]interface{}{
+				"FABRIC_CA_HOME":                            "/etc/hyperledger/fabric-ca-server",
+				"FABRIC_CA_SERVER_CA_NAME":                  org.CAHost,
+				"FABRIC_CA_SERVER_PORT":                     "7054",
+				"FABRIC_CA_SERVER_OPERATIONS_LISTENADDRESS": "0.0.0.0:17054",
+				"FABRIC_CA_SERVER_CA_CERTFILE":              fmt.Sprintf("%s/%s.%s-cert.pem", caDir, org.CAHost, org.Domain),
+				"FABRIC_CA_SERVER_CA_KEYFILE":               fmt.Sprintf("%s/priv_sk", caDir),
+			},
+			Command: "sh -c 'fabric-ca-server start -b admin:adminpw'",
+			Volumes: []string{
+				"firefly_fabric:/etc/firefly",
 			},
-			VolumeNames: []string{"fabric_ca"},
+			HealthCheck: operationsHealthCheck(17054),
 		},
+		VolumeNames: []string{org.CAHost},
+	}
+	if publishPorts {
+		serviceDefinition.Service.Ports = []string{
+			"7054:7054",
+			"17054:17054",
+		}
+	}
+	return serviceDefinition
+}
 
-		// Fabric Orderer
-		{
-			ServiceName: "fabric_orderer",
-			Service: &docker.Service{
-				Image:         FabricOrdererImageName,
-				ContainerName: fmt.Sprintf("%s_fabric_orderer", s.Name),
-				Environment: map[string]interface{}{
-					"FABRIC_LOGGING_SPEC":                       "INFO",
-					"ORDERER_GENERAL_LISTENADDRESS":             "0.0.0.0",
-					"ORDERER_GENERAL_LISTENPORT":                "7050",
-					"ORDERER_GENERAL_LOCALMSPID":                "OrdererMSP",
-					"ORDERER_GENERAL_LOCALMSPDIR":               "/etc/firefly/organizations/ordererOrganizations/example.com/orderers/fabric_orderer.example.com/msp",
-					"ORDERER_GENERAL_TLS_ENABLED":               "true",
-					"ORDERER_GENERAL_TLS_PRIVATEKEY":            "/etc/firefly/organizations/ordererOrganizations/example.com/orderers/fabric_orderer.example.com/tls/server.key",
-					"ORDERER_GENERAL_TLS_CERTIFICATE":           "/etc/firefly/organizations/ordererOrganizations/example.com/orderers/fabric_orderer.example.com/tls/server.crt",
-					"ORDERER_GENERAL_TLS_ROOTCAS":               "[/etc/firefly/organizations/ordererOrganizations/example.com/orderers/fabric_orderer.example.com/tls/ca.crt]",
-					"ORDERER_KAFKA_TOPIC_REPLICATIONFACTOR":     "1",
-					"ORDERER_KAFKA_VERBOSE":                     "true",
-					"ORDERER_GENERAL_CLUSTER_CLIENTCERTIFICATE": "/etc/firefly/organizations/ordererOrganizations/example.com/orderers/fabric_orderer.example.com/tls/server.crt",
-					"ORDERER_GENERAL_CLUSTER_CLIENTPRIVATEKEY":  "/etc/firefly/organizations/ordererOrganizations/example.com/orderers/fabric_orderer.example.com/tls/server.key",
-					"ORDERER_GENERAL_CLUSTER_ROOTCAS":           "[/etc/firefly/organizations/ordererOrganizations/example.com/orderers/fabric_orderer.example.com/tls/ca.crt]",
-					"ORDERER_GENERAL_BOOTSTRAPMETHOD":           "none",
-					"ORDERER_CHANNELPARTICIPATION_ENABLED":      "true",
-					"ORDERER_ADMIN_TLS_ENABLED":                 "true",
-					"ORDERER_ADMIN_TLS_CERTIFICATE":             "/etc/firefly/organizations/ordererOrganizations/example.com/orderers/fabric_orderer.example.com/tls/server.crt",
-					"ORDERER_ADMIN_TLS_PRIVATEKEY":              "/etc/firefly/organizations/ordererOrganizations/example.com/orderers/fabric_orderer.example.com/tls/server.key",
-					"ORDERER_ADMIN_TLS_ROOTCAS":                 "[/etc/firefly/organizations/ordererOrganizations/example.com/orderers/fabric_orderer.example.com/tls/ca.crt]",
-					"ORDERER_ADMIN_TLS_CLIENTROOTCAS":           "[/etc/firefly/organizations/ordererOrganizations/example.com/orderers/fabric_orderer.example.com/tls/ca.crt]",
-					"ORDERER_ADMIN_LISTENADDRESS":               "0.0.0.0:7053",
-					"ORDERER_OPERATIONS_LISTENADDRESS":          "0.0.0.0:17050",
-				},
-				WorkingDir: "/opt/gopath/src/github.com/hyperledger/fabric",
-				Command:    "orderer",
-				Volumes: []string{
-					"firefly_fabric:/etc/firefly",
-					"fabric_orderer:/var/hyperledger/production/orderer",
-				},
-				Ports: []string{
-					"7050:7050",
-					"7053:7053",
-					"17050:17050",
-				},
+func ordererServiceDefinition(s *types.Stack, host string, publishPorts bool) *docker.ServiceDefinition {
+	ordererDir := fmt.Sprintf("/etc/firefly/organizations/ordererOrganizations/example.com/orderers/%s.example.com", host)
+	serviceDefinition := &docker.ServiceDefinition{
+		ServiceName: host,
+		Service: &docker.Service{
+			Image:         FabricOrdererImageName,
+			ContainerName: fmt.Sprintf("%s_%s", s.ResourcePrefix(), host),
+			Environment: map[string]interface{}{
+				"FABRIC_LOGGING_SPEC":                       "INFO",
+				"ORDERER_GENERAL_LISTENADDRESS":             "0.0.0.0",
+				"ORDERER_GENERAL_LISTENPORT":                "7050",
+				"ORDERER_GENERAL_LOCALMSPID":                "OrdererMSP",
+				"ORDERER_GENERAL_LOCALMSPDIR":               fmt.Sprintf("%s/msp", ordererDir),
+				"ORDERER_GENERAL_TLS_ENABLED":               "true",
+				"ORDERER_GENERAL_TLS_PRIVATEKEY":            fmt.Sprintf("%s/tls/server.key", ordererDir),
+				"ORDERER_GENERAL_TLS_CERTIFICATE":           fmt.Sprintf("%s/tls/server.crt", ordererDir),
+				"ORDERER_GENERAL_TLS_ROOTCAS":               fmt.Sprintf("[%s/tls/ca.crt]", ordererDir),
+				"ORDERER_KAFKA_TOPIC_REPLICATIONFACTOR":     "1",
+				"ORDERER_KAFKA_VERBOSE":                     "true",
+				"ORDERER_GENERAL_CLUSTER_CLIENTCERTIFICATE": fmt.Sprintf("%s/tls/server.crt", ordererDir),
+				"ORDERER_GENERAL_CLUSTER_CLIENTPRIVATEKEY":  fmt.Sprintf("%s/tls/server.key", ordererDir),
+				"ORDERER_GENERAL_CLUSTER_ROOTCAS":           fmt.Sprintf("[%s/tls/ca.crt]", ordererDir),
+				"ORDERER_GENERAL_BOOTSTRAPMETHOD":           "none",
+				"ORDERER_CHANNELPARTICIPATION_ENABLED":      "true",
+				"ORDERER_ADMIN_TLS_ENABLED":                 "true",
+				"ORDERER_ADMIN_TLS_CERTIFICATE":             fmt.Sprintf("%s/tls/server.crt", ordererDir),
+				"ORDERER_ADMIN_TLS_PRIVATEKEY":              fmt.Sprintf("%s/tls/server.key", ordererDir),
+				"ORDERER_ADMIN_TLS_ROOTCAS":                 fmt.Sprintf("[%s/tls/ca.crt]", ordererDir),
+				"ORDERER_ADMIN_TLS_CLIENTROOTCAS":           fmt.Sprintf("[%s/tls/ca.crt]", ordererDir),
+				"ORDERER_ADMIN_LISTENADDRESS":               "0.0.0.0:7053",
+				"ORDERER_OPERATIONS_LISTENADDRESS":          "0.0.0.0:17050",
 			},
-			VolumeNames: []string{"fabric_orderer"},
+			WorkingDir: "/opt/gopath/src/github.com/hyperledger/fabric",
+			Command:    "orderer",
+			Volumes: []string{
+				"firefly_fabric:/etc/firefly",
+				fmt.Sprintf("%s:/var/hyperledger/production/orderer", host),
+			},
+			HealthCheck: operationsHealthCheck(17050),
 		},
+		VolumeNames: []string{host},
+	}
+	if publishPorts {
+		serviceDefinition.Service.Ports = []string{
+			"7050:7050",
+			"7053:7053",
+			"17050:17050",
+		}
+	}
+	return serviceDefinition
+}
 
-		// Fabric Peer
-		{
-			ServiceName: "fabric_peer",
-			Service: &docker.Service{
-				Image:         FabricPeerImageName,
-				ContainerName: fmt.Sprintf("%s_fabric_peer", s.Name),
-				Environment: map[string]interface{}{
-					"CORE_VM_ENDPOINT":                      "unix:///host/var/run/docker.sock",
-					"CORE_VM_DOCKER_HOSTCONFIG_NETWORKMODE": fmt.Sprintf("%s_default", s.Name),
-					"FABRIC_LOGGING_SPEC":                   "INFO",
-					"CORE_PEER_TLS_ENABLED":                 "true",
-					"CORE_PEER_PROFILE_ENABLED":             "false",
-					"CORE_PEER_MSPCONFIGPATH":               "/etc/firefly/organizations/peerOrganizations/org1.example.com/peers/fabric_peer.org1.example.com/msp",
-					"CORE_PEER_TLS_CERT_FILE":               "/etc/firefly/organizations/peerOrganizations/org1.example.com/peers/fabric_peer.org1.example.com/tls/server.crt",
-					"CORE_PEER_TLS_KEY_FILE":                "/etc/firefly/organizations/peerOrganizations/org1.example.com/peers/fabric_peer.org1.example.com/tls/server.key",
-					"CORE_PEER_TLS_ROOTCERT_FILE":           "/etc/firefly/organizations/peerOrganizations/org1.example.com/peers/fabric_peer.org1.example.com/tls/ca.crt",
-					"CORE_PEER_ID":                          "fabric_peer",
-					"CORE_PEER_ADDRESS":                     "fabric_peer:7051",
-					"CORE_PEER_LISTENADDRESS":               "0.0.0.0:7051",
-					"CORE_PEER_CHAINCODEADDRESS":            "fabric_peer:7052",
-					"CORE_PEER_CHAINCODELISTENADDRESS":      "0.0.0.0:7052",
-					"CORE_PEER_GOSSIP_BOOTSTRAP":            "fabric_peer:7051",
-					"CORE_PEER_GOSSIP_EXTERNALENDPOINT":     "fabric_peer:7051",
-					"CORE_PEER_LOCALMSPID":                  "Org1MSP",
-					"CORE_OPERATIONS_LISTENADDRESS":         "0.0.0.0:17051",
-				},
-				Volumes: []string{
-					"firefly_fabric:/etc/firefly",
-					"fabric_peer:/var/hyperledger/production",
-					"/var/run/docker.sock:/host/var/run/docker.sock",
-				},
-				Ports: []string{
-					"7051:7051",
-					"17051:17051",
-				},
+func peerServiceDefinition(s *types.Stack, org *fabricOrg, publishPorts bool) *docker.ServiceDefinition {
+	peerDir := fmt.Sprintf("/etc/firefly/organizations/peerOrganizations/%s/peers/%s.%s", org.Domain, org.PeerHost, org.Domain)
+	serviceDefinition := &docker.ServiceDefinition{
+		ServiceName: org.PeerHost,
+		Service: &docker.Service{
+			Image:         FabricPeerImageName,
+			ContainerName: fmt.Sprintf("%s_%s", s.ResourcePrefix(), org.PeerHost),
+			Environment: map[string]interface{}{
+				"CORE_VM_ENDPOINT":                      "unix:///host/var/run/docker.sock",
+				"CORE_VM_DOCKER_HOSTCONFIG_NETWORKMODE": fmt.Sprintf("%s_default", s.ResourcePrefix()),
+				"FABRIC_LOGGING_SPEC":                   "INFO",
+				"CORE_PEER_TLS_ENABLED":                 "true",
+				"CORE_PEER_PROFILE_ENABLED":             "false",
+				"CORE_PEER_MSPCONFIGPATH":               fmt.Sprintf("%s/msp", peerDir),
+				"CORE_PEER_TLS_CERT_FILE":               fmt.Sprintf("%s/tls/server.crt", peerDir),
+				"CORE_PEER_TLS_KEY_FILE":                fmt.Sprintf("%s/tls/server.key", peerDir),
+				"CORE_PEER_TLS_ROOTCERT_FILE":           fmt.Sprintf("%s/tls/ca.crt", peerDir),
+				"CORE_PEER_ID":                          org.PeerHost,
+				"CORE_PEER_ADDRESS":                     fmt.Sprintf("%s:7051", org.PeerHost),
+				"CORE_PEER_LISTENADDRESS":               "0.0.0.0:7051",
+				"CORE_PEER_CHAINCODEADDRESS":            fmt.Sprintf("%s:7052", org.PeerHost),
+				"CORE_PEER_CHAINCODELISTENADDRESS":      "0.0.0.0:7052",
+				"CORE_PEER_GOSSIP_BOOTSTRAP":            fmt.Sprintf("%s:7051", org.PeerHost),
+				"CORE_PEER_GOSSIP_EXTERNALENDPOINT":     fmt.Sprintf("%s:7051", org.PeerHost),
+				"CORE_PEER_LOCALMSPID":                  org.MSPID,
+				"CORE_OPERATIONS_LISTENADDRESS":         "0.0.0.0:17051",
+			},
+			Volumes: []string{
+				"firefly_fabric:/etc/firefly",
+				fmt.Sprintf("%s:/var/hyperledger/production", org.PeerHost),
+				"/var/run/docker.sock:/host/var/run/docker.sock",
 			},
-			VolumeNames: []string{"fabric_peer"},
+			HealthCheck: operationsHealthCheck(17051),
 		},
+		VolumeNames: []string{org.PeerHost},
 	}
-	return serviceDefinitions
+	if publishPorts {
+		serviceDefinition.Service.Ports = []string{
+			"7051:7051",
+			"17051:17051",
+		}
+	}
+	return serviceDefinition
 }
@@ -17,6 +17,7 @@
 package fabric
 
 import (
+	"bytes"
 	"context"
 	_ "embed"
 	"encoding/json"
@@ -26,6 +27,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"text/template"
 
 	"github.com/hyperledger/firefly-cli/internal/blockchain/fabric/fabconnect"
 	"github.com/hyperledger/firefly-cli/internal/docker"
@@ -65,11 +67,16 @@ func (p *FabricProvider) WriteConfig(options *types.InitOptions) error {
 
 	os.MkdirAll(blockchainDirectory, 0755)
 
-	if err := WriteCryptogenConfig(len(p.stack.Members), cryptogenYamlPath); err != nil {
+	topology := p.topology()
+	orgs := p.orgs()
+	ordererHosts := getOrdererHosts(topology)
+	if err := WriteCryptogenConfig(orgs, ordererHosts, cryptogenYamlPath); err != nil {
 		return err
 	}
-	if err := WriteNetworkConfig(path.Join(blockchainDirectory, "ccp.yaml")); err != nil {
-		return err
+	for _, org := range orgs {
+		if err := WriteNetworkConfig(path.Join(blockchainDirectory, org.ConnectionProfile), org, orgs, ordererHosts, topology.Channels); err != nil {
+			return err
+		}
 	}
 	if err := fabconnect.WriteFabconnectConfig(path.Join(blockchainDirectory, "fabconnect.yaml")); err != nil {
 		return err
@@ -84,7 +91,7 @@ func (p *FabricProvider) WriteConfig(options *types.InitOptions) error {
 func (p *FabricProvider) FirstTimeSetup() error {
 	blockchainDirectory := path.Join(p.stack.RuntimeDir, "blockchain")
 	cryptogenYamlPath := path.Join(blockchainDirectory, "cryptogen.yaml")
-	volumeName := fmt.Sprintf("%s_firefly_fabric", p.stack.Name)
+	volumeName := fmt.Sprintf("%s_firefly_fabric", p.stack.ResourcePrefix())
 
 	if err := docker.CreateVolume(p.ctx, volumeName); err != nil {
 		return err
@@ -105,20 +112,22 @@ func (p *FabricProvider) FirstTimeSetup() error {
 		return err
 	}
 
-	// Generate genesis block
-	if err := docker.RunDockerCommand(p.ctx, blockchainDirectory,
-		"run",
-		"--platform", getDockerPlatform(),
-		"--rm",
-		"-v", fmt.Sprintf("%s:/etc/firefly", volumeName),
-		"-v", fmt.Sprintf("%s:/etc/hyperledger/fabric/configtx.yaml", path.Join(blockchainDirectory, "configtx.yaml")),
-		FabricToolsImageName,
-		"configtxgen",
-		"-outputBlock", "/etc/firefly/firefly.block",
-		"-profile", "SingleOrgApplicationGenesis",
-		"-channelID", "firefly",
-	); err != nil {
-		return err
+	// Generate the genesis block of each channel
+	for _, c := range p.topology().Channels {
+		if err := docker.RunDockerCommand(p.ctx, blockchainDirectory,
+			"run",
+			"--platform", getDockerPlatform(),
+			"--rm",
+			"-v", fmt.Sprintf("%s:/etc/firefly", volumeName),
+			"-v", fmt.Sprintf("%s:/etc/hyperledger/fabric/configtx.yaml", path.Join(blockchainDirectory, "configtx.yaml")),
+			FabricToolsImageName,
+			"configtxgen",
+			"-outputBlock", fmt.Sprintf("/etc/firefly/%s.block", c.Name),
+			"-profile", channelProfile(c.Name),
+			"-channelID", c.Name,
+		); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -137,11 +146,19 @@ func (p *FabricProvider) deploySmartContracts() (*types.ContractDeploymentResult
 		return nil, err
 	}
 
-	if err := p.installChaincode(packageFilename); err != nil {
+	channel := p.fireflyChannel()
+	orgs, err := p.channelOrgs(channel)
+	if err != nil {
 		return nil, err
 	}
 
-	res, err := p.queryInstalled()
+	for _, org := range orgs {
+		if err := p.installChaincode(org, packageFilename); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := p.queryInstalled(orgs[0])
 	if err != nil {
 		return nil, err
 	}
@@ -149,11 +166,13 @@ func (p *FabricProvider) deploySmartContracts() (*types.ContractDeploymentResult
 		return nil, fmt.Errorf("failed to find installed chaincode")
 	}
 
-	if err := p.approveChaincode(channel, chaincodeName, chaincodeVersion, res.InstalledChaincodes[0].PackageID); err != nil {
-		return nil, err
+	for _, org := range orgs {
+		if err := p.approveChaincode(org, channel, chaincodeName, chaincodeVersion, res.InstalledChaincodes[0].PackageID); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := p.commitChaincode(channel, chaincodeName, chaincodeVersion); err != nil {
+	if err := p.commitChaincode(orgs, channel, chaincodeName, chaincodeVersion); err != nil {
 		return nil, err
 	}
 
@@ -175,12 +194,19 @@ func (p *FabricProvider) PreStart() error {
 
 func (p *FabricProvider) PostStart(firstTimeSetup bool) error {
 	if firstTimeSetup {
-		if err := p.createChannel(); err != nil {
-			return err
-		}
-
-		if err := p.joinChannel(); err != nil {
-			return err
+		for _, c := range p.topology().Channels {
+			if err := p.createChannel(c.Name); err != nil {
+				return err
+			}
+			orgs, err := p.channelOrgs(c.Name)
+			if err != nil {
+				return err
+			}
+			for _, org := range orgs {
+				if err := p.joinChannel(org, c.Name); err != nil {
+					return err
+				}
+			}
 		}
 
 		// Register pre-created identities
@@ -196,7 +222,7 @@ func (p *FabricProvider) PostStart(firstTimeSetup bool) error {
 }
 
 func (p *FabricProvider) GetDockerServiceDefinitions() []*docker.ServiceDefinition {
-	serviceDefinitions := GenerateDockerServiceDefinitions(p.stack)
+	serviceDefinitions := GenerateDockerServiceDefinitions(p.stack, p.orgs(), getOrdererHosts(p.topology()))
 	serviceDefinitions = append(serviceDefinitions, p.getFabconnectServiceDefinitions(p.stack.Members)...)
 	return serviceDefinitions
 }
@@ -215,7 +241,7 @@ func (p *FabricProvider) GetBlockchainPluginConfig(stack *types.Stack, m *types.
 			Fabconnect: &types.FabconnectConfig{
 				URL:       connectorURL,
 				Chaincode: "firefly",
-				Channel:   "firefly",
+				Channel:   p.fireflyChannel(),
 				Signer:    m.OrgName,
 				Topic:     m.ID,
 			},
@@ -238,25 +264,26 @@ func (p *FabricProvider) Reset() error {
 
 func (p *FabricProvider) getFabconnectServiceDefinitions(members []*types.Organization) []*docker.ServiceDefinition {
 	blockchainDirectory := path.Join(p.stack.RuntimeDir, "blockchain")
+	dependsOn := map[string]map[string]string{}
+	for _, fabricService := range GenerateDockerServiceDefinitions(p.stack, p.orgs(), getOrdererHosts(p.topology())) {
+		dependsOn[fabricService.ServiceName] = map[string]string{"condition": "service_healthy"}
+	}
 	serviceDefinitions := make([]*docker.ServiceDefinition, len(members))
 	for i, member := range members {
+		connectionProfile := path.Join(blockchainDirectory, p.memberOrg(member).ConnectionProfile)
 		serviceDefinitions[i] = &docker.ServiceDefinition{
 			ServiceName: "fabconnect_" + member.ID,
 			Service: &docker.Service{
 				Image:         p.stack.VersionManifest.Fabconnect.GetDockerImageString(),
-				ContainerName: fmt.Sprintf("%s_fabconnect_%s", p.stack.Name, member.ID),
+				ContainerName: fmt.Sprintf("%s_fabconnect_%s", p.stack.ResourcePrefix(), member.ID),
 				Command:       "-f /fabconnect/fabconnect.yaml",
-				DependsOn: map[string]map[string]string{
-					"fabric_ca":      {"condition": "service_started"},
-					"fabric_peer":    {"condition": "service_started"},
-					"fabric_orderer": {"condition": "service_started"},
-				},
-				Ports: []string{fmt.Sprintf("%d:3000", member.ExposedConnectorPort)},
+				DependsOn:     dependsOn,
+				Ports:         []string{fmt.Sprintf("%d:3000", member.ExposedConnectorPort)},
 				Volumes: []string{
 					fmt.Sprintf("fabconnect_receipts_%s:/fabconnect/receipts", member.ID),
 					fmt.Sprintf("fabconnect_events_%s:/fabconnect/events", member.ID),
 					fmt.Sprintf("%s:/fabconnect/fabconnect.yaml", path.Join(blockchainDirectory, "fabconnect.yaml")),
-					fmt.Sprintf("%s:/fabconnect/ccp.yaml", path.Join(blockchainDirectory, "ccp.yaml")),
+					fmt.Sprintf("%s:/fabconnect/ccp.yaml", connectionProfile),
 					"firefly_fabric:/etc/firefly",
 				},
 				HealthCheck: &docker.HealthCheck{
@@ -274,50 +301,88 @@ func (p *FabricProvider) getFabconnectServiceDefinitions(members []*types.Organi
 	return serviceDefinitions
 }
 
+type configtxChannel struct {
+	Profile string
+	Orgs    []*fabricOrg
+}
+
+func channelProfile(channelName string) string {
+	return fmt.Sprintf("%sGenesis", channelName)
+}
+
 func (p *FabricProvider) writeConfigtxYaml() error {
+	tmpl, err := template.New("configtx").Parse(configtxYaml)
+	if err != nil {
+		return err
+	}
+	topology := p.topology()
+	channels := make([]*configtxChannel, len(topology.Channels))
+	for i, c := range topology.Channels {
+		orgs, err := p.channelOrgs(c.Name)
+		if err != nil {
+			return err
+		}
+		channels[i] = &configtxChannel{
+			Profile: channelProfile(c.Name),
+			Orgs:    orgs,
+		}
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]interface{}{
+		"Orgs":     p.orgs(),
+		"Orderers": getOrdererHosts(topology),
+		"Channels": channels,
+	}); err != nil {
+		return err
+	}
 	filePath := path.Join(p.stack.InitDir, "blockchain", "configtx.yaml")
-	return ioutil.WriteFile(filePath, []byte(configtxYaml), 0755)
+	return ioutil.WriteFile(filePath, buf.Bytes(), 0755)
 }
 
-func (p *FabricProvider) createChannel() error {
-	p.log.Info("creating channel")
+// createChannel joins every orderer to a channel, using the channel's genesis block
+func (p *FabricProvider) createChannel(channel string) error {
+	p.log.Info(fmt.Sprintf("creating channel %s", channel))
 	stackDir := p.stack.StackDir
-	volumeName := fmt.Sprintf("%s_firefly_fabric", p.stack.Name)
-	return docker.RunDockerCommand(p.ctx, stackDir,
-		"run",
-		"--platform", getDockerPlatform(),
-		"--rm",
-		fmt.Sprintf("--network=%s_default", p.stack.Name),
-		"-v", fmt.Sprintf("%s:/etc/firefly", volumeName),
-		FabricToolsImageName,
-		"osnadmin", "channel", "join",
-		"--channelID", "firefly",
-		"--config-block", "/etc/firefly/firefly.block",
-		"-o", "fabric_orderer:7053",
-		"--ca-file", "/etc/firefly/organizations/ordererOrganizations/example.com/users/Admin@example.com/tls/ca.crt",
-		"--client-cert", "/etc/firefly/organizations/ordererOrganizations/example.com/users/Admin@example.com/tls/client.crt",
-		"--client-key", "/etc/firefly/organizations/ordererOrganizations/example.com/users/Admin@example.com/tls/client.key",
-	)
+	volumeName := fmt.Sprintf("%s_firefly_fabric", p.stack.ResourcePrefix())
+	for _, host := range getOrdererHosts(p.topology()) {
+		if err := docker.RunDockerCommand(p.ctx, stackDir,
+			"run",
+			"--platform", getDockerPlatform(),
+			"--rm",
+			fmt.Sprintf("--network=%s_default", p.stack.ResourcePrefix()),
+			"-v", fmt.Sprintf("%s:/etc/firefly", volumeName),
+			FabricToolsImageName,
+			"osnadmin", "channel", "join",
+			"--channelID", channel,
+			"--config-block", fmt.Sprintf("/etc/firefly/%s.block", channel),
+			"-o", fmt.Sprintf("%s:7053", host),
+			"--ca-file", "/etc/firefly/organizations/ordererOrganizations/example.com/users/Admin@example.com/tls/ca.crt",
+			"--client-cert", "/etc/firefly/organizations/ordererOrganizations/example.com/users/Admin@example.com/tls/client.crt",
+			"--client-key", "/etc/firefly/organizations/ordererOrganizations/example.com/users/Admin@example.com/tls/client.key",
+		); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (p *FabricProvider) joinChannel() error {
-	p.log.Info("joining channel")
+func (p *FabricProvider) joinChannel(org *fabricOrg, channel string) error {
+	p.log.Info(fmt.Sprintf("joining %s to channel %s", org.Name, channel))
 	stackDir := p.stack.StackDir
-	volumeName := fmt.Sprintf("%s_firefly_fabric", p.stack.Name)
-	return docker.RunDockerCommand(p.ctx, stackDir,
+	volumeName := fmt.Sprintf("%s_firefly_fabric", p.stack.ResourcePrefix())
+	args := []string{
 		"run",
 		"--platform", getDockerPlatform(),
 		"--rm",
-		fmt.Sprintf("--network=%s_default", p.stack.Name),
+		fmt.Sprintf("--network=%s_default", p.stack.ResourcePrefix()),
 		"-v", fmt.Sprintf("%s:/etc/firefly", volumeName),
-		"-e", "CORE_PEER_ADDRESS=fabric_peer:7051",
-		"-e", "CORE_PEER_TLS_ENABLED=true",
-		"-e", "CORE_PEER_TLS_ROOTCERT_FILE=/etc/firefly/organizations/peerOrganizations/org1.example.com/peers/fabric_peer.org1.example.com/tls/ca.crt",
-		"-e", "CORE_PEER_LOCALMSPID=Org1MSP",
-		"-e", "CORE_PEER_MSPCONFIGPATH=/etc/firefly/organizations/peerOrganizations/org1.example.com/users/Admin@org1.example.com/msp",
+	}
+	args = append(args, peerEnv(org)...)
+	args = append(args,
 		FabricToolsImageName,
 		"peer", "channel", "join",
-		"-b", "/etc/firefly/firefly.block")
+		"-b", fmt.Sprintf("/etc/firefly/%s.block", channel))
+	return docker.RunDockerCommand(p.ctx, stackDir, args...)
 }
 
 func (p *FabricProvider) extractChaincode() error {
@@ -330,7 +395,7 @@ func (p *FabricProvider) extractChaincode() error {
 	var containerName string
 	for _, member := range p.stack.Members {
 		if !member.External {
-			containerName = fmt.Sprintf("%s_firefly_core_%s", p.stack.Name, member.ID)
+			containerName = fmt.Sprintf("%s_firefly_core_%s", p.stack.ResourcePrefix(), member.ID)
 			break
 		}
 	}
@@ -344,45 +409,43 @@ func (p *FabricProvider) extractChaincode() error {
 	return nil
 }
 
-func (p *FabricProvider) installChaincode(packageFilename string) error {
-	p.log.Info("installing chaincode")
+func (p *FabricProvider) installChaincode(org *fabricOrg, packageFilename string) error {
+	p.log.Info(fmt.Sprintf("installing chaincode on %s", org.PeerHost))
 	contractsDir := path.Join(p.stack.RuntimeDir, "contracts")
-	volumeName := fmt.Sprintf("%s_firefly_fabric", p.stack.Name)
-	return docker.RunDockerCommand(p.ctx, contractsDir,
+	volumeName := fmt.Sprintf("%s_firefly_fabric", p.stack.ResourcePrefix())
+	args := []string{
 		"run",
 		"--platform", getDockerPlatform(),
 		"--rm",
-		fmt.Sprintf("--network=%s_default", p.stack.Name),
-		"-e", "CORE_PEER_ADDRESS=fabric_peer:7051",
-		"-e", "CORE_PEER_TLS_ENABLED=true",
-		"-e", "CORE_PEER_TLS_ROOTCERT_FILE=/etc/firefly/organizations/peerOrganizations/org1.example.com/peers/fabric_peer.org1.example.com/tls/ca.crt",
-		"-e", "CORE_PEER_LOCALMSPID=Org1MSP",
-		"-e", "CORE_PEER_MSPCONFIGPATH=/etc/firefly/organizations/peerOrganizations/org1.example.com/users/Admin@org1.example.com/msp",
+		fmt.Sprintf("--network=%s_default", p.stack.ResourcePrefix()),
+	}
+	args = append(args, peerEnv(org)...)
+	args = append(args,
 		"-v", fmt.Sprintf("%s:/package.tar.gz", packageFilename),
 		"-v", fmt.Sprintf("%s:/etc/firefly", volumeName),
 		FabricToolsImageName,
 		"peer", "lifecycle", "chaincode", "install", "/package.tar.gz",
 	)
+	return docker.RunDockerCommand(p.ctx, contractsDir, args...)
 }
 
-func (p *FabricProvider) queryInstalled() (*QueryInstalledResponse, error) {
+func (p *FabricProvider) queryInstalled(org *fabricOrg) (*QueryInstalledResponse, error) {
 	p.log.Info("querying installed chaincode")
-	volumeName := fmt.Sprintf("%s_firefly_fabric", p.stack.Name)
-	str, err := docker.RunDockerCommandBuffered(p.ctx, p.stack.RuntimeDir,
+	volumeName := fmt.Sprintf("%s_firefly_fabric", p.stack.ResourcePrefix())
+	args := []string{
 		"run",
 		"--platform", getDockerPlatform(),
 		"--rm",
-		fmt.Sprintf("--network=%s_default", p.stack.Name),
-		"-e", "CORE_PEER_ADDRESS=fabric_peer:7051",
-		"-e", "CORE_PEER_TLS_ENABLED=true",
-		"-e", "CORE_PEER_TLS_ROOTCERT_FILE=/etc/firefly/organizations/peerOrganizations/org1.example.com/peers/fabric_peer.org1.example.com/tls/ca.crt",
-		"-e", "CORE_PEER_LOCALMSPID=Org1MSP",
-		"-e", "CORE_PEER_MSPCONFIGPATH=/etc/firefly/organizations/peerOrganizations/org1.example.com/users/Admin@org1.example.com/msp",
+		fmt.Sprintf("--network=%s_default", p.stack.ResourcePrefix()),
+	}
+	args = append(args, peerEnv(org)...)
+	args = append(args,
 		"-v", fmt.Sprintf("%s:/etc/firefly", volumeName),
 		FabricToolsImageName,
 		"peer", "lifecycle", "chaincode", "queryinstalled",
 		"--output", "json",
 	)
+	str, err := docker.RunDockerCommandBuffered(p.ctx, p.stack.RuntimeDir, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -394,19 +457,17 @@ func (p *FabricProvider) queryInstalled() (*QueryInstalledResponse, error) {
 	return res, nil
 }
 
-func (p *FabricProvider) approveChaincode(channel, chaincode, version, packageId string) error {
-	p.log.Info("approving chaincode")
-	volumeName := fmt.Sprintf("%s_firefly_fabric", p.stack.Name)
-	return docker.RunDockerCommand(p.ctx, p.stack.RuntimeDir,
+func (p *FabricProvider) approveChaincode(org *fabricOrg, channel, chaincode, version, packageId string) error {
+	p.log.Info(fmt.Sprintf("approving chaincode for %s", org.Name))
+	volumeName := fmt.Sprintf("%s_firefly_fabric", p.stack.ResourcePrefix())
+	args := []string{
 		"run",
 		"--platform", getDockerPlatform(),
 		"--rm",
-		fmt.Sprintf("--network=%s_default", p.stack.Name),
-		"-e", "CORE_PEER_ADDRESS=fabric_peer:7051",
-		"-e", "CORE_PEER_TLS_ENABLED=true",
-		"-e", "CORE_PEER_TLS_ROOTCERT_FILE=/etc/firefly/organizations/peerOrganizations/org1.example.com/peers/fabric_peer.org1.example.com/tls/ca.crt",
-		"-e", "CORE_PEER_LOCALMSPID=Org1MSP",
-		"-e", "CORE_PEER_MSPCONFIGPATH=/etc/firefly/organizations/peerOrganizations/org1.example.com/users/Admin@org1.example.com/msp",
+		fmt.Sprintf("--network=%s_default", p.stack.ResourcePrefix()),
+	}
+	args = append(args, peerEnv(org)...)
+	args = append(args,
 		"-v", fmt.Sprintf("%s:/etc/firefly", volumeName),
 		FabricToolsImageName,
 		"peer", "lifecycle", "chaincode", "approveformyorg",
@@ -420,21 +481,22 @@ func (p *FabricProvider) approveChaincode(channel, chaincode, version, packageId
 		"--tls",
 		"--cafile", "/etc/firefly/organizations/ordererOrganizations/example.com/orderers/fabric_orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem",
 	)
+	return docker.RunDockerCommand(p.ctx, p.stack.RuntimeDir, args...)
 }
 
-func (p *FabricProvider) commitChaincode(channel, chaincode, version string) error {
+// commitChaincode commits a chaincode definition to a channel, collecting endorsements from the
+// peer of every org on the channel
+func (p *FabricProvider) commitChaincode(orgs []*fabricOrg, channel, chaincode, version string) error {
 	p.log.Info("committing chaincode")
-	volumeName := fmt.Sprintf("%s_firefly_fabric", p.stack.Name)
-	return docker.RunDockerCommand(p.ctx, p.stack.RuntimeDir,
+	volumeName := fmt.Sprintf("%s_firefly_fabric", p.stack.ResourcePrefix())
+	args := []string{
 		"run",
 		"--platform", getDockerPlatform(),
 		"--rm",
-		fmt.Sprintf("--network=%s_default", p.stack.Name),
-		"-e", "CORE_PEER_ADDRESS=fabric_peer:7051",
-		"-e", "CORE_PEER_TLS_ENABLED=true",
-		"-e", "CORE_PEER_TLS_ROOTCERT_FILE=/etc/firefly/organizations/peerOrganizations/org1.example.com/peers/fabric_peer.org1.example.com/tls/ca.crt",
-		"-e", "CORE_PEER_LOCALMSPID=Org1MSP",
-		"-e", "CORE_PEER_MSPCONFIGPATH=/etc/firefly/organizations/peerOrganizations/org1.example.com/users/Admin@org1.example.com/msp",
+		fmt.Sprintf("--network=%s_default", p.stack.ResourcePrefix()),
+	}
+	args = append(args, peerEnv(orgs[0])...)
+	args = append(args,
 		"-v", fmt.Sprintf("%s:/etc/firefly", volumeName),
 		FabricToolsImageName,
 		"peer", "lifecycle", "chaincode", "commit",
@@ -447,6 +509,15 @@ func (p *FabricProvider) commitChaincode(channel, chaincode, version string) err
 		"--tls",
 		"--cafile", "/etc/firefly/organizations/ordererOrganizations/example.com/orderers/fabric_orderer.example.com/msp/tlscacerts/tlsca.example.com-cert.pem",
 	)
+	if len(orgs) > 1 {
+		for _, org := range orgs {
+			args = append(args,
+				"--peerAddresses", fmt.Sprintf("%s:7051", org.PeerHost),
+				"--tlsRootCertFiles", fmt.Sprintf("/etc/firefly/organizations/peerOrganizations/%s/peers/%s.%s/tls/ca.crt", org.Domain, org.PeerHost, org.Domain),
+			)
+		}
+	}
+	return docker.RunDockerCommand(p.ctx, p.stack.RuntimeDir, args...)
 }
 
 func (p *FabricProvider) registerIdentity(member *types.Organization, name string) (*Account, error) {
@@ -485,11 +556,18 @@ func (p *FabricProvider) DeployContract(filename, contractName, instanceName str
 	chaincode := extraArgs[1]
 	version := extraArgs[2]
 
-	if err := p.installChaincode(filename); err != nil {
+	orgs, err := p.channelOrgs(channel)
+	if err != nil {
 		return nil, err
 	}
 
-	res, err := p.queryInstalled()
+	for _, org := range orgs {
+		if err := p.installChaincode(org, filename); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := p.queryInstalled(orgs[0])
 	if err != nil {
 		return nil, err
 	}
@@ -508,11 +586,13 @@ func (p *FabricProvider) DeployContract(filename, contractName, instanceName str
 		return nil, fmt.Errorf("failed to find installed chaincode")
 	}
 
-	if err := p.approveChaincode(channel, chaincode, version, packageID); err != nil {
-		return nil, err
+	for _, org := range orgs {
+		if err := p.approveChaincode(org, channel, chaincode, version, packageID); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := p.commitChaincode(channel, chaincode, version); err != nil {
+	if err := p.commitChaincode(orgs, channel, chaincode, version); err != nil {
 		return nil, err
 	}
 	result := &types.ContractDeploymentResult{
@@ -580,3 +660,15 @@ func (p *FabricProvider) GetConnectorURL(org *types.Organization) string {
 func (p *FabricProvider) GetConnectorExternalURL(org *types.Organization) string {
 	return fmt.Sprintf("http://127.0.0.1:%v", org.ExposedConnectorPort)
 }
+
+func (p *FabricProvider) ListEventStreams(member *types.Organization) ([]*types.EventStream, error) {
+	return nil, fmt.Errorf("event stream management is not supported for %s", p.GetConnectorName())
+}
+
+func (p *FabricProvider) ResetEventStreamListener(member *types.Organization, streamID, listenerID, fromBlock string) error {
+	return fmt.Errorf("event stream management is not supported for %s", p.GetConnectorName())
+}
+
+func (p *FabricProvider) ExportChain(outputDir string) error {
+	return fmt.Errorf("chain export is not supported for fabric")
+}
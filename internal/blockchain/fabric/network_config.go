@@ -17,8 +17,10 @@
 package fabric
 
 import (
+	"fmt"
 	"io/ioutil"
 
+	"github.com/hyperledger/firefly-cli/pkg/types"
 	"gopkg.in/yaml.v3"
 )
 
@@ -110,33 +112,13 @@ type FabricNetworkConfig struct {
 	Version                string                    `yaml:"version,omitempty"`
 }
 
-func WriteNetworkConfig(outputPath string) error {
+// WriteNetworkConfig writes the connection profile used by the fabconnect instances of an org. It
+// contains the channels the org is on, and the peers of every org on those channels.
+func WriteNetworkConfig(outputPath string, org *fabricOrg, orgs []*fabricOrg, ordererHosts []string, channels []*types.FabricTopologyChannel) error {
+	orgsDir := "/etc/firefly/organizations/peerOrganizations"
 	networkConfig := &FabricNetworkConfig{
-		CertificateAuthorities: map[string]*NetworkEntity{
-			"org1.example.com": {
-				TLSCACerts: &Path{
-					Path: "/etc/firefly/organizations/peerOrganizations/org1.example.com/ca/fabric_ca.org1.example.com-cert.pem",
-				},
-				URL: "http://fabric_ca:7054",
-				Registrar: &Registrar{
-					EnrollID:     "admin",
-					EnrollSecret: "adminpw",
-				},
-			},
-		},
-		Channels: map[string]*Channel{
-			"firefly": {
-				Orderers: []string{"fabric_orderer"},
-				Peers: map[string]*ChannelPeer{
-					"fabric_peer": {
-						ChaincodeQuery: true,
-						EndorsingPeer:  true,
-						EventSource:    true,
-						LedgerQuery:    true,
-					},
-				},
-			},
-		},
+		CertificateAuthorities: map[string]*NetworkEntity{},
+		Channels:               map[string]*Channel{},
 		Client: &Client{
 			BCCSP: &BCCSP{
 				Security: &BCCSPSecurity{
@@ -151,54 +133,87 @@ func WriteNetworkConfig(outputPath string) error {
 			},
 			CredentialStore: &CredentialStore{
 				CryptoStore: &Path{
-					Path: "/etc/firefly/organizations/peerOrganizations/org1.example.com/msp",
+					Path: fmt.Sprintf("%s/%s/msp", orgsDir, org.Domain),
 				},
-				Path: "/etc/firefly/organizations/peerOrganizations/org1.example.com/msp",
+				Path: fmt.Sprintf("%s/%s/msp", orgsDir, org.Domain),
 			},
 			CryptoConfig: &Path{
-				Path: "/etc/firefly/organizations/peerOrganizations/org1.example.com/msp",
+				Path: fmt.Sprintf("%s/%s/msp", orgsDir, org.Domain),
 			},
 			Logging: &Logging{
 				Level: "info",
 			},
-			Organization: "org1.example.com",
+			Organization: org.Domain,
 			TLSCerts: &TLSCerts{
 				Client: &TLSCertsClient{
 					Cert: &Path{
-						Path: "/etc/firefly/organizations/peerOrganizations/org1.example.com/users/Admin@org1.example.com/tls/client.crt",
+						Path: fmt.Sprintf("%s/%s/users/Admin@%s/tls/client.crt", orgsDir, org.Domain, org.Domain),
 					},
 					Key: &Path{
-						Path: "/etc/firefly/organizations/peerOrganizations/org1.example.com/users/Admin@org1.example.com/tls/client.key",
+						Path: fmt.Sprintf("%s/%s/users/Admin@%s/tls/client.key", orgsDir, org.Domain, org.Domain),
 					},
 				},
 			},
 		},
-		Orderers: map[string]*NetworkEntity{
-			"fabric_orderer": {
+		Orderers:      map[string]*NetworkEntity{},
+		Organizations: map[string]*Organization{},
+		Peers:         map[string]*NetworkEntity{},
+		Version:       "1.1.0%",
+	}
+
+	for _, host := range ordererHosts {
+		networkConfig.Orderers[host] = &NetworkEntity{
+			TLSCACerts: &Path{
+				Path: "/etc/firefly/organizations/ordererOrganizations/example.com/tlsca/tlsca.example.com-cert.pem",
+			},
+			URL: fmt.Sprintf("grpcs://%s:7050", host),
+		}
+	}
+
+	for _, c := range channels {
+		if !channelHasOrg(c, org.Name) {
+			continue
+		}
+		channelConfig := &Channel{
+			Orderers: ordererHosts,
+			Peers:    map[string]*ChannelPeer{},
+		}
+		for _, o := range orgs {
+			if !channelHasOrg(c, o.Name) {
+				continue
+			}
+			channelConfig.Peers[o.PeerHost] = &ChannelPeer{
+				ChaincodeQuery: true,
+				EndorsingPeer:  true,
+				EventSource:    true,
+				LedgerQuery:    true,
+			}
+			networkConfig.CertificateAuthorities[o.Domain] = &NetworkEntity{
 				TLSCACerts: &Path{
-					Path: "/etc/firefly/organizations/ordererOrganizations/example.com/tlsca/tlsca.example.com-cert.pem",
+					Path: fmt.Sprintf("%s/%s/ca/%s.%s-cert.pem", orgsDir, o.Domain, o.CAHost, o.Domain),
 				},
-				URL: "grpcs://fabric_orderer:7050",
-			},
-		},
-		Organizations: map[string]*Organization{
-			"org1.example.com": {
-				CertificateAuthorities: []string{"org1.example.com"},
+				URL: fmt.Sprintf("http://%s:7054", o.CAHost),
+				Registrar: &Registrar{
+					EnrollID:     "admin",
+					EnrollSecret: "adminpw",
+				},
+			}
+			networkConfig.Organizations[o.Domain] = &Organization{
+				CertificateAuthorities: []string{o.Domain},
 				CryptoPath:             "/tmp/msp",
-				MSPID:                  "Org1MSP",
-				Peers:                  []string{"fabric_peer"},
-			},
-		},
-		Peers: map[string]*NetworkEntity{
-			"fabric_peer": {
+				MSPID:                  o.MSPID,
+				Peers:                  []string{o.PeerHost},
+			}
+			networkConfig.Peers[o.PeerHost] = &NetworkEntity{
 				TLSCACerts: &Path{
-					Path: "/etc/firefly/organizations/peerOrganizations/org1.example.com/tlsca/tlsfabric_ca.org1.example.com-cert.pem",
+					Path: fmt.Sprintf("%s/%s/tlsca/tls%s.%s-cert.pem", orgsDir, o.Domain, o.CAHost, o.Domain),
 				},
-				URL: "grpcs://fabric_peer:7051",
-			},
-		},
-		Version: "1.1.0%",
+				URL: fmt.Sprintf("grpcs://%s:7051", o.PeerHost),
+			}
+		}
+		networkConfig.Channels[c.Name] = channelConfig
 	}
+
 	networkConfigBytes, _ := yaml.Marshal(networkConfig)
 	return ioutil.WriteFile(outputPath, networkConfigBytes, 0755)
 }
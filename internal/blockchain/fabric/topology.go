@@ -0,0 +1,248 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+var topologyOrgNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+var topologyChannelNameRegex = regexp.MustCompile(`^[a-z][a-z0-9.-]*$`)
+
+// fabricOrg is a peer organization of the stack, along with the hostnames of its CA and peer
+type fabricOrg struct {
+	Name     string
+	MSPID    string
+	Domain   string
+	CAHost   string
+	PeerHost string
+	// ConnectionProfile is the filename of the connection profile used by the org's fabconnect instances
+	ConnectionProfile string
+	Users             int
+}
+
+// DefaultTopology is the layout of stacks created without a topology file: a single orderer,
+// and a single org on the "firefly" channel
+func DefaultTopology() *types.FabricTopology {
+	return &types.FabricTopology{
+		Orderers: 1,
+		Orgs:     []*types.FabricTopologyOrg{{Name: "Org1"}},
+		Channels: []*types.FabricTopologyChannel{{Name: channel, Orgs: []string{"Org1"}}},
+	}
+}
+
+// ReadTopology reads a topology file, filling in the default orderer count, orgs and channel
+// for anything that is not set in the file
+func ReadTopology(filename string, memberCount int) (*types.FabricTopology, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var topology *types.FabricTopology
+	if err := yaml.Unmarshal(b, &topology); err != nil {
+		return nil, fmt.Errorf("invalid fabric topology file %s: %s", filename, err)
+	}
+	if topology == nil {
+		topology = &types.FabricTopology{}
+	}
+	defaults := DefaultTopology()
+	if topology.Orderers == 0 {
+		topology.Orderers = defaults.Orderers
+	}
+	if len(topology.Orgs) == 0 {
+		topology.Orgs = defaults.Orgs
+	}
+	if len(topology.Channels) == 0 {
+		allOrgs := make([]string, len(topology.Orgs))
+		for i, org := range topology.Orgs {
+			allOrgs[i] = org.Name
+		}
+		topology.Channels = []*types.FabricTopologyChannel{{Name: channel, Orgs: allOrgs}}
+	}
+	if err := ValidateTopology(topology, memberCount); err != nil {
+		return nil, err
+	}
+	return topology, nil
+}
+
+// ValidateTopology checks the names in a topology, and that every org hosting a FireFly member
+// is on the FireFly channel. Members are assigned to the orgs in turn.
+func ValidateTopology(topology *types.FabricTopology, memberCount int) error {
+	if topology.Orderers < 1 {
+		return fmt.Errorf("the fabric topology must have at least one orderer")
+	}
+	if len(topology.Orgs) == 0 {
+		return fmt.Errorf("the fabric topology must have at least one org")
+	}
+	orgNames := make(map[string]bool)
+	for _, org := range topology.Orgs {
+		if !topologyOrgNameRegex.MatchString(org.Name) {
+			return fmt.Errorf("invalid fabric org name '%s'. org names must start with a letter and contain only letters and numbers", org.Name)
+		}
+		if strings.EqualFold(org.Name, "Orderer") {
+			return fmt.Errorf("the fabric org name '%s' is reserved for the orderer org", org.Name)
+		}
+		if orgNames[strings.ToLower(org.Name)] {
+			return fmt.Errorf("duplicate fabric org name '%s'", org.Name)
+		}
+		orgNames[strings.ToLower(org.Name)] = true
+	}
+
+	if len(topology.Channels) == 0 {
+		return fmt.Errorf("the fabric topology must have at least one channel")
+	}
+	channelNames := make(map[string]bool)
+	for _, c := range topology.Channels {
+		if !topologyChannelNameRegex.MatchString(c.Name) {
+			return fmt.Errorf("invalid fabric channel name '%s'. channel names must start with a lowercase letter and contain only lowercase letters, numbers, '.' and '-'", c.Name)
+		}
+		if channelNames[c.Name] {
+			return fmt.Errorf("duplicate fabric channel name '%s'", c.Name)
+		}
+		channelNames[c.Name] = true
+		if len(c.Orgs) == 0 {
+			return fmt.Errorf("fabric channel '%s' must have at least one org", c.Name)
+		}
+		for _, orgName := range c.Orgs {
+			if !hasOrgNamed(topology, orgName) {
+				return fmt.Errorf("fabric channel '%s' refers to unknown org '%s'", c.Name, orgName)
+			}
+		}
+	}
+
+	fireflyChannel := topology.Channels[0]
+	for i := 0; i < memberCount && i < len(topology.Orgs); i++ {
+		if !channelHasOrg(fireflyChannel, topology.Orgs[i].Name) {
+			return fmt.Errorf("org '%s' hosts a FireFly member, so it must be on the FireFly channel '%s'", topology.Orgs[i].Name, fireflyChannel.Name)
+		}
+	}
+	return nil
+}
+
+func hasOrgNamed(topology *types.FabricTopology, orgName string) bool {
+	for _, org := range topology.Orgs {
+		if org.Name == orgName {
+			return true
+		}
+	}
+	return false
+}
+
+func channelHasOrg(c *types.FabricTopologyChannel, orgName string) bool {
+	for _, name := range c.Orgs {
+		if name == orgName {
+			return true
+		}
+	}
+	return false
+}
+
+// getOrgs returns the peer orgs of a topology. The first org keeps the hostnames
+// used before the topology was configurable.
+func getOrgs(topology *types.FabricTopology, memberCount int) []*fabricOrg {
+	orgs := make([]*fabricOrg, len(topology.Orgs))
+	for i, org := range topology.Orgs {
+		lowerName := strings.ToLower(org.Name)
+		orgs[i] = &fabricOrg{
+			Name:              org.Name,
+			MSPID:             org.Name + "MSP",
+			Domain:            lowerName + ".example.com",
+			CAHost:            "fabric_ca",
+			PeerHost:          "fabric_peer",
+			ConnectionProfile: "ccp.yaml",
+		}
+		if i > 0 {
+			orgs[i].CAHost = "fabric_ca_" + lowerName
+			orgs[i].PeerHost = "fabric_peer_" + lowerName
+			orgs[i].ConnectionProfile = fmt.Sprintf("ccp_%s.yaml", lowerName)
+		}
+	}
+	for i := 0; i < memberCount; i++ {
+		orgs[i%len(orgs)].Users++
+	}
+	return orgs
+}
+
+func getOrdererHosts(topology *types.FabricTopology) []string {
+	hosts := make([]string, topology.Orderers)
+	for i := range hosts {
+		if i == 0 {
+			hosts[i] = "fabric_orderer"
+		} else {
+			hosts[i] = fmt.Sprintf("fabric_orderer_%d", i)
+		}
+	}
+	return hosts
+}
+
+func (p *FabricProvider) topology() *types.FabricTopology {
+	if p.stack.FabricTopology != nil {
+		return p.stack.FabricTopology
+	}
+	return DefaultTopology()
+}
+
+func (p *FabricProvider) orgs() []*fabricOrg {
+	return getOrgs(p.topology(), len(p.stack.Members))
+}
+
+// memberOrg returns the Fabric org that a FireFly member belongs to
+func (p *FabricProvider) memberOrg(member *types.Organization) *fabricOrg {
+	orgs := p.orgs()
+	index := 0
+	if member.Index != nil {
+		index = *member.Index
+	}
+	return orgs[index%len(orgs)]
+}
+
+func (p *FabricProvider) fireflyChannel() string {
+	return p.topology().Channels[0].Name
+}
+
+func (p *FabricProvider) channelOrgs(channelName string) ([]*fabricOrg, error) {
+	for _, c := range p.topology().Channels {
+		if c.Name == channelName {
+			orgs := make([]*fabricOrg, 0, len(c.Orgs))
+			for _, org := range p.orgs() {
+				if channelHasOrg(c, org.Name) {
+					orgs = append(orgs, org)
+				}
+			}
+			return orgs, nil
+		}
+	}
+	return nil, fmt.Errorf("channel '%s' is not part of the stack's fabric topology", channelName)
+}
+
+// peerEnv returns the docker arguments to run the fabric tools as the admin of an org's peer
+func peerEnv(org *fabricOrg) []string {
+	peerDir := fmt.Sprintf("/etc/firefly/organizations/peerOrganizations/%s/peers/%s.%s", org.Domain, org.PeerHost, org.Domain)
+	return []string{
+		"-e", fmt.Sprintf("CORE_PEER_ADDRESS=%s:7051", org.PeerHost),
+		"-e", "CORE_PEER_TLS_ENABLED=true",
+		"-e", fmt.Sprintf("CORE_PEER_TLS_ROOTCERT_FILE=%s/tls/ca.crt", peerDir),
+		"-e", fmt.Sprintf("CORE_PEER_LOCALMSPID=%s", org.MSPID),
+		"-e", fmt.Sprintf("CORE_PEER_MSPCONFIGPATH=/etc/firefly/organizations/peerOrganizations/%s/users/Admin@%s/msp", org.Domain, org.Domain),
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadTopology(t *testing.T) {
+	tests := []struct {
+		name        string
+		yaml        string
+		memberCount int
+		expected    *types.FabricTopology
+		err         string
+	}{
+		{
+			name:        "empty file gives the default topology",
+			yaml:        "",
+			memberCount: 1,
+			expected:    DefaultTopology(),
+		},
+		{
+			name:        "zero orderers defaults to one",
+			yaml:        "orderers: 0\norgs:\n  - name: Org1\n",
+			memberCount: 1,
+			expected:    DefaultTopology(),
+		},
+		{
+			name:        "orgs without channels are all put on the firefly channel",
+			yaml:        "orderers: 3\norgs:\n  - name: Org1\n  - name: Org2\n",
+			memberCount: 2,
+			expected: &types.FabricTopology{
+				Orderers: 3,
+				Orgs:     []*types.FabricTopologyOrg{{Name: "Org1"}, {Name: "Org2"}},
+				Channels: []*types.FabricTopologyChannel{{Name: "firefly", Orgs: []string{"Org1", "Org2"}}},
+			},
+		},
+		{
+			name:        "duplicate org names",
+			yaml:        "orgs:\n  - name: Org1\n  - name: org1\n",
+			memberCount: 1,
+			err:         "duplicate fabric org name 'org1'",
+		},
+		{
+			name:        "unknown org in a channel",
+			yaml:        "orgs:\n  - name: Org1\nchannels:\n  - name: firefly\n    orgs: [Org1, Org2]\n",
+			memberCount: 1,
+			err:         "fabric channel 'firefly' refers to unknown org 'Org2'",
+		},
+		{
+			name:        "member org missing from the firefly channel",
+			yaml:        "orgs:\n  - name: Org1\n  - name: Org2\nchannels:\n  - name: firefly\n    orgs: [Org1]\n  - name: other\n    orgs: [Org1, Org2]\n",
+			memberCount: 2,
+			err:         "org 'Org2' hosts a FireFly member, so it must be on the FireFly channel 'firefly'",
+		},
+		{
+			name:        "orgs without members may stay off the firefly channel",
+			yaml:        "orgs:\n  - name: Org1\n  - name: Org2\nchannels:\n  - name: firefly\n    orgs: [Org1]\n",
+			memberCount: 1,
+			expected: &types.FabricTopology{
+				Orderers: 1,
+				Orgs:     []*types.FabricTopologyOrg{{Name: "Org1"}, {Name: "Org2"}},
+				Channels: []*types.FabricTopologyChannel{{Name: "firefly", Orgs: []string{"Org1"}}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "topology.yaml")
+			assert.NoError(t, ioutil.WriteFile(filename, []byte(test.yaml), 0644))
+			topology, err := ReadTopology(filename, test.memberCount)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, topology)
+		})
+	}
+}
+
+func TestValidateTopology(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology *types.FabricTopology
+		err      string
+	}{
+		{
+			name:     "default topology",
+			topology: DefaultTopology(),
+		},
+		{
+			name: "zero orderers",
+			topology: &types.FabricTopology{
+				Orgs:     []*types.FabricTopologyOrg{{Name: "Org1"}},
+				Channels: []*types.FabricTopologyChannel{{Name: "firefly", Orgs: []string{"Org1"}}},
+			},
+			err: "the fabric topology must have at least one orderer",
+		},
+		{
+			name: "duplicate org names",
+			topology: &types.FabricTopology{
+				Orderers: 1,
+				Orgs:     []*types.FabricTopologyOrg{{Name: "Org1"}, {Name: "Org1"}},
+				Channels: []*types.FabricTopologyChannel{{Name: "firefly", Orgs: []string{"Org1"}}},
+			},
+			err: "duplicate fabric org name 'Org1'",
+		},
+		{
+			name: "unknown org in a channel",
+			topology: &types.FabricTopology{
+				Orderers: 1,
+				Orgs:     []*types.FabricTopologyOrg{{Name: "Org1"}},
+				Channels: []*types.FabricTopologyChannel{{Name: "firefly", Orgs: []string{"Org1"}}, {Name: "other", Orgs: []string{"Org3"}}},
+			},
+			err: "fabric channel 'other' refers to unknown org 'Org3'",
+		},
+		{
+			name: "member org missing from the firefly channel",
+			topology: &types.FabricTopology{
+				Orderers: 1,
+				Orgs:     []*types.FabricTopologyOrg{{Name: "Org1"}, {Name: "Org2"}},
+				Channels: []*types.FabricTopologyChannel{{Name: "firefly", Orgs: []string{"Org2"}}},
+			},
+			err: "org 'Org1' hosts a FireFly member, so it must be on the FireFly channel 'firefly'",
+		},
+		{
+			name: "orderer org name is reserved",
+			topology: &types.FabricTopology{
+				Orderers: 1,
+				Orgs:     []*types.FabricTopologyOrg{{Name: "Orderer"}},
+				Channels: []*types.FabricTopologyChannel{{Name: "firefly", Orgs: []string{"Orderer"}}},
+			},
+			err: "the fabric org name 'Orderer' is reserved for the orderer org",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateTopology(test.topology, 2)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetOrgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		topology    *types.FabricTopology
+		memberCount int
+		expected    []*fabricOrg
+	}{
+		{
+			name:        "default topology keeps the original hostnames",
+			topology:    DefaultTopology(),
+			memberCount: 2,
+			expected: []*fabricOrg{
+				{Name: "Org1", MSPID: "Org1MSP", Domain: "org1.example.com", CAHost: "fabric_ca", PeerHost: "fabric_peer", ConnectionProfile: "ccp.yaml", Users: 2},
+			},
+		},
+		{
+			name: "members are assigned to the orgs in turn",
+			topology: &types.FabricTopology{
+				Orderers: 1,
+				Orgs:     []*types.FabricTopologyOrg{{Name: "Org1"}, {Name: "Org2"}},
+				Channels: []*types.FabricTopologyChannel{{Name: "firefly", Orgs: []string{"Org1", "Org2"}}},
+			},
+			memberCount: 3,
+			expected: []*fabricOrg{
+				{Name: "Org1", MSPID: "Org1MSP", Domain: "org1.example.com", CAHost: "fabric_ca", PeerHost: "fabric_peer", ConnectionProfile: "ccp.yaml", Users: 2},
+				{Name: "Org2", MSPID: "Org2MSP", Domain: "org2.example.com", CAHost: "fabric_ca_org2", PeerHost: "fabric_peer_org2", ConnectionProfile: "ccp_org2.yaml", Users: 1},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, getOrgs(test.topology, test.memberCount))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package climsgs
+
+//revive:disable
+var (
+	// Prompts and confirmations
+	MsgPromptStackName   = ffm("cli.prompt.stackName", "stack name: ")
+	MsgPromptMemberCount = ffm("cli.prompt.memberCount", "number of members: ")
+	MsgPromptOrgName     = ffm("cli.prompt.orgName", "name for org %d: ")
+	MsgPromptNodeName    = ffm("cli.prompt.nodeName", "name for node %d: ")
+	MsgConfirmChoices    = ffm("cli.prompt.confirmChoices", "[y/N]")
+	MsgConfirmYes        = ffm("cli.prompt.confirmYes", "y,yes")
+	MsgConfirmDeclined   = ffm("cli.prompt.confirmDeclined", "confirmation declined with response: '%s'")
+	MsgInvalidOption     = ffm("cli.prompt.invalidOption", "'%s' is not a valid option")
+	MsgCanceled          = ffm("cli.prompt.canceled", "canceled")
+	MsgRemoveWarning     = ffm("cli.prompt.removeWarning", "WARNING: This will completely remove your stack and all of its data. Are you sure this is what you want to do?")
+	MsgRemoveConfirm     = ffm("cli.prompt.removeConfirm", "completely delete FireFly stack '%s'")
+	MsgResetWarning      = ffm("cli.prompt.resetWarning", "WARNING: This will completely remove all transactions and data from your FireFly stack. Are you sure you want to do that?")
+	MsgResetConfirm      = ffm("cli.prompt.resetConfirm", "reset all data in FireFly stack '%s'")
+
+	// Progress and results
+	MsgError               = ffm("cli.message.error", "Error: %s")
+	MsgDone                = ffm("cli.message.done", "done")
+	MsgInitializing        = ffm("cli.message.initializing", "initializing new FireFly stack...")
+	MsgStackSelected       = ffm("cli.message.stackSelected", "You selected %s")
+	MsgStackCreated        = ffm("cli.message.stackCreated", "Stack '%s' created!\nTo start your new stack run:\n\n%s start %s\n")
+	MsgComposeFileLocation = ffm("cli.message.composeFileLocation", "Your docker compose file for this stack can be found at: %s")
+	MsgFirstStart          = ffm("cli.message.firstStart", "this will take a few seconds longer since this is the first time you're running this stack...")
+	MsgWebUI               = ffm("cli.message.webUI", "Web UI for member '%v': http://127.0.0.1:%v/ui")
+	MsgSandboxUI           = ffm("cli.message.sandboxUI", "Sandbox UI for member '%v': http://127.0.0.1:%v")
+	MsgPrometheusUI        = ffm("cli.message.prometheusUI", "Web UI for shared Prometheus: http://127.0.0.1:%v")
+	MsgSeeLogs             = ffm("cli.message.seeLogs", "To see logs for your stack run:\n\n%s logs %s")
+	MsgStopping            = ffm("cli.message.stopping", "stopping stack '%s'... ")
+	MsgRemoving            = ffm("cli.message.removing", "deleting FireFly stack '%s'... ")
+	MsgResetting           = ffm("cli.message.resetting", "resetting FireFly stack '%s'... ")
+	MsgStackReset          = ffm("cli.message.stackReset", "Your stack has been reset. To start your stack run:\n\n%s start %s")
+	MsgExplainCauses       = ffm("cli.message.explainCauses", "Possible causes:")
+	MsgExplainFixes        = ffm("cli.message.explainFixes", "How to fix it:")
+	MsgExplainHint         = ffm("cli.message.explainHint", "Run '%s explain %s' for possible causes and fixes")
+
+	// Errors
+	MsgNoStackSpecified = ffm("cli.error.noStackSpecified", "no stack specified")
+	MsgUnknownErrorCode = ffm("cli.error.unknownErrorCode", "unknown error code '%s'. run '%s explain' to list all codes")
+
+	// Help text
+	MsgHelpRoot        = ffm("cli.help.root", "FireFly CLI is a developer tool used to manage local development stacks")
+	MsgHelpInit        = ffm("cli.help.init", "Create a new FireFly local dev stack")
+	MsgHelpStart       = ffm("cli.help.start", "Start a stack")
+	MsgHelpStop        = ffm("cli.help.stop", "Stop a stack")
+	MsgHelpRestart     = ffm("cli.help.restart", "Restart a stack, or only some of its services")
+	MsgHelpRemove      = ffm("cli.help.remove", "Completely remove a stack")
+	MsgHelpReset       = ffm("cli.help.reset", "Clear all data in a stack")
+	MsgHelpList        = ffm("cli.help.list", "list stacks")
+	MsgHelpInfo        = ffm("cli.help.info", "Get info about a stack")
+	MsgHelpLogs        = ffm("cli.help.logs", "View log output from a stack")
+	MsgHelpPull        = ffm("cli.help.pull", "Pull a stack")
+	MsgHelpUpgrade     = ffm("cli.help.upgrade", "Upgrade a stack")
+	MsgHelpVersion     = ffm("cli.help.version", "Prints the version info")
+	MsgHelpExplain     = ffm("cli.help.explain", "Show the causes and fixes of an error code")
+	MsgHelpAccounts    = ffm("cli.help.accounts", "Work with accounts in a FireFly stack")
+	MsgHelpDeploy      = ffm("cli.help.deploy", "Deploy a compiled smart contract")
+	MsgHelpI18n        = ffm("cli.help.i18n", "Work with the translations of the CLI")
+	MsgHelpI18nExtract = ffm("cli.help.i18nExtract", "Print the messages of the CLI as JSON for translators")
+)
@@ -0,0 +1,73 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package climsgs
+
+import "github.com/hyperledger/firefly-common/pkg/i18n"
+
+var esMessages = map[i18n.MessageKey]string{
+	MsgPromptStackName:     "nombre del stack: ",
+	MsgPromptMemberCount:   "número de miembros: ",
+	MsgPromptOrgName:       "nombre de la organización %d: ",
+	MsgPromptNodeName:      "nombre del nodo %d: ",
+	MsgConfirmChoices:      "[s/N]",
+	MsgConfirmYes:          "s,si,sí,y,yes",
+	MsgConfirmDeclined:     "confirmación rechazada con la respuesta: '%s'",
+	MsgInvalidOption:       "'%s' no es una opción válida",
+	MsgCanceled:            "cancelado",
+	MsgRemoveWarning:       "ADVERTENCIA: Esto eliminará por completo su stack y todos sus datos. ¿Está seguro de que quiere hacerlo?",
+	MsgRemoveConfirm:       "eliminar por completo el stack de FireFly '%s'",
+	MsgResetWarning:        "ADVERTENCIA: Esto eliminará por completo todas las transacciones y los datos de su stack de FireFly. ¿Está seguro de que quiere hacerlo?",
+	MsgResetConfirm:        "restablecer todos los datos del stack de FireFly '%s'",
+	MsgError:               "Error: %s",
+	MsgDone:                "listo",
+	MsgInitializing:        "inicializando un nuevo stack de FireFly...",
+	MsgStackSelected:       "Ha seleccionado %s",
+	MsgStackCreated:        "¡Stack '%s' creado!\nPara iniciar su nuevo stack ejecute:\n\n%s start %s\n",
+	MsgComposeFileLocation: "El archivo docker compose de este stack se encuentra en: %s",
+	MsgFirstStart:          "esto tardará unos segundos más porque es la primera vez que ejecuta este stack...",
+	MsgWebUI:               "Interfaz web del miembro '%v': http://127.0.0.1:%v/ui",
+	MsgSandboxUI:           "Interfaz del sandbox del miembro '%v': http://127.0.0.1:%v",
+	MsgPrometheusUI:        "Interfaz web de Prometheus compartido: http://127.0.0.1:%v",
+	MsgSeeLogs:             "Para ver los logs de su stack ejecute:\n\n%s logs %s",
+	MsgStopping:            "deteniendo el stack '%s'... ",
+	MsgRemoving:            "eliminando el stack de FireFly '%s'... ",
+	MsgResetting:           "restableciendo el stack de FireFly '%s'... ",
+	MsgStackReset:          "Su stack se ha restablecido. Para iniciar su stack ejecute:\n\n%s start %s",
+	MsgExplainCauses:       "Causas posibles:",
+	MsgExplainFixes:        "Cómo solucionarlo:",
+	MsgExplainHint:         "Ejecute '%s explain %s' para ver las causas posibles y cómo solucionarlo",
+	MsgNoStackSpecified:    "no se especificó ningún stack",
+	MsgUnknownErrorCode:    "código de error desconocido '%s'. ejecute '%s explain' para ver todos los códigos",
+	MsgHelpRoot:            "FireFly CLI es una herramienta para desarrolladores que gestiona stacks de desarrollo locales",
+	MsgHelpInit:            "Crear un nuevo stack local de desarrollo de FireFly",
+	MsgHelpStart:           "Iniciar un stack",
+	MsgHelpStop:            "Detener un stack",
+	MsgHelpRestart:         "Reiniciar un stack, o solo algunos de sus servicios",
+	MsgHelpRemove:          "Eliminar un stack por completo",
+	MsgHelpReset:           "Borrar todos los datos de un stack",
+	MsgHelpList:            "listar los stacks",
+	MsgHelpInfo:            "Obtener información sobre un stack",
+	MsgHelpLogs:            "Ver los logs de un stack",
+	MsgHelpPull:            "Descargar las imágenes de un stack",
+	MsgHelpUpgrade:         "Actualizar un stack",
+	MsgHelpVersion:         "Muestra la información de la versión",
+	MsgHelpExplain:         "Mostrar las causas y soluciones de un código de error",
+	MsgHelpAccounts:        "Trabajar con las cuentas de un stack de FireFly",
+	MsgHelpDeploy:          "Desplegar un contrato inteligente compilado",
+	MsgHelpI18n:            "Trabajar con las traducciones de la CLI",
+	MsgHelpI18nExtract:     "Mostrar los mensajes de la CLI en JSON para los traductores",
+}
@@ -0,0 +1,106 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package climsgs holds the translatable prompts, help text and messages of the CLI. English text
+// is registered in en_messages.go, and each other locale has its own <lang>_messages.go file.
+package climsgs
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"golang.org/x/text/language"
+)
+
+// LangEnvVar selects the language of the CLI, for example FF_LANG=es
+const LangEnvVar = "FF_LANG"
+
+var english = map[i18n.MessageKey]string{}
+
+// translations maps each supported language to its messages. Add new locales here.
+var translations = map[language.Tag]map[i18n.MessageKey]string{
+	language.Spanish: esMessages,
+}
+
+func ffm(key, translation string) i18n.MessageKey {
+	english[i18n.MessageKey(key)] = translation
+	return i18n.FFM(language.AmericanEnglish, key, translation)
+}
+
+func init() {
+	for lang, messages := range translations {
+		for key, translation := range messages {
+			i18n.FFM(lang, string(key), translation)
+		}
+	}
+	SetLang(os.Getenv(LangEnvVar))
+}
+
+// SetLang selects the language used by T. It accepts tags like "es" as well as POSIX style
+// locales like "es_ES.UTF-8". Languages without a translation fall back to English.
+func SetLang(lang string) {
+	lang = strings.SplitN(lang, ".", 2)[0]
+	lang = strings.ReplaceAll(lang, "_", "-")
+	if lang == "" || lang == "C" || lang == "POSIX" {
+		lang = "en"
+	}
+	i18n.SetLang(lang)
+}
+
+// T returns the message in the selected language, with the inserts formatted into it
+func T(key i18n.MessageKey, inserts ...interface{}) string {
+	return i18n.Expand(context.Background(), key, inserts...)
+}
+
+// Languages returns the languages that have translations, other than English
+func Languages() []string {
+	langs := make([]string, 0, len(translations))
+	for lang := range translations {
+		langs = append(langs, lang.String())
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Entry is a message extracted for translators
+type Entry struct {
+	Key         string `json:"key"`
+	English     string `json:"en"`
+	Translation string `json:"translation"`
+}
+
+// Extract lists every message of the CLI with its English text, and the existing translation
+// in the given language. Only the untranslated messages are returned if missingOnly is set.
+func Extract(lang string, missingOnly bool) []*Entry {
+	translated := translations[language.Make(lang)]
+	entries := make([]*Entry, 0, len(english))
+	for key, text := range english {
+		translation := translated[key]
+		if missingOnly && translation != "" {
+			continue
+		}
+		entries = append(entries, &Entry{
+			Key:         string(key),
+			English:     text,
+			Translation: translation,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
@@ -0,0 +1,57 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package climsgs
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var verbRegexp = regexp.MustCompile(`%[a-z]`)
+
+func TestTranslationsKeepInserts(t *testing.T) {
+	for lang, messages := range translations {
+		for key, translation := range messages {
+			assert.Equal(t, verbRegexp.FindAllString(english[key], -1), verbRegexp.FindAllString(translation, -1), "%s %s", lang, key)
+		}
+	}
+}
+
+func TestSetLang(t *testing.T) {
+	defer SetLang("en")
+
+	SetLang("es_ES.UTF-8")
+	assert.Equal(t, "nombre del nodo 1: ", T(MsgPromptNodeName, 1))
+	assert.Equal(t, "Trabajar con las traducciones de la CLI", T(MsgHelpI18n))
+
+	SetLang("fr")
+	assert.Equal(t, "name for node 1: ", T(MsgPromptNodeName, 1))
+
+	SetLang("")
+	assert.Equal(t, "stack name: ", T(MsgPromptStackName))
+}
+
+func TestExtractMissing(t *testing.T) {
+	for _, e := range Extract("es", true) {
+		assert.Empty(t, e.Translation)
+		assert.NotEmpty(t, e.English)
+	}
+	assert.Len(t, Extract("es", false), len(english))
+	assert.Len(t, Extract("fr", true), len(english))
+}
@@ -23,9 +23,29 @@ import (
 
 var homeDir, _ = os.UserHomeDir()
 var StacksDir = filepath.Join(homeDir, ".firefly", "stacks")
+var PluginsDir = filepath.Join(homeDir, ".firefly", "plugins")
+var StatsFile = filepath.Join(homeDir, ".firefly", "stats.jsonl")
+var CurrentStackFile = filepath.Join(homeDir, ".firefly", "current_stack")
+
+// WorkspaceFileName is the file in a project directory, or one of its parents, that names the stack
+// commands default to when run in that directory
+const WorkspaceFileName = ".firefly"
 
 var FireFlyCoreImageName = "ghcr.io/hyperledger/firefly"
 var IPFSImageName = "ipfs/go-ipfs:v0.10.0"
 var PostgresImageName = "postgres"
 var PrometheusImageName = "prom/prometheus"
 var SandboxImageName = "ghcr.io/hyperledger/firefly-sandbox:latest"
+var NetemImageName = "nicolaka/netshoot"
+var FaketimeImageName = "alpine:3.18"
+var NATSImageName = "nats:2.9-alpine"
+var RedisImageName = "redis:7-alpine"
+var RPCProxyImageName = "openresty/openresty:1.21.4.1-alpine"
+
+// The username, and the path inside the FireFly core container of the password file,
+// used when basic auth is enabled on the FireFly API
+var APIAuthUsername = "firefly"
+var APIPasswordFile = "/etc/firefly/api_users"
+
+// The directory inside the FireFly core container that holds the password file of each demo tenant
+var TenantPasswordDir = "/etc/firefly/tenants"
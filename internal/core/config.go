@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path"
+	"path/filepath"
 
+	"github.com/hyperledger/firefly-cli/internal/constants"
 	"github.com/hyperledger/firefly-cli/pkg/types"
 	"github.com/miracl/conflate"
 	"gopkg.in/yaml.v2"
@@ -69,29 +71,46 @@ func NewFireflyConfig(stack *types.Stack, member *types.Organization) *types.Fir
 		Plugins: &types.Plugins{},
 	}
 
-	memberConfig.Plugins.SharedStorage = []*types.SharedStorageConfig{
-		{
-			Type: "ipfs",
-			Name: "sharedstorage0",
-			IPFS: &types.FireflyIPFSConfig{
-				API: &types.HttpEndpointConfig{
-					URL: getIPFSAPIURL(member),
-				},
-				Gateway: &types.HttpEndpointConfig{
-					URL: getIPFSGatewayURL(member),
+	if stack.APIToken(member) != "" {
+		memberConfig.HTTP.Auth = &types.HttpAuthConfig{
+			Type: "basic",
+			Basic: &types.BasicAuthConfig{
+				PasswordFile: constants.APIPasswordFile,
+			},
+		}
+		// A member running outside docker reads the password file straight from the runtime directory
+		if member.External {
+			memberConfig.HTTP.Auth.Basic.PasswordFile = filepath.Join(stack.RuntimeDir, "config", stack.APIPasswordFileName(member))
+		}
+	}
+
+	if !stack.DisableIPFS {
+		memberConfig.Plugins.SharedStorage = []*types.SharedStorageConfig{
+			{
+				Type: "ipfs",
+				Name: "sharedstorage0",
+				IPFS: &types.FireflyIPFSConfig{
+					API: &types.HttpEndpointConfig{
+						URL: getIPFSAPIURL(member),
+					},
+					Gateway: &types.HttpEndpointConfig{
+						URL: getIPFSGatewayURL(member),
+					},
 				},
 			},
-		},
+		}
 	}
 
-	memberConfig.Plugins.DataExchange = []*types.DataExchangeConfig{
-		{
-			Type: "ffdx",
-			Name: "dataexchange0",
-			FFDX: &types.HttpEndpointConfig{
-				URL: getDataExchangeURL(member),
+	if !stack.DisableDataExchange {
+		memberConfig.Plugins.DataExchange = []*types.DataExchangeConfig{
+			{
+				Type: "ffdx",
+				Name: "dataexchange0",
+				FFDX: &types.HttpEndpointConfig{
+					URL: getDataExchangeURL(member),
+				},
 			},
-		},
+		}
 	}
 
 	if stack.PrometheusEnabled {
@@ -189,16 +208,16 @@ func ReadFireflyConfig(filePath string) (*types.FireflyConfig, error) {
 	}
 }
 
-func WriteFireflyConfig(config *types.FireflyConfig, filePath, extraCoreConfigPath string) error {
-	if bytes, err := yaml.Marshal(config); err != nil {
+func WriteFireflyConfig(config *types.FireflyConfig, filePath string, extraCoreConfig []byte) error {
+	configBytes, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filePath, configBytes, 0755); err != nil {
 		return err
-	} else {
-		if err := ioutil.WriteFile(filePath, bytes, 0755); err != nil {
-			return err
-		}
 	}
-	if extraCoreConfigPath != "" {
-		c, err := conflate.FromFiles(filePath, extraCoreConfigPath)
+	if len(extraCoreConfig) > 0 {
+		c, err := conflate.FromData(configBytes, extraCoreConfig)
 		if err != nil {
 			return err
 		}
@@ -24,26 +24,47 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/hyperledger/firefly-cli/internal/log"
 )
 
 var requestTimeout int = -1
+var basicAuthUsername, basicAuthPassword string
+var basicAuthByPort = map[string][2]string{}
 
 func SetRequestTimeout(customRequestTimeoutSecs int) {
 	requestTimeout = customRequestTimeoutSecs
 }
 
+// SetBasicAuth sets the credentials sent with every request, for stacks that have auth enabled on the FireFly API
+func SetBasicAuth(username, password string) {
+	basicAuthUsername = username
+	basicAuthPassword = password
+}
+
+// SetBasicAuthForPort sets the credentials sent with requests to one port, for members that have their own token
+func SetBasicAuthForPort(port int, username, password string) {
+	basicAuthByPort[fmt.Sprint(port)] = [2]string{username, password}
+}
+
+// basicAuthFor returns the credentials to send to a URL. The port identifies the member, so a
+// member with its own token gets that, and everything else gets the credentials set by SetBasicAuth.
+func basicAuthFor(u *url.URL) (username, password string, ok bool) {
+	if creds, found := basicAuthByPort[u.Port()]; found {
+		return creds[0], creds[1], true
+	}
+	return basicAuthUsername, basicAuthPassword, basicAuthUsername != ""
+}
+
 func RequestWithRetry(ctx context.Context, method, url string, body, result interface{}) (err error) {
-	verbose := log.VerbosityFromContext(ctx)
+	l := log.LoggerFromContext(ctx)
 	retries := 30
 	for {
 		if err := request(method, url, body, result); err != nil {
 			if retries > 0 {
-				if verbose {
-					fmt.Printf("%s - retrying request...", err.Error())
-				}
+				l.Debug(fmt.Sprintf("%s - retrying request", err))
 				retries--
 				time.Sleep(1 * time.Second)
 			} else {
@@ -55,6 +76,11 @@ func RequestWithRetry(ctx context.Context, method, url string, body, result inte
 	}
 }
 
+// Request makes a single attempt at a request, for callers that poll and would rather report an error than wait
+func Request(method, url string, body, result interface{}) error {
+	return request(method, url, body, result)
+}
+
 func request(method, url string, body, result interface{}) (err error) {
 	if body == nil {
 		body = make(map[string]interface{})
@@ -77,6 +103,9 @@ func request(method, url string, body, result interface{}) (err error) {
 		req.Header.Set("Request-Timeout", fmt.Sprintf("%d", requestTimeout))
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if username, password, ok := basicAuthFor(req.URL); ok {
+		req.SetBasicAuth(username, password)
+	}
 	if err != nil {
 		return err
 	}
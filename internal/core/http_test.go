@@ -0,0 +1,46 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasicAuthFor(t *testing.T) {
+	defer func() {
+		basicAuthUsername, basicAuthPassword = "", ""
+		basicAuthByPort = map[string][2]string{}
+	}()
+
+	u, _ := url.Parse("ws://127.0.0.1:5000/ws")
+	_, _, ok := basicAuthFor(u)
+	assert.False(t, ok)
+
+	SetBasicAuth("firefly", "shared")
+	SetBasicAuthForPort(5001, "firefly", "member1")
+	username, password, ok := basicAuthFor(u)
+	assert.True(t, ok)
+	assert.Equal(t, "firefly", username)
+	assert.Equal(t, "shared", password)
+
+	u, _ = url.Parse("ws://127.0.0.1:5001/ws")
+	_, password, _ = basicAuthFor(u)
+	assert.Equal(t, "member1", password)
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"strings"
 
 	"github.com/hyperledger/firefly-cli/internal/constants"
 	"github.com/hyperledger/firefly-cli/internal/docker"
@@ -28,6 +29,9 @@ import (
 )
 
 func GetManifestForReleaseChannel(releaseChannel fftypes.FFEnum) (*types.VersionManifest, error) {
+	if releaseChannel == types.ReleaseChannelHead {
+		return GetHeadManifest()
+	}
 	dockerTag := releaseChannel.String()
 	if releaseChannel == types.ReleaseChannelStable {
 		dockerTag = "latest"
@@ -60,6 +64,59 @@ func GetManifestForReleaseChannel(releaseChannel fftypes.FFEnum) (*types.Version
 	return manifest, nil
 }
 
+// GetHeadManifest returns a manifest that tracks the main branch of every microservice. The image names
+// come from the manifest on the main branch of FireFly, and each image uses the "head" tag that is built
+// from main, resolved to the digest it points at now so the stack does not change under the developer.
+func GetHeadManifest() (*types.VersionManifest, error) {
+	manifest, err := GetReleaseManifest("main")
+	if err != nil {
+		return nil, err
+	}
+	if manifest.FireFly == nil {
+		manifest.FireFly = &types.ManifestEntry{Image: constants.FireFlyCoreImageName}
+	}
+	for _, entry := range manifest.Entries() {
+		if entry != nil {
+			entry.Tag = "head"
+		}
+	}
+	if _, err := ResolveDigests(manifest, docker.GetImageDigest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// ResolveDigests points each entry of the manifest that has a tag at the digest the tag currently
+// resolves to, and returns a description of each entry that changed. Local images are left alone.
+func ResolveDigests(manifest *types.VersionManifest, resolve func(image string) (string, error)) ([]string, error) {
+	changes := []string{}
+	for _, entry := range manifest.Entries() {
+		if entry == nil || entry.Local || entry.Tag == "" {
+			continue
+		}
+		digest, err := resolve(fmt.Sprintf("%s:%s", entry.Image, entry.Tag))
+		if err != nil {
+			return nil, err
+		}
+		sha := strings.TrimPrefix(digest, "sha256:")
+		if sha != entry.SHA {
+			changes = append(changes, fmt.Sprintf("%s:%s %s -> %s", entry.Image, entry.Tag, shortSHA(entry.SHA), shortSHA(sha)))
+			entry.SHA = sha
+		}
+	}
+	return changes, nil
+}
+
+func shortSHA(sha string) string {
+	if sha == "" {
+		return "(none)"
+	}
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
+
 func GetReleaseManifest(version string) (*types.VersionManifest, error) {
 	manifest := &types.VersionManifest{}
 	if err := request("GET", fmt.Sprintf("https://raw.githubusercontent.com/hyperledger/firefly/%s/manifest.json", version), nil, &manifest); err != nil {
@@ -46,3 +46,24 @@ func TestGetLatestReleaseManifest(T *testing.T) {
 	assert.NotNil(T, manifest.TokensERC1155)
 	assert.NotNil(T, manifest.TokensERC20ERC721)
 }
+
+func TestResolveDigests(T *testing.T) {
+	manifest := &types.VersionManifest{
+		FireFly:      &types.ManifestEntry{Image: "ghcr.io/hyperledger/firefly", Tag: "head", SHA: "aaaa"},
+		Ethconnect:   &types.ManifestEntry{Image: "ghcr.io/hyperledger/firefly-ethconnect", Tag: "head", SHA: "bbbb"},
+		DataExchange: &types.ManifestEntry{Image: "dx", Local: true},
+	}
+	resolved := []string{}
+	changes, err := ResolveDigests(manifest, func(image string) (string, error) {
+		resolved = append(resolved, image)
+		if image == "ghcr.io/hyperledger/firefly:head" {
+			return "sha256:cccc", nil
+		}
+		return "sha256:bbbb", nil
+	})
+	assert.NoError(T, err)
+	assert.Equal(T, []string{"ghcr.io/hyperledger/firefly:head", "ghcr.io/hyperledger/firefly-ethconnect:head"}, resolved)
+	assert.Equal(T, []string{"ghcr.io/hyperledger/firefly:head aaaa -> cccc"}, changes)
+	assert.Equal(T, "cccc", manifest.FireFly.SHA)
+	assert.Equal(T, "bbbb", manifest.Ethconnect.SHA)
+}
@@ -0,0 +1,64 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+// ConfigTemplateData is the data available to Go template placeholders in extra config files,
+// for example {{ .Stack.Name }} or {{ .Member.Index }}
+type ConfigTemplateData struct {
+	Stack  *types.Stack
+	Member *types.Organization
+}
+
+var configTemplateFuncs = template.FuncMap{
+	"env": os.Getenv,
+}
+
+// ReadExtraConfig reads an extra config file and renders it as a Go template for the given member.
+// If no path is set, nil is returned.
+func ReadExtraConfig(filePath string, stack *types.Stack, member *types.Organization) ([]byte, error) {
+	if filePath == "" {
+		return nil, nil
+	}
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return RenderConfigTemplate(filepath.Base(filePath), b, &ConfigTemplateData{Stack: stack, Member: member})
+}
+
+func RenderConfigTemplate(name string, content []byte, data *ConfigTemplateData) ([]byte, error) {
+	t, err := template.New(name).Funcs(configTemplateFuncs).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config template '%s': %s", name, err)
+	}
+	buf := &bytes.Buffer{}
+	if err := t.Execute(buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render config template '%s': %s", name, err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,44 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderConfigTemplate(T *testing.T) {
+	os.Setenv("FF_TEST_API_KEY", "abc123")
+	defer os.Unsetenv("FF_TEST_API_KEY")
+	index := 1
+	data := &ConfigTemplateData{
+		Stack:  &types.Stack{Name: "dev"},
+		Member: &types.Organization{ID: "1", Index: &index},
+	}
+	b, err := RenderConfigTemplate("test.yml", []byte(`name: {{ .Stack.Name }}_{{ .Member.Index }}
+key: {{ env "FF_TEST_API_KEY" }}`), data)
+	assert.NoError(T, err)
+	assert.Equal(T, "name: dev_1\nkey: abc123", string(b))
+}
+
+func TestRenderConfigTemplateBadField(T *testing.T) {
+	_, err := RenderConfigTemplate("test.yml", []byte(`{{ .Stack.Missing }}`), &ConfigTemplateData{Stack: &types.Stack{}})
+	assert.Regexp(T, "failed to render config template 'test.yml'", err)
+}
@@ -0,0 +1,109 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"golang.org/x/net/websocket"
+)
+
+type WebSocketStartMessage struct {
+	Type      string                 `json:"type"`
+	Namespace string                 `json:"namespace"`
+	Ephemeral bool                   `json:"ephemeral"`
+	AutoAck   bool                   `json:"autoack"`
+	Options   map[string]interface{} `json:"options,omitempty"`
+}
+
+type WebSocketEvent struct {
+	ID    string `json:"id,omitempty"`
+	Type  string `json:"type,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// CheckWebSocket connects to the FireFly WebSocket endpoint and starts an ephemeral subscription that
+// replays the namespace from its oldest event. When the namespace has events, one of them must be
+// delivered before the timeout. Replaying events creates nothing in FireFly, so the check can run as
+// often as needed. A namespace without events has nothing to deliver, so then the subscription only
+// has to be accepted without an error.
+func CheckWebSocket(ctx context.Context, wsURL, namespace string, hasEvents bool, timeout time.Duration) error {
+	conn, err := dialWebSocketWithRetry(ctx, wsURL)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	start := &WebSocketStartMessage{
+		Type:      "start",
+		Namespace: namespace,
+		Ephemeral: true,
+		AutoAck:   true,
+		Options:   map[string]interface{}{"firstEvent": "oldest"},
+	}
+	if err := websocket.JSON.Send(conn, start); err != nil {
+		return fmt.Errorf("failed to start subscription on websocket %s: %s", wsURL, err)
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	var event WebSocketEvent
+	if err := websocket.JSON.Receive(conn, &event); err != nil {
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			if !hasEvents {
+				return nil
+			}
+			return fmt.Errorf("no event received on websocket %s within %s", wsURL, timeout)
+		}
+		return fmt.Errorf("no event received on websocket %s: %s", wsURL, err)
+	}
+	if event.Type == "protocol_error" {
+		return fmt.Errorf("websocket %s returned an error: %s", wsURL, event.Error)
+	}
+	return nil
+}
+
+func dialWebSocketWithRetry(ctx context.Context, wsURL string) (*websocket.Conn, error) {
+	l := log.LoggerFromContext(ctx)
+	retries := 30
+	config, err := websocket.NewConfig(wsURL, "http://localhost/")
+	if err != nil {
+		return nil, err
+	}
+	if username, password, ok := basicAuthFor(config.Location); ok {
+		auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+		config.Header.Set("Authorization", "Basic "+auth)
+	}
+	for {
+		conn, err := websocket.DialConfig(config)
+		if err == nil {
+			return conn, nil
+		}
+		if retries == 0 {
+			return nil, fmt.Errorf("failed to connect to websocket %s: %s", wsURL, err)
+		}
+		l.Debug(fmt.Sprintf("%s - retrying websocket connection", err))
+		retries--
+		time.Sleep(1 * time.Second)
+	}
+}
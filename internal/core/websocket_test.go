@@ -0,0 +1,55 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+func TestCheckWebSocket(t *testing.T) {
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		var start WebSocketStartMessage
+		if err := websocket.JSON.Receive(conn, &start); err != nil {
+			return
+		}
+		// The default namespace has one recorded event, which is replayed to a subscription from the oldest
+		if start.Namespace == "default" && start.Options["firstEvent"] == "oldest" {
+			_ = websocket.JSON.Send(conn, &WebSocketEvent{ID: "1", Type: "identity_confirmed"})
+		}
+		time.Sleep(500 * time.Millisecond)
+	}))
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	ctx := log.WithLogger(context.Background(), &log.StdoutLogger{LogLevel: log.Error})
+
+	assert.NoError(t, CheckWebSocket(ctx, wsURL, "default", true, 3*time.Second))
+
+	// A namespace with events whose subscription delivers nothing fails
+	err := CheckWebSocket(ctx, wsURL, "empty", true, 300*time.Millisecond)
+	assert.Regexp(t, "no event received", err)
+
+	// A namespace without events only needs the subscription to be accepted
+	assert.NoError(t, CheckWebSocket(ctx, wsURL, "empty", false, 300*time.Millisecond))
+}
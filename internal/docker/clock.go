@@ -0,0 +1,148 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+// The path libfaketime is copied to in the faketime volume, and mounted at in skewed services
+const faketimeLibrary = "/faketime/libfaketime.so.1"
+
+// skewedServices are the member services that use the libc clock, so libfaketime can skew them.
+// FireFly core and the blockchain connectors are Go programs which read the clock without libc.
+var skewedServices = []string{"dataexchange", "tokens", "sandbox"}
+
+func ValidateTimezone(timezone string) error {
+	if timezone == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("unknown timezone '%s'. use an IANA name such as Europe/London or America/New_York", timezone)
+	}
+	return nil
+}
+
+// ParseClockSkew parses clock skews in the format <member index>=<seconds>, where seconds may be negative
+func ParseClockSkew(input []string) (map[string]int, error) {
+	skew := make(map[string]int, len(input))
+	for _, s := range input {
+		parts := strings.SplitN(s, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid clock skew '%s'. clock skews must be in the format <member index>=<seconds>", s)
+		}
+		index, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+		if err != nil || index < 0 {
+			return nil, fmt.Errorf("invalid clock skew '%s'. '%s' is not a member index", s, parts[0])
+		}
+		seconds, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid clock skew '%s'. '%s' is not a number of seconds", s, parts[1])
+		}
+		skew[fmt.Sprint(index)] = seconds
+	}
+	return skew, nil
+}
+
+// ApplyTimezone sets the timezone of every service of the stack
+func ApplyTimezone(compose *DockerComposeConfig, s *types.Stack) {
+	if s.Timezone == "" {
+		return
+	}
+	for _, service := range compose.Services {
+		service.Environment = copyEnvironment(service.Environment)
+		service.Environment["TZ"] = s.Timezone
+	}
+}
+
+// ApplyClockSkew adds a faketime service, which copies libfaketime into a volume, and preloads it in
+// the services of each member with a clock skew so their clock runs the given number of seconds off
+func ApplyClockSkew(compose *DockerComposeConfig, s *types.Stack) {
+	if len(s.ClockSkew) == 0 {
+		return
+	}
+	compose.Services["faketime"] = &Service{
+		Image:         constants.FaketimeImageName,
+		ContainerName: fmt.Sprintf("%s_faketime", s.ResourcePrefix()),
+		EntryPoint: []string{"/bin/sh", "-c", strings.Join([]string{
+			"set -e",
+			"apk add --no-cache libfaketime",
+			fmt.Sprintf("cp /usr/lib/faketime/libfaketime.so.1 %s", faketimeLibrary),
+			"exec sleep infinity",
+		}, "\n")},
+		Volumes: []string{"faketime:/faketime"},
+		HealthCheck: &HealthCheck{
+			Test:     []string{"CMD", "test", "-f", faketimeLibrary},
+			Interval: "2s",
+			Timeout:  "5s",
+			Retries:  30,
+		},
+		Logging:  StandardLogOptions,
+		Profiles: []string{ProfileCore},
+	}
+	compose.Volumes["faketime"] = &Volume{}
+
+	for _, member := range s.Members {
+		seconds, ok := s.ClockSkew[member.ID]
+		if !ok {
+			continue
+		}
+		for _, serviceName := range memberServices(compose, member.ID, skewedServices) {
+			service := compose.Services[serviceName]
+			service.Environment = copyEnvironment(service.Environment)
+			service.Environment["LD_PRELOAD"] = faketimeLibrary
+			service.Environment["FAKETIME"] = fmt.Sprintf("%+d", seconds)
+			service.Environment["FAKETIME_DONT_FAKE_MONOTONIC"] = "1"
+			service.Volumes = append(append([]string{}, service.Volumes...), "faketime:/faketime:ro")
+			if service.DependsOn == nil {
+				service.DependsOn = map[string]map[string]string{}
+			}
+			service.DependsOn["faketime"] = map[string]string{"condition": "service_healthy"}
+		}
+	}
+}
+
+// memberServices returns the names of the services of a member with one of the given types, which are
+// named <type>_<member id> or, where a member has more than one, <type>_<member id>_<n>
+func memberServices(compose *DockerComposeConfig, memberID string, serviceTypes []string) []string {
+	names := []string{}
+	for name := range compose.Services {
+		for _, serviceType := range serviceTypes {
+			prefix := fmt.Sprintf("%s_%s", serviceType, memberID)
+			if name == prefix || strings.HasPrefix(name, prefix+"_") {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func copyEnvironment(env map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(env)+1)
+	for k, v := range env {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,102 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTimezone(T *testing.T) {
+	assert.NoError(T, ValidateTimezone(""))
+	assert.NoError(T, ValidateTimezone("Asia/Kolkata"))
+	assert.Regexp(T, "unknown timezone 'Mars/Olympus'", ValidateTimezone("Mars/Olympus"))
+}
+
+func TestParseClockSkew(T *testing.T) {
+	skew, err := ParseClockSkew([]string{"1=+90", "2=-30"})
+	assert.NoError(T, err)
+	assert.Equal(T, map[string]int{"1": 90, "2": -30}, skew)
+
+	_, err = ParseClockSkew([]string{"1"})
+	assert.Regexp(T, "format <member index>=<seconds>", err)
+	_, err = ParseClockSkew([]string{"org_1=90"})
+	assert.Regexp(T, "'org_1' is not a member index", err)
+	_, err = ParseClockSkew([]string{"1=90s"})
+	assert.Regexp(T, "'90s' is not a number of seconds", err)
+}
+
+func TestApplyTimezone(T *testing.T) {
+	env := map[string]interface{}{"NODE_ENV": "production"}
+	compose := &DockerComposeConfig{
+		Services: map[string]*Service{
+			"firefly_core_0": {},
+			"dataexchange_0": {Environment: env},
+		},
+	}
+	ApplyTimezone(compose, &types.Stack{Timezone: "Asia/Kolkata"})
+	assert.Equal(T, "Asia/Kolkata", compose.Services["firefly_core_0"].Environment["TZ"])
+	assert.Equal(T, "Asia/Kolkata", compose.Services["dataexchange_0"].Environment["TZ"])
+	assert.Equal(T, "production", compose.Services["dataexchange_0"].Environment["NODE_ENV"])
+	assert.NotContains(T, env, "TZ")
+}
+
+func TestApplyClockSkew(T *testing.T) {
+	index0, index1 := 0, 1
+	compose := &DockerComposeConfig{
+		Services: map[string]*Service{
+			"firefly_core_1": {},
+			"dataexchange_0": {},
+			"dataexchange_1": {Volumes: []string{"dataexchange_1:/data"}},
+			"tokens_1_0":     {},
+			"tokens_1_1":     {},
+			"tokens_10_0":    {},
+			"sandbox_1":      {},
+			"evmconnect_1":   {},
+			"latency_ipfs_1": {},
+		},
+		Volumes: map[string]*Volume{},
+	}
+	stack := &types.Stack{
+		Name:      "skewed",
+		Members:   []*types.Organization{{ID: "0", Index: &index0}, {ID: "1", Index: &index1}},
+		ClockSkew: map[string]int{"1": -45},
+	}
+	ApplyClockSkew(compose, stack)
+
+	assert.Contains(T, compose.Services, "faketime")
+	assert.Contains(T, compose.Volumes, "faketime")
+	for _, name := range []string{"dataexchange_1", "tokens_1_0", "tokens_1_1", "sandbox_1"} {
+		service := compose.Services[name]
+		assert.Equal(T, "-45", service.Environment["FAKETIME"], name)
+		assert.Equal(T, "/faketime/libfaketime.so.1", service.Environment["LD_PRELOAD"], name)
+		assert.Contains(T, service.Volumes, "faketime:/faketime:ro", name)
+		assert.Equal(T, "service_healthy", service.DependsOn["faketime"]["condition"], name)
+	}
+	assert.Equal(T, []string{"dataexchange_1:/data", "faketime:/faketime:ro"}, compose.Services["dataexchange_1"].Volumes)
+	for _, name := range []string{"firefly_core_1", "dataexchange_0", "tokens_10_0", "evmconnect_1", "latency_ipfs_1"} {
+		assert.Nil(T, compose.Services[name].Environment, name)
+	}
+}
+
+func TestApplyClockSkewNone(T *testing.T) {
+	compose := &DockerComposeConfig{Services: map[string]*Service{}, Volumes: map[string]*Volume{}}
+	ApplyClockSkew(compose, &types.Stack{})
+	assert.Empty(T, compose.Services)
+}
@@ -0,0 +1,172 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/firefly-cli/internal/errcodes"
+	"github.com/hyperledger/firefly-cli/internal/log"
+)
+
+// legacyComposeFileVersion is written to compose files for docker-compose 1.x, which reads a file
+// without a version as the original v1 format. Compose v2 ignores the version.
+const legacyComposeFileVersion = "2.1"
+
+// ComposeCLI is the docker compose command found on this machine
+type ComposeCLI struct {
+	Command []string
+	Version string
+	Major   int
+	Minor   int
+}
+
+// IsV2 returns whether this is Compose v2 or later, either as the docker plugin or as a standalone binary
+func (c *ComposeCLI) IsV2() bool {
+	return c.Major >= 2
+}
+
+// FileVersion returns the version to write at the top of compose files, or "" to leave it out
+func (c *ComposeCLI) FileVersion() string {
+	if c.IsV2() {
+		return ""
+	}
+	return legacyComposeFileVersion
+}
+
+var composeVersionRegex = regexp.MustCompile(`v?(\d+)\.(\d+)\.\d+`)
+
+func parseComposeVersion(command []string, output string) (*ComposeCLI, error) {
+	match := composeVersionRegex.FindStringSubmatch(output)
+	if match == nil {
+		return nil, fmt.Errorf("unable to read the version of '%s' from '%s'", strings.Join(command, " "), strings.TrimSpace(output))
+	}
+	cli := &ComposeCLI{Command: command, Version: match[0]}
+	cli.Major, _ = strconv.Atoi(match[1])
+	cli.Minor, _ = strconv.Atoi(match[2])
+	return cli, nil
+}
+
+// composeCandidates are tried in order. The v2 plugin is preferred over a standalone docker-compose.
+var composeCandidates = [][]string{
+	{"docker", "compose"},
+	{"docker-compose"},
+}
+
+var detectedCompose *ComposeCLI
+var detectComposeErr error
+var detectComposeOnce sync.Once
+
+// DetectCompose finds the docker compose command on this machine. The result is cached.
+func DetectCompose() (*ComposeCLI, error) {
+	detectComposeOnce.Do(func() {
+		detectedCompose, detectComposeErr = detectCompose()
+	})
+	return detectedCompose, detectComposeErr
+}
+
+func detectCompose() (*ComposeCLI, error) {
+	for _, command := range composeCandidates {
+		args := append(append([]string{}, command[1:]...), "version")
+		output, err := exec.Command(command[0], args...).CombinedOutput()
+		if err != nil {
+			log.LogFile().Debug(fmt.Sprintf("%s version: %s %s", strings.Join(command, " "), err, output))
+			continue
+		}
+		cli, err := parseComposeVersion(command, string(output))
+		if err != nil {
+			log.LogFile().Debug(err.Error())
+			continue
+		}
+		log.LogFile().Debug(fmt.Sprintf("using '%s' version %s", strings.Join(cli.Command, " "), cli.Version))
+		return cli, nil
+	}
+	return nil, errcodes.New(errcodes.DockerComposeNotInstalled, "neither 'docker compose' nor 'docker-compose' was found. Install the Docker Compose plugin from https://docs.docker.com/compose/install/")
+}
+
+func checkComposeVersion(cli *ComposeCLI) error {
+	// Every service is assigned to a profile, which needs docker-compose 1.28 or later
+	if !cli.IsV2() && (cli.Major < 1 || cli.Major == 1 && cli.Minor < 28) {
+		return errcodes.New(errcodes.DockerComposeNotInstalled, "docker-compose %s is too old. Install the Docker Compose plugin from https://docs.docker.com/compose/install/, or docker-compose 1.28 or later", cli.Version)
+	}
+	return nil
+}
+
+// ComposeFileVersion returns the version to write at the top of compose files for the compose
+// command on this machine. If none is found, the version is left out as Compose v2 expects.
+func ComposeFileVersion() string {
+	cli, err := DetectCompose()
+	if err != nil {
+		return ""
+	}
+	return cli.FileVersion()
+}
+
+func composeCommand(command ...string) *exec.Cmd {
+	base := []string{"docker", "compose"}
+	if cli, err := DetectCompose(); err == nil {
+		base = cli.Command
+	}
+	return exec.Command(base[0], append(append([]string{}, base[1:]...), command...)...)
+}
+
+var composeVersionLineRegex = regexp.MustCompile(`(?m)^version:.*\n?`)
+var composeLeadingCommentsRegex = regexp.MustCompile(`^(#.*\n)*`)
+
+// setComposeFileVersion sets the top level version of a compose file, or removes it if version is "".
+// Only the version line is touched, so the rest of a hand edited override file is kept as it is.
+func setComposeFileVersion(content, version string) string {
+	content = composeVersionLineRegex.ReplaceAllString(content, "")
+	if version == "" {
+		if strings.TrimSpace(content[len(composeLeadingCommentsRegex.FindString(content)):]) == "" {
+			// An override file with only a version in it would be left empty, which is not valid
+			content += "{}\n"
+		}
+		return content
+	}
+	comments := composeLeadingCommentsRegex.FindString(content)
+	body := content[len(comments):]
+	if strings.TrimSpace(body) == "{}" {
+		body = ""
+	}
+	return comments + fmt.Sprintf("version: %q\n", version) + body
+}
+
+// FixComposeFileVersion updates the version of an existing compose file to suit the compose command
+// on this machine, for stacks created on a machine with the other major version of compose.
+func FixComposeFileVersion(filename string) error {
+	b, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	content := string(b)
+	fixed := setComposeFileVersion(content, ComposeFileVersion())
+	if fixed == content {
+		return nil
+	}
+	log.LogFile().Debug(fmt.Sprintf("updating the version of %s for this version of docker compose", filename))
+	return ioutil.WriteFile(filename, []byte(fixed), 0755)
+}
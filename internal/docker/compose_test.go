@@ -0,0 +1,57 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseComposeVersion(T *testing.T) {
+	cli, err := parseComposeVersion([]string{"docker", "compose"}, "Docker Compose version v2.20.2-desktop.1\n")
+	assert.NoError(T, err)
+	assert.Equal(T, "v2.20.2", cli.Version)
+	assert.True(T, cli.IsV2())
+	assert.Equal(T, "", cli.FileVersion())
+	assert.NoError(T, checkComposeVersion(cli))
+
+	cli, err = parseComposeVersion([]string{"docker-compose"}, "docker-compose version 1.29.2, build 5becea4c\n")
+	assert.NoError(T, err)
+	assert.False(T, cli.IsV2())
+	assert.Equal(T, "2.1", cli.FileVersion())
+	assert.NoError(T, checkComposeVersion(cli))
+
+	cli, err = parseComposeVersion([]string{"docker-compose"}, "docker-compose version 1.25.0, build unknown\n")
+	assert.NoError(T, err)
+	assert.Regexp(T, "FF-CLI-0002.*1.25.0 is too old", checkComposeVersion(cli))
+
+	_, err = parseComposeVersion([]string{"docker-compose"}, "command not found")
+	assert.Error(T, err)
+}
+
+func TestSetComposeFileVersion(T *testing.T) {
+	legacy := "# This file is generated - DO NOT EDIT!\nversion: \"2.1\"\nservices:\n    geth: {}\n"
+	v2 := "# This file is generated - DO NOT EDIT!\nservices:\n    geth: {}\n"
+	assert.Equal(T, v2, setComposeFileVersion(legacy, ""))
+	assert.Equal(T, legacy, setComposeFileVersion(v2, "2.1"))
+	assert.Equal(T, legacy, setComposeFileVersion(legacy, "2.1"))
+
+	override := "# Add custom config overrides here\nversion: \"2.1\"\n"
+	assert.Equal(T, "# Add custom config overrides here\n{}\n", setComposeFileVersion(override, ""))
+	assert.Equal(T, override, setComposeFileVersion("# Add custom config overrides here\n{}\n", "2.1"))
+}
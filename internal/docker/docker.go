@@ -27,9 +27,23 @@ import (
 	"strings"
 
 	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/hyperledger/firefly-cli/internal/errcodes"
 	"github.com/hyperledger/firefly-cli/internal/log"
 )
 
+type ctxDryRunKey struct{}
+
+// WithDryRun returns a context which causes docker and docker-compose commands
+// to be printed rather than executed
+func WithDryRun(ctx context.Context, dryRun bool) context.Context {
+	return context.WithValue(ctx, ctxDryRunKey{}, dryRun)
+}
+
+func DryRunFromContext(ctx context.Context) bool {
+	dryRun, ok := ctx.Value(ctxDryRunKey{}).(bool)
+	return ok && dryRun
+}
+
 func CreateVolume(ctx context.Context, volumeName string) error {
 	return RunDockerCommand(ctx, ".", "volume", "create", volumeName)
 }
@@ -77,7 +91,7 @@ func RunDockerCommand(ctx context.Context, workingDir string, command ...string)
 }
 
 func RunDockerComposeCommand(ctx context.Context, workingDir string, command ...string) error {
-	dockerCmd := exec.Command("docker-compose", command...)
+	dockerCmd := composeCommand(command...)
 	dockerCmd.Dir = workingDir
 	_, err := runCommand(ctx, dockerCmd)
 	return err
@@ -90,43 +104,60 @@ func RunDockerCommandBuffered(ctx context.Context, workingDir string, command ..
 }
 
 func runCommand(ctx context.Context, cmd *exec.Cmd) (string, error) {
+	if DryRunFromContext(ctx) {
+		if cmd.Dir != "" && cmd.Dir != "." {
+			fmt.Printf("(cd %s && %s)\n", cmd.Dir, cmd.String())
+		} else {
+			fmt.Println(cmd.String())
+		}
+		return "", nil
+	}
 	verbose := log.VerbosityFromContext(ctx)
 	if verbose {
 		fmt.Println(cmd.String())
 	}
+	// The log file gets the full output of every command, whether or not it is shown on the console
+	logFile := log.LogFile()
+	logFile.Debug(fmt.Sprintf("running: %s (in %s)", cmd.String(), cmd.Dir))
 	outputBuff := strings.Builder{}
 	stdoutChan := make(chan string)
 	stderrChan := make(chan string)
 	errChan := make(chan error)
 	go pipeCommand(cmd, stdoutChan, stderrChan, errChan)
 
-outputCapture:
-	for {
+	// Read until both pipes are closed, so no output is lost when one finishes before the other
+	for stdoutChan != nil || stderrChan != nil {
 		select {
 		case s, ok := <-stdoutChan:
+			if !ok {
+				stdoutChan = nil
+				continue
+			}
 			if verbose {
-				if !ok {
-					break outputCapture
-				}
 				fmt.Print(s)
 			}
+			logFile.Debug(s)
 			outputBuff.WriteString(s)
 		case s, ok := <-stderrChan:
 			if !ok {
-				break outputCapture
+				stderrChan = nil
+				continue
 			}
 			if verbose {
 				fmt.Print(s)
 			}
+			logFile.Debug(s)
 			outputBuff.WriteString(s)
 		case err := <-errChan:
+			logFile.Error(err)
 			return "", err
 		}
 	}
 	cmd.Wait()
 	statusCode := cmd.ProcessState.ExitCode()
+	logFile.Debug(fmt.Sprintf("exit code %d: %s", statusCode, cmd.String()))
 	if statusCode != 0 {
-		return "", fmt.Errorf("%s [%d] %s", strings.Join(cmd.Args, " "), statusCode, outputBuff.String())
+		return "", errcodes.New(errcodes.DockerCommandFailed, "%s [%d] %s", strings.Join(cmd.Args, " "), statusCode, outputBuff.String())
 	}
 	return outputBuff.String(), nil
 }
@@ -153,6 +184,9 @@ func readPipe(pipe io.ReadCloser, outputChan chan string, errChan chan error) {
 		line, err := buf.ReadString('\n')
 		if err != nil {
 			if err == io.EOF {
+				if line != "" {
+					outputChan <- line
+				}
 				close(outputChan)
 				return
 			} else {
@@ -202,3 +236,20 @@ func GetImageLabel(image, label string) (string, error) {
 func GetImageDigest(image string) (string, error) {
 	return crane.Digest(image)
 }
+
+// ImageExists returns whether an image is already present on this machine
+func ImageExists(ctx context.Context, image string) bool {
+	if DryRunFromContext(ctx) {
+		return false
+	}
+	return exec.Command("docker", "image", "inspect", image).Run() == nil
+}
+
+// GetImageID returns the ID of an image on this machine, which stays the same when its tag is moved to another image
+func GetImageID(ctx context.Context, image string) (string, error) {
+	output, err := RunDockerCommandBuffered(ctx, ".", "image", "inspect", "--format", "{{.Id}}", image)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
@@ -19,28 +19,34 @@ package docker
 import (
 	"fmt"
 	"os/exec"
+
+	"github.com/hyperledger/firefly-cli/internal/errcodes"
+	"github.com/hyperledger/firefly-cli/internal/log"
 )
 
-// CheckDockerConfig is a function to check docker and docker-compose configuration on the host
+// CheckDockerConfig is a function to check docker and docker compose configuration on the host
 func CheckDockerConfig() error {
 
 	dockerCmd := exec.Command("docker", "-v")
 	_, err := dockerCmd.Output()
 	if err != nil {
-		return fmt.Errorf("an error occurred while running docker. Is docker installed on your computer?")
+		log.LogFile().Error(fmt.Errorf("%s: %s", dockerCmd.String(), err))
+		return errcodes.New(errcodes.DockerNotInstalled, "an error occurred while running docker. Is docker installed on your computer?")
 	}
 
-	dockerComposeCmd := exec.Command("docker-compose", "-v")
-	_, err = dockerComposeCmd.Output()
-
+	composeCLI, err := DetectCompose()
 	if err != nil {
-		return fmt.Errorf("an error occurred while running docker-compose. Is docker-compose installed on your computer?")
+		return err
+	}
+	if err := checkComposeVersion(composeCLI); err != nil {
+		return err
 	}
 
 	dockerDeamonCheck := exec.Command("docker", "ps")
 	_, err = dockerDeamonCheck.Output()
 	if err != nil {
-		return fmt.Errorf("an error occurred while running docker. Is docker running on your computer?")
+		log.LogFile().Error(fmt.Errorf("%s: %s", dockerDeamonCheck.String(), err))
+		return errcodes.New(errcodes.DockerNotRunning, "an error occurred while running docker. Is docker running on your computer?")
 	}
 
 	return nil
@@ -18,7 +18,9 @@ package docker
 
 import (
 	"fmt"
+	"net"
 	"path/filepath"
+	"strings"
 
 	"github.com/hyperledger/firefly-cli/internal/constants"
 	"github.com/hyperledger/firefly-cli/pkg/types"
@@ -26,6 +28,41 @@ import (
 
 type DependsOn map[string]map[string]string
 
+// Compose profiles that each generated service is assigned to, so subsets of a stack can be started
+const (
+	ProfileCore       = "core"
+	ProfileBlockchain = "blockchain"
+	ProfileTokens     = "tokens"
+	ProfileMonitoring = "monitoring"
+	ProfileSandbox    = "sandbox"
+)
+
+var AllProfiles = []string{ProfileCore, ProfileBlockchain, ProfileTokens, ProfileMonitoring, ProfileSandbox}
+
+func ValidateProfiles(profiles []string) error {
+	for _, profile := range profiles {
+		valid := false
+		for _, p := range AllProfiles {
+			if p == profile {
+				valid = true
+			}
+		}
+		if !valid {
+			return fmt.Errorf("unknown profile '%s'. valid profiles are: %s", profile, strings.Join(AllProfiles, ", "))
+		}
+	}
+	return nil
+}
+
+// AllProfilesArgs returns the docker compose arguments to enable every profile
+func AllProfilesArgs() []string {
+	args := []string{}
+	for _, profile := range AllProfiles {
+		args = append(args, "--profile", profile)
+	}
+	return args
+}
+
 type HealthCheck struct {
 	Test     []string `yaml:"test,omitempty"`
 	Interval string   `yaml:"interval,omitempty"`
@@ -60,12 +97,44 @@ type Service struct {
 	EntryPoint    []string                     `yaml:"entrypoint,omitempty"`
 	EnvFile       string                       `yaml:"env_file,omitempty"`
 	Expose        []int                        `yaml:"expose,omitempty"`
+	NetworkMode   string                       `yaml:"network_mode,omitempty"`
+	CapAdd        []string                     `yaml:"cap_add,omitempty"`
+	Profiles      []string                     `yaml:"profiles,omitempty"`
+	Labels        map[string]string            `yaml:"labels,omitempty"`
+	DNS           []string                     `yaml:"dns,omitempty"`
+	ExtraHosts    []string                     `yaml:"extra_hosts,omitempty"`
+	Restart       string                       `yaml:"restart,omitempty"`
+}
+
+type Volume struct {
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+type Network struct {
+	Labels map[string]string `yaml:"labels,omitempty"`
 }
 
 type DockerComposeConfig struct {
 	Version  string              `yaml:"version,omitempty"`
 	Services map[string]*Service `yaml:"services,omitempty"`
-	Volumes  map[string]struct{} `yaml:"volumes,omitempty"`
+	Volumes  map[string]*Volume  `yaml:"volumes,omitempty"`
+	Networks map[string]*Network `yaml:"networks,omitempty"`
+}
+
+// StackLabel is attached to every container, volume and network of a stack, so that
+// docker tooling can attribute resources to the stack that created them
+const StackLabel = "org.hyperledger.firefly.stack"
+
+// HostGateway is an extra host entry that lets a container reach the docker host, where
+// the FireFly core of a member runs when it is managed outside of the stack
+const HostGateway = "host.docker.internal:host-gateway"
+
+// CoreHost returns the hostname that other containers of the stack use to reach the FireFly core of a member
+func CoreHost(member *types.Organization) string {
+	if member.External {
+		return "host.docker.internal"
+	}
+	return fmt.Sprintf("firefly_core_%s", member.ID)
 }
 
 var StandardLogOptions = &LoggingConfig{
@@ -78,9 +147,9 @@ var StandardLogOptions = &LoggingConfig{
 
 func CreateDockerCompose(s *types.Stack) *DockerComposeConfig {
 	compose := &DockerComposeConfig{
-		Version:  "2.1",
+		Version:  ComposeFileVersion(),
 		Services: make(map[string]*Service),
-		Volumes:  make(map[string]struct{}),
+		Volumes:  make(map[string]*Volume),
 	}
 	for _, member := range s.Members {
 
@@ -90,7 +159,7 @@ func CreateDockerCompose(s *types.Stack) *DockerComposeConfig {
 			configFile := filepath.Join(s.RuntimeDir, "config", fmt.Sprintf("firefly_core_%s.yml", member.ID))
 			compose.Services["firefly_core_"+member.ID] = &Service{
 				Image:         s.VersionManifest.FireFly.GetDockerImageString(),
-				ContainerName: fmt.Sprintf("%s_firefly_core_%s", s.Name, member.ID),
+				ContainerName: fmt.Sprintf("%s_firefly_core_%s", s.ResourcePrefix(), member.ID),
 				Ports: []string{
 					fmt.Sprintf("%d:%d", member.ExposedFireflyPort, member.ExposedFireflyPort),
 					fmt.Sprintf("%d:%d", member.ExposedFireflyAdminSPIPort, member.ExposedFireflyAdminSPIPort),
@@ -98,14 +167,34 @@ func CreateDockerCompose(s *types.Stack) *DockerComposeConfig {
 				Volumes:   []string{fmt.Sprintf("%s:/etc/firefly/firefly.core.yml:ro", configFile)},
 				DependsOn: map[string]map[string]string{},
 				Logging:   StandardLogOptions,
+				// Any HTTP response means the API is up, including a 401 when API auth is enabled
+				HealthCheck: &HealthCheck{
+					Test:     []string{"CMD", "curl", "-s", "-o", "/dev/null", fmt.Sprintf("http://localhost:%d/api/v1/status", member.ExposedFireflyPort)},
+					Interval: "5s",
+					Timeout:  "3s",
+					Retries:  60,
+				},
+				Profiles: []string{ProfileCore},
+			}
+			if s.APIToken(member) != "" {
+				passwordFile := filepath.Join(s.RuntimeDir, "config", s.APIPasswordFileName(member))
+				compose.Services["firefly_core_"+member.ID].Volumes = append(compose.Services["firefly_core_"+member.ID].Volumes, fmt.Sprintf("%s:%s:ro", passwordFile, constants.APIPasswordFile))
+			}
+			if len(s.Tenants) > 0 {
+				tenantDir := filepath.Join(s.RuntimeDir, "config", "tenants")
+				compose.Services["firefly_core_"+member.ID].Volumes = append(compose.Services["firefly_core_"+member.ID].Volumes, fmt.Sprintf("%s:%s:ro", tenantDir, constants.TenantPasswordDir))
+			}
+			if !s.DisableDataExchange {
+				compose.Services["firefly_core_"+member.ID].DependsOn["dataexchange_"+member.ID] = map[string]string{"condition": "service_healthy"}
+			}
+			if !s.DisableIPFS {
+				compose.Services["firefly_core_"+member.ID].DependsOn["ipfs_"+member.ID] = map[string]string{"condition": "service_healthy"}
 			}
-			compose.Services["firefly_core_"+member.ID].DependsOn["dataexchange_"+member.ID] = map[string]string{"condition": "service_started"}
-			compose.Services["firefly_core_"+member.ID].DependsOn["ipfs_"+member.ID] = map[string]string{"condition": "service_healthy"}
 		}
 		if s.Database == "postgres" {
 			compose.Services["postgres_"+member.ID] = &Service{
 				Image:         constants.PostgresImageName,
-				ContainerName: fmt.Sprintf("%s_postgres_%s", s.Name, member.ID),
+				ContainerName: fmt.Sprintf("%s_postgres_%s", s.ResourcePrefix(), member.ID),
 				Ports:         []string{fmt.Sprintf("%d:5432", member.ExposedDatabasePort)},
 				Environment: map[string]interface{}{
 					"POSTGRES_PASSWORD": "f1refly",
@@ -118,57 +207,80 @@ func CreateDockerCompose(s *types.Stack) *DockerComposeConfig {
 					Timeout:  "3s",
 					Retries:  12,
 				},
-				Logging: StandardLogOptions,
+				Logging:  StandardLogOptions,
+				Profiles: []string{ProfileCore},
 			}
-			compose.Volumes[fmt.Sprintf("postgres_%s", member.ID)] = struct{}{}
+			compose.Volumes[fmt.Sprintf("postgres_%s", member.ID)] = &Volume{}
 			if service, ok := compose.Services[fmt.Sprintf("firefly_core_%s", member.ID)]; ok {
 				service.DependsOn["postgres_"+member.ID] = map[string]string{"condition": "service_healthy"}
 			}
 		}
-		sharedStorage := &Service{
-			Image:         constants.IPFSImageName,
-			ContainerName: fmt.Sprintf("%s_ipfs_%s", s.Name, member.ID),
-			Ports: []string{
-				fmt.Sprintf("%d:5001", member.ExposedIPFSApiPort),
-				fmt.Sprintf("%d:8080", member.ExposedIPFSGWPort),
-			},
-			Volumes: []string{
-				fmt.Sprintf("ipfs_staging_%s:/export", member.ID),
-				fmt.Sprintf("ipfs_data_%s:/data/ipfs", member.ID),
-			},
-			Logging: StandardLogOptions,
-			HealthCheck: &HealthCheck{
-				Test:     []string{"CMD-SHELL", `wget --post-data= http://127.0.0.1:5001/api/v0/id -O - -q`},
-				Interval: "5s",
-				Timeout:  "3s",
-				Retries:  12,
-			},
-		}
-		if s.IPFSMode.Equals(types.IPFSModePrivate) {
-			sharedStorage.Environment = map[string]interface{}{
-				"IPFS_SWARM_KEY":    s.SwarmKey,
-				"LIBP2P_FORCE_PNET": "1",
+		if !s.DisableIPFS {
+			sharedStorage := &Service{
+				Image:         constants.IPFSImageName,
+				ContainerName: fmt.Sprintf("%s_ipfs_%s", s.ResourcePrefix(), member.ID),
+				Ports: []string{
+					fmt.Sprintf("%d:5001", member.ExposedIPFSApiPort),
+					fmt.Sprintf("%d:8080", member.ExposedIPFSGWPort),
+				},
+				Volumes: []string{
+					fmt.Sprintf("ipfs_staging_%s:/export", member.ID),
+					fmt.Sprintf("ipfs_data_%s:/data/ipfs", member.ID),
+				},
+				Logging: StandardLogOptions,
+				HealthCheck: &HealthCheck{
+					Test:     []string{"CMD-SHELL", `wget --post-data= http://127.0.0.1:5001/api/v0/id -O - -q`},
+					Interval: "5s",
+					Timeout:  "3s",
+					Retries:  12,
+				},
+				Profiles: []string{ProfileCore},
+			}
+			if s.IPFSMode.Equals(types.IPFSModePrivate) {
+				sharedStorage.Environment = map[string]interface{}{
+					"IPFS_SWARM_KEY":    s.SwarmKey,
+					"LIBP2P_FORCE_PNET": "1",
+				}
 			}
+			compose.Services["ipfs_"+member.ID] = sharedStorage
+			compose.Volumes[fmt.Sprintf("ipfs_staging_%s", member.ID)] = &Volume{}
+			compose.Volumes[fmt.Sprintf("ipfs_data_%s", member.ID)] = &Volume{}
 		}
-		compose.Services["ipfs_"+member.ID] = sharedStorage
-		compose.Volumes[fmt.Sprintf("ipfs_staging_%s", member.ID)] = struct{}{}
-		compose.Volumes[fmt.Sprintf("ipfs_data_%s", member.ID)] = struct{}{}
-		compose.Services["dataexchange_"+member.ID] = &Service{
-			Image:         s.VersionManifest.DataExchange.GetDockerImageString(),
-			ContainerName: fmt.Sprintf("%s_dataexchange_%s", s.Name, member.ID),
-			Ports:         []string{fmt.Sprintf("%d:3000", member.ExposedDataexchangePort)},
-			Volumes:       []string{fmt.Sprintf("dataexchange_%s:/data", member.ID)},
-			Logging:       StandardLogOptions,
+		if !s.DisableDataExchange {
+			compose.Services["dataexchange_"+member.ID] = &Service{
+				Image:         s.VersionManifest.DataExchange.GetDockerImageString(),
+				ContainerName: fmt.Sprintf("%s_dataexchange_%s", s.ResourcePrefix(), member.ID),
+				Ports:         []string{fmt.Sprintf("%d:3000", member.ExposedDataexchangePort)},
+				Volumes:       []string{fmt.Sprintf("dataexchange_%s:/data", member.ID)},
+				Logging:       StandardLogOptions,
+				HealthCheck: &HealthCheck{
+					Test:     []string{"CMD", "wget", "-q", "-O", "-", "http://localhost:3000/api/v1/id"},
+					Interval: "5s",
+					Timeout:  "3s",
+					Retries:  12,
+				},
+				Profiles: []string{ProfileCore},
+			}
+			compose.Volumes[fmt.Sprintf("dataexchange_%s", member.ID)] = &Volume{}
 		}
-		compose.Volumes[fmt.Sprintf("dataexchange_%s", member.ID)] = struct{}{}
 		if s.SandboxEnabled {
 			compose.Services["sandbox_"+member.ID] = &Service{
 				Image:         constants.SandboxImageName,
-				ContainerName: fmt.Sprintf("%s_sandbox_%s", s.Name, member.ID),
+				ContainerName: fmt.Sprintf("%s_sandbox_%s", s.ResourcePrefix(), member.ID),
 				Ports:         []string{fmt.Sprintf("%d:3001", member.ExposedSandboxPort)},
 				Environment: map[string]interface{}{
-					"FF_ENDPOINT": fmt.Sprintf("http://firefly_core_%d:%d", *member.Index, member.ExposedFireflyPort),
+					"FF_ENDPOINT": fmt.Sprintf("http://%s:%d", CoreHost(member), member.ExposedFireflyPort),
 				},
+				HealthCheck: &HealthCheck{
+					Test:     []string{"CMD", "wget", "-q", "-O", "/dev/null", "http://localhost:3001"},
+					Interval: "5s",
+					Timeout:  "3s",
+					Retries:  12,
+				},
+				Profiles: []string{ProfileSandbox},
+			}
+			if member.External {
+				compose.Services["sandbox_"+member.ID].ExtraHosts = []string{HostGateway}
 			}
 		}
 	}
@@ -176,14 +288,144 @@ func CreateDockerCompose(s *types.Stack) *DockerComposeConfig {
 	if s.PrometheusEnabled {
 		compose.Services["prometheus"] = &Service{
 			Image:         constants.PrometheusImageName,
-			ContainerName: fmt.Sprintf("%s_prometheus", s.Name),
+			ContainerName: fmt.Sprintf("%s_prometheus", s.ResourcePrefix()),
 			Ports:         []string{fmt.Sprintf("%d:9090", s.ExposedPrometheusPort)},
 			Volumes:       []string{"prometheus_data:/prometheus", "prometheus_config:/etc/prometheus"},
 			Logging:       StandardLogOptions,
+			HealthCheck: &HealthCheck{
+				Test:     []string{"CMD", "wget", "-q", "-O", "-", "http://localhost:9090/-/healthy"},
+				Interval: "5s",
+				Timeout:  "3s",
+				Retries:  12,
+			},
+			Profiles: []string{ProfileMonitoring},
 		}
-		compose.Volumes["prometheus_data"] = struct{}{}
-		compose.Volumes["prometheus_config"] = struct{}{}
+		for _, member := range s.Members {
+			if member.External {
+				compose.Services["prometheus"].ExtraHosts = []string{HostGateway}
+			}
+		}
+		compose.Volumes["prometheus_data"] = &Volume{}
+		compose.Volumes["prometheus_config"] = &Volume{}
+	}
+
+	if s.MessageQueue != "" {
+		compose.Services[s.MessageQueue.String()] = messageQueueService(s)
+		compose.Volumes[fmt.Sprintf("%s_data", s.MessageQueue)] = &Volume{}
+	}
+
+	for _, serviceDefinition := range CreateLatencyServices(s) {
+		compose.Services[serviceDefinition.ServiceName] = serviceDefinition.Service
 	}
 
 	return compose
 }
+
+// ApplyLabels attaches the stack label, and any custom labels for the stack, to every
+// service and volume in the compose file, and to the default network
+func ApplyLabels(compose *DockerComposeConfig, s *types.Stack) {
+	labels := map[string]string{StackLabel: s.Name}
+	for k, v := range s.Labels {
+		labels[k] = v
+	}
+	for _, service := range compose.Services {
+		service.Labels = labels
+	}
+	for name := range compose.Volumes {
+		compose.Volumes[name] = &Volume{Labels: labels}
+	}
+	compose.Networks = map[string]*Network{
+		"default": {Labels: labels},
+	}
+}
+
+// ParseLabels parses a list of key=value strings into a map of docker labels
+func ParseLabels(input []string) (map[string]string, error) {
+	labels := make(map[string]string, len(input))
+	for _, l := range input {
+		parts := strings.SplitN(l, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid label '%s'. labels must be in the format key=value", l)
+		}
+		labels[strings.TrimSpace(parts[0])] = parts[1]
+	}
+	return labels, nil
+}
+
+// ApplyNetworkOverrides sets the custom DNS servers and extra hosts of the stack on every service.
+// Services that share the network namespace of another service inherit its DNS config, so they are skipped.
+func ApplyNetworkOverrides(compose *DockerComposeConfig, s *types.Stack) {
+	if len(s.DNS) == 0 && len(s.ExtraHosts) == 0 {
+		return
+	}
+	for _, service := range compose.Services {
+		if service.NetworkMode != "" {
+			continue
+		}
+		service.DNS = s.DNS
+		service.ExtraHosts = append(service.ExtraHosts, s.ExtraHosts...)
+	}
+}
+
+// ValidateDNS checks that each DNS server is an IP address
+func ValidateDNS(servers []string) error {
+	for _, server := range servers {
+		if net.ParseIP(server) == nil {
+			return fmt.Errorf("invalid DNS server '%s'. DNS servers must be IP addresses", server)
+		}
+	}
+	return nil
+}
+
+// ValidateExtraHosts checks that each extra host is in the format hostname:ip. The special
+// address host-gateway resolves to the docker host.
+func ValidateExtraHosts(hosts []string) error {
+	for _, h := range hosts {
+		parts := strings.SplitN(h, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || (parts[1] != "host-gateway" && net.ParseIP(parts[1]) == nil) {
+			return fmt.Errorf("invalid extra host '%s'. extra hosts must be in the format hostname:ip", h)
+		}
+	}
+	return nil
+}
+
+// ApplyRestartPolicy sets the restart policy of the stack on every service, so docker starts the
+// containers again when the daemon restarts
+func ApplyRestartPolicy(compose *DockerComposeConfig, s *types.Stack) {
+	if s.RestartPolicy == "" {
+		return
+	}
+	for _, service := range compose.Services {
+		service.Restart = s.RestartPolicy
+	}
+}
+
+// ApplyBindAddress publishes the FireFly API and sandbox of each member on the bind address of the
+// stack, and every other port on the loopback address only. Stacks created before the bind address
+// could be set are left unchanged.
+func ApplyBindAddress(compose *DockerComposeConfig, s *types.Stack) {
+	if s.BindAddress == "" {
+		return
+	}
+	exposed := make(map[string]bool)
+	for _, member := range s.Members {
+		exposed[fmt.Sprint(member.ExposedFireflyPort)] = true
+		if s.SandboxEnabled {
+			exposed[fmt.Sprint(member.ExposedSandboxPort)] = true
+		}
+	}
+	for _, service := range compose.Services {
+		for i, port := range service.Ports {
+			parts := strings.Split(port, ":")
+			if len(parts) != 2 {
+				// Already bound to a specific address
+				continue
+			}
+			address := "127.0.0.1"
+			if exposed[parts[0]] {
+				address = s.BindAddress
+			}
+			service.Ports[i] = fmt.Sprintf("%s:%s", address, port)
+		}
+	}
+}
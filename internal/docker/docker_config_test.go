@@ -0,0 +1,54 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyNetworkOverrides(T *testing.T) {
+	compose := &DockerComposeConfig{
+		Services: map[string]*Service{
+			"firefly_core_0":       {},
+			"latency_firefly_core": {NetworkMode: "service:firefly_core_0"},
+		},
+	}
+	stack := &types.Stack{
+		DNS:        []string{"10.0.0.53"},
+		ExtraHosts: []string{"registry.corp:10.0.0.10"},
+	}
+	ApplyNetworkOverrides(compose, stack)
+	assert.Equal(T, []string{"10.0.0.53"}, compose.Services["firefly_core_0"].DNS)
+	assert.Equal(T, []string{"registry.corp:10.0.0.10"}, compose.Services["firefly_core_0"].ExtraHosts)
+	assert.Nil(T, compose.Services["latency_firefly_core"].DNS)
+	assert.Nil(T, compose.Services["latency_firefly_core"].ExtraHosts)
+}
+
+func TestValidateDNS(T *testing.T) {
+	assert.NoError(T, ValidateDNS([]string{"10.0.0.53", "fd00::53"}))
+	assert.Regexp(T, "invalid DNS server 'dns.corp'", ValidateDNS([]string{"dns.corp"}))
+}
+
+func TestValidateExtraHosts(T *testing.T) {
+	assert.NoError(T, ValidateExtraHosts([]string{"node.corp:10.0.0.1", "node6.corp:fd00::1", "host.docker.internal:host-gateway"}))
+	assert.Regexp(T, "invalid extra host", ValidateExtraHosts([]string{"node.corp"}))
+	assert.Regexp(T, "invalid extra host", ValidateExtraHosts([]string{"node.corp:not-an-ip"}))
+	assert.Regexp(T, "invalid extra host", ValidateExtraHosts([]string{":10.0.0.1"}))
+}
@@ -0,0 +1,157 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+// LatencyProfile describes a set of regions and the round trip times between them.
+// Members are assigned to regions in order, wrapping around if there are more members than regions.
+type LatencyProfile struct {
+	Regions []string
+	// RTT holds the round trip time in milliseconds between each pair of regions, keyed by "regionA:regionB"
+	RTT map[string]int
+}
+
+var LatencyProfiles = map[string]*LatencyProfile{
+	"us-eu": {
+		Regions: []string{"us-east", "eu-west"},
+		RTT: map[string]int{
+			"us-east:eu-west": 80,
+		},
+	},
+	"us-eu-apac": {
+		Regions: []string{"us-east", "eu-west", "ap-southeast"},
+		RTT: map[string]int{
+			"us-east:eu-west":      80,
+			"us-east:ap-southeast": 220,
+			"eu-west:ap-southeast": 160,
+		},
+	},
+	"us-coast-to-coast": {
+		Regions: []string{"us-east", "us-west"},
+		RTT: map[string]int{
+			"us-east:us-west": 65,
+		},
+	},
+}
+
+// latencyServices are the member services that carry traffic between members, and so get a latency sidecar
+var latencyServices = []string{"dataexchange", "ipfs"}
+
+func LatencyProfileNames() []string {
+	names := make([]string, 0, len(LatencyProfiles))
+	for name := range LatencyProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func ValidateLatencyProfile(name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, ok := LatencyProfiles[name]; !ok {
+		return fmt.Errorf("unknown latency profile '%s'. valid profiles are: %s", name, strings.Join(LatencyProfileNames(), ", "))
+	}
+	return nil
+}
+
+// Region returns the region a member is placed in for this profile
+func (p *LatencyProfile) Region(memberIndex int) string {
+	return p.Regions[memberIndex%len(p.Regions)]
+}
+
+// Delay returns the one way delay in milliseconds between two regions. Each side of a
+// connection delays its own outbound traffic, so together they add up to the full round trip.
+func (p *LatencyProfile) Delay(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if rtt, ok := p.RTT[a+":"+b]; ok {
+		return rtt / 2
+	}
+	return p.RTT[b+":"+a] / 2
+}
+
+// CreateLatencyServices returns a sidecar for each member's dataexchange and IPFS containers, which shares the
+// network namespace of that container and uses tc netem to delay its outbound traffic to other members
+func CreateLatencyServices(s *types.Stack) []*ServiceDefinition {
+	profile, ok := LatencyProfiles[s.LatencyProfile]
+	if !ok {
+		return nil
+	}
+	serviceDefinitions := []*ServiceDefinition{}
+	for _, member := range s.Members {
+		region := profile.Region(*member.Index)
+		for _, serviceType := range latencyServices {
+			if (serviceType == "dataexchange" && s.DisableDataExchange) || (serviceType == "ipfs" && s.DisableIPFS) {
+				continue
+			}
+			serviceName := fmt.Sprintf("%s_%s", serviceType, member.ID)
+			// Note that $ is escaped as $$ so docker compose does not try to interpolate it
+			script := []string{
+				"set -e",
+				"tc qdisc add dev eth0 root handle 1: htb default 1",
+				"tc class add dev eth0 parent 1: classid 1:1 htb rate 10gbit",
+			}
+			dependsOn := map[string]map[string]string{
+				serviceName: {"condition": "service_started"},
+			}
+			for _, peer := range s.Members {
+				delay := profile.Delay(region, profile.Region(*peer.Index))
+				if peer.ID == member.ID || delay == 0 {
+					continue
+				}
+				peerService := fmt.Sprintf("%s_%s", serviceType, peer.ID)
+				dependsOn[peerService] = map[string]string{"condition": "service_started"}
+				class := fmt.Sprintf("%x", *peer.Index+16)
+				script = append(script,
+					fmt.Sprintf("until getent hosts %s; do sleep 1; done", peerService),
+					fmt.Sprintf("tc class add dev eth0 parent 1: classid 1:%s htb rate 10gbit", class),
+					fmt.Sprintf("tc qdisc add dev eth0 parent 1:%s handle %s: netem delay %dms %dms", class, class, delay, delay/10),
+					fmt.Sprintf("tc filter add dev eth0 protocol ip parent 1: prio 1 u32 match ip dst $$(getent hosts %s | awk '{print $$1}')/32 flowid 1:%s", peerService, class),
+				)
+			}
+			script = append(script,
+				fmt.Sprintf("echo '%s is in region %s'", serviceName, region),
+				"exec sleep infinity",
+			)
+			serviceDefinitions = append(serviceDefinitions, &ServiceDefinition{
+				ServiceName: fmt.Sprintf("latency_%s", serviceName),
+				Service: &Service{
+					Image:         constants.NetemImageName,
+					ContainerName: fmt.Sprintf("%s_latency_%s", s.ResourcePrefix(), serviceName),
+					NetworkMode:   fmt.Sprintf("service:%s", serviceName),
+					CapAdd:        []string{"NET_ADMIN"},
+					EntryPoint:    []string{"/bin/sh", "-c", strings.Join(script, "\n")},
+					DependsOn:     dependsOn,
+					Logging:       StandardLogOptions,
+					Profiles:      []string{ProfileCore},
+				},
+			})
+		}
+	}
+	return serviceDefinitions
+}
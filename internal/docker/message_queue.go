@@ -0,0 +1,67 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+)
+
+// MessageQueuePort returns the standard port of a message broker, which is used on the host unless another is chosen
+func MessageQueuePort(mq fftypes.FFEnum) int {
+	if mq.Equals(types.MessageQueueRedis) {
+		return 6379
+	}
+	return 4222
+}
+
+// messageQueueService returns the shared message broker of the stack. NATS is run with JetStream enabled,
+// and Redis with append only persistence, so messages survive a restart of the stack like the rest of its data.
+func messageQueueService(s *types.Stack) *Service {
+	port := MessageQueuePort(s.MessageQueue)
+	service := &Service{
+		ContainerName: fmt.Sprintf("%s_%s", s.ResourcePrefix(), s.MessageQueue),
+		Ports:         []string{fmt.Sprintf("%d:%d", s.ExposedMessageQueuePort, port)},
+		Logging:       StandardLogOptions,
+		Profiles:      []string{ProfileCore},
+	}
+	if s.MessageQueue.Equals(types.MessageQueueRedis) {
+		service.Image = constants.RedisImageName
+		service.Command = "redis-server --appendonly yes"
+		service.Volumes = []string{"redis_data:/data"}
+		service.HealthCheck = &HealthCheck{
+			Test:     []string{"CMD", "redis-cli", "ping"},
+			Interval: "5s",
+			Timeout:  "3s",
+			Retries:  12,
+		}
+	} else {
+		service.Image = constants.NATSImageName
+		service.Command = "--jetstream --store_dir /data --http_port 8222"
+		service.Volumes = []string{"nats_data:/data"}
+		service.HealthCheck = &HealthCheck{
+			Test:     []string{"CMD", "wget", "-q", "-O", "-", "http://127.0.0.1:8222/healthz"},
+			Interval: "5s",
+			Timeout:  "3s",
+			Retries:  12,
+		}
+	}
+	return service
+}
@@ -0,0 +1,60 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// EngineResources is the memory and CPU available to containers, as reported by docker info.
+// With Docker Desktop and WSL2 this is the size of the VM, not of the host.
+type EngineResources struct {
+	MemoryBytes     int64  `json:"MemTotal"`
+	CPUs            int    `json:"NCPU"`
+	OperatingSystem string `json:"OperatingSystem"`
+	KernelVersion   string `json:"KernelVersion"`
+}
+
+// IsDockerDesktop returns whether the engine runs in the Docker Desktop VM
+func (r *EngineResources) IsDockerDesktop() bool {
+	return strings.Contains(r.OperatingSystem, "Docker Desktop")
+}
+
+// IsWSL2 returns whether the engine runs on a WSL2 kernel, either in Docker Desktop or in a WSL2 distribution
+func (r *EngineResources) IsWSL2() bool {
+	kernel := strings.ToLower(r.KernelVersion)
+	return strings.Contains(kernel, "microsoft") || strings.Contains(kernel, "wsl2")
+}
+
+func parseEngineResources(output string) (*EngineResources, error) {
+	var resources EngineResources
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &resources); err != nil {
+		return nil, err
+	}
+	return &resources, nil
+}
+
+// GetEngineResources returns the memory and CPU limits of the docker engine
+func GetEngineResources(ctx context.Context) (*EngineResources, error) {
+	output, err := RunDockerCommandBuffered(ctx, ".", "info", "--format", "{{json .}}")
+	if err != nil {
+		return nil, err
+	}
+	return parseEngineResources(output)
+}
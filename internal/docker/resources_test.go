@@ -0,0 +1,40 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEngineResources(T *testing.T) {
+	r, err := parseEngineResources(`{"MemTotal":4116832256,"NCPU":4,"OperatingSystem":"Docker Desktop","KernelVersion":"5.15.90.1-microsoft-standard-WSL2","Name":"docker-desktop"}` + "\n")
+	assert.NoError(T, err)
+	assert.Equal(T, int64(4116832256), r.MemoryBytes)
+	assert.Equal(T, 4, r.CPUs)
+	assert.True(T, r.IsDockerDesktop())
+	assert.True(T, r.IsWSL2())
+
+	r, err = parseEngineResources(`{"MemTotal":16000000000,"NCPU":8,"OperatingSystem":"Ubuntu 22.04.2 LTS","KernelVersion":"5.15.0-76-generic"}`)
+	assert.NoError(T, err)
+	assert.False(T, r.IsDockerDesktop())
+	assert.False(T, r.IsWSL2())
+
+	_, err = parseEngineResources("")
+	assert.Error(T, err)
+}
@@ -0,0 +1,164 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ContainerStats is a single sample of resource usage for a container, as reported by docker stats
+type ContainerStats struct {
+	Name         string
+	CPUPercent   float64
+	MemoryBytes  float64
+	NetRxBytes   float64
+	NetTxBytes   float64
+	BlockRead    float64
+	BlockWritten float64
+}
+
+type dockerStatsLine struct {
+	Name     string `json:"Name"`
+	CPUPerc  string `json:"CPUPerc"`
+	MemUsage string `json:"MemUsage"`
+	NetIO    string `json:"NetIO"`
+	BlockIO  string `json:"BlockIO"`
+}
+
+var sizeRegex = regexp.MustCompile(`^([0-9.]+)\s*([a-zA-Z]*)$`)
+
+var sizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// ParseSize parses a human readable size as printed by docker (e.g. "1.5MiB" or "20kB") into bytes
+func ParseSize(s string) (float64, error) {
+	match := sizeRegex.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return 0, fmt.Errorf("invalid size '%s'", s)
+	}
+	unit, ok := sizeUnits[strings.ToLower(match[2])]
+	if !ok {
+		return 0, fmt.Errorf("invalid size unit in '%s'", s)
+	}
+	v, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	return v * unit, nil
+}
+
+// parseSizePair parses a pair of sizes separated by a slash, such as the NetIO column of docker stats
+func parseSizePair(s string) (float64, float64, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid size pair '%s'", s)
+	}
+	a, err := ParseSize(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	b, err := ParseSize(parts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return a, b, nil
+}
+
+func parseStatsLine(line string) (*ContainerStats, error) {
+	var l dockerStatsLine
+	if err := json.Unmarshal([]byte(line), &l); err != nil {
+		return nil, err
+	}
+	cpu, err := strconv.ParseFloat(strings.TrimSuffix(l.CPUPerc, "%"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CPU percentage '%s' for container %s", l.CPUPerc, l.Name)
+	}
+	mem, _, err := parseSizePair(l.MemUsage)
+	if err != nil {
+		return nil, err
+	}
+	rx, tx, err := parseSizePair(l.NetIO)
+	if err != nil {
+		return nil, err
+	}
+	read, written, err := parseSizePair(l.BlockIO)
+	if err != nil {
+		return nil, err
+	}
+	return &ContainerStats{
+		Name:         l.Name,
+		CPUPercent:   cpu,
+		MemoryBytes:  mem,
+		NetRxBytes:   rx,
+		NetTxBytes:   tx,
+		BlockRead:    read,
+		BlockWritten: written,
+	}, nil
+}
+
+// GetContainerStats takes a single sample of resource usage for each of the named containers
+func GetContainerStats(ctx context.Context, containerNames []string) ([]*ContainerStats, error) {
+	args := append([]string{"stats", "--no-stream", "--format", "{{json .}}"}, containerNames...)
+	out, err := RunDockerCommandBuffered(ctx, "", args...)
+	if err != nil {
+		return nil, err
+	}
+	stats := []*ContainerStats{}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		s, err := parseStatsLine(line)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// ListRunningContainers returns the names of the running containers whose names start with prefix
+func ListRunningContainers(ctx context.Context, prefix string) ([]string, error) {
+	out, err := RunDockerCommandBuffered(ctx, "", "ps", "--filter", fmt.Sprintf("name=^%s", prefix), "--format", "{{.Names}}")
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, name := range strings.Split(out, "\n") {
+		name = strings.TrimSpace(name)
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,52 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSize(T *testing.T) {
+	v, err := ParseSize("1.5MiB")
+	assert.NoError(T, err)
+	assert.Equal(T, float64(1.5*(1<<20)), v)
+
+	v, err = ParseSize("20kB")
+	assert.NoError(T, err)
+	assert.Equal(T, float64(20000), v)
+
+	v, err = ParseSize("0B")
+	assert.NoError(T, err)
+	assert.Equal(T, float64(0), v)
+
+	_, err = ParseSize("lots")
+	assert.Error(T, err)
+}
+
+func TestParseStatsLine(T *testing.T) {
+	s, err := parseStatsLine(`{"Name":"dev_geth","CPUPerc":"12.50%","MemUsage":"100MiB / 7.5GiB","NetIO":"1kB / 2kB","BlockIO":"3MB / 4MB"}`)
+	assert.NoError(T, err)
+	assert.Equal(T, "dev_geth", s.Name)
+	assert.Equal(T, 12.5, s.CPUPercent)
+	assert.Equal(T, float64(100*(1<<20)), s.MemoryBytes)
+	assert.Equal(T, float64(1000), s.NetRxBytes)
+	assert.Equal(T, float64(2000), s.NetTxBytes)
+	assert.Equal(T, float64(3e6), s.BlockRead)
+	assert.Equal(T, float64(4e6), s.BlockWritten)
+}
@@ -0,0 +1,157 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/errcodes"
+)
+
+// ContainerStatus is the state of a container, as reported by docker inspect
+type ContainerStatus struct {
+	Name         string
+	State        string
+	Health       string
+	RestartCount int
+	StartedAt    time.Time
+}
+
+const statusFormat = "{{.Name}}\t{{.State.Status}}\t{{if .State.Health}}{{.State.Health.Status}}{{end}}\t{{.RestartCount}}\t{{.State.StartedAt}}"
+
+func parseStatusLine(line string) (*ContainerStatus, error) {
+	fields := strings.Split(line, "\t")
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid container status '%s'", line)
+	}
+	restarts, err := strconv.Atoi(fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid restart count '%s' for container %s", fields[3], fields[0])
+	}
+	// Containers that never started report the zero time, which still parses
+	startedAt, err := time.Parse(time.RFC3339Nano, fields[4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid start time '%s' for container %s", fields[4], fields[0])
+	}
+	return &ContainerStatus{
+		Name:         strings.TrimPrefix(fields[0], "/"),
+		State:        fields[1],
+		Health:       fields[2],
+		RestartCount: restarts,
+		StartedAt:    startedAt,
+	}, nil
+}
+
+// GetContainerStatus returns the state, health and restart count of each of the named containers
+func GetContainerStatus(ctx context.Context, containerNames []string) ([]*ContainerStatus, error) {
+	args := append([]string{"inspect", "--format", statusFormat}, containerNames...)
+	out, err := RunDockerCommandBuffered(ctx, "", args...)
+	if err != nil {
+		return nil, err
+	}
+	statuses := []*ContainerStatus{}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		s, err := parseStatusLine(line)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// WaitForHealthy waits until each of the named containers reports a healthy status. It fails as soon as a
+// container exits or fails its healthcheck, or when the timeout passes.
+func WaitForHealthy(ctx context.Context, containerNames []string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		statuses, err := GetContainerStatus(ctx, containerNames)
+		if err != nil {
+			return err
+		}
+		pending, err := checkHealth(statuses)
+		if err != nil || len(pending) == 0 {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return errcodes.New(errcodes.ServiceUnhealthy, "timed out after %s waiting for %s to become healthy", timeout, strings.Join(pending, ", "))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// checkHealth returns the containers that are still starting, or an error for the first one that has failed
+func checkHealth(statuses []*ContainerStatus) ([]string, error) {
+	pending := []string{}
+	for _, s := range statuses {
+		switch {
+		case s.State == "exited" || s.State == "dead":
+			return nil, errcodes.New(errcodes.ServiceUnhealthy, "container %s %s during startup", s.Name, s.State)
+		case s.Health == "unhealthy":
+			return nil, errcodes.New(errcodes.ServiceUnhealthy, "container %s failed its healthcheck", s.Name)
+		case s.Health == "" && s.State == "running":
+			// Created from an older compose file without a healthcheck, so there is nothing more to wait for
+		case s.Health != "healthy":
+			pending = append(pending, s.Name)
+		}
+	}
+	return pending, nil
+}
+
+// ListContainers returns the names of all containers whose names start with prefix, including stopped ones
+func ListContainers(ctx context.Context, prefix string) ([]string, error) {
+	out, err := RunDockerCommandBuffered(ctx, "", "ps", "--all", "--filter", fmt.Sprintf("name=^%s", prefix), "--format", "{{.Names}}")
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, name := range strings.Split(out, "\n") {
+		name = strings.TrimSpace(name)
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// GetRecentLogs returns the last lines of output of a container, each prefixed with its RFC3339 timestamp
+func GetRecentLogs(ctx context.Context, containerName string, lines int) ([]string, error) {
+	out, err := RunDockerCommandBuffered(ctx, "", "logs", "--timestamps", "--tail", strconv.Itoa(lines), containerName)
+	if err != nil {
+		return nil, err
+	}
+	logs := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line != "" {
+			logs = append(logs, line)
+		}
+	}
+	return logs, nil
+}
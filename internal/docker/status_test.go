@@ -0,0 +1,58 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStatusLine(T *testing.T) {
+	s, err := parseStatusLine("/dev_firefly_core_0\trunning\thealthy\t2\t2022-06-01T10:00:00.123456789Z")
+	assert.NoError(T, err)
+	assert.Equal(T, "dev_firefly_core_0", s.Name)
+	assert.Equal(T, "running", s.State)
+	assert.Equal(T, "healthy", s.Health)
+	assert.Equal(T, 2, s.RestartCount)
+	assert.Equal(T, 2022, s.StartedAt.Year())
+
+	s, err = parseStatusLine("/dev_ipfs_0\texited\t\t0\t0001-01-01T00:00:00Z")
+	assert.NoError(T, err)
+	assert.Equal(T, "", s.Health)
+	assert.True(T, s.StartedAt.IsZero())
+
+	_, err = parseStatusLine("/dev_ipfs_0\trunning")
+	assert.Error(T, err)
+}
+
+func TestCheckHealth(T *testing.T) {
+	pending, err := checkHealth([]*ContainerStatus{
+		{Name: "dev_geth", State: "running", Health: "healthy"},
+		{Name: "dev_ethconnect_0", State: "running", Health: "starting"},
+		{Name: "dev_sandbox_0", State: "running"},
+		{Name: "dev_ipfs_0", State: "created"},
+	})
+	assert.NoError(T, err)
+	assert.Equal(T, []string{"dev_ethconnect_0", "dev_ipfs_0"}, pending)
+
+	_, err = checkHealth([]*ContainerStatus{{Name: "dev_geth", State: "running", Health: "unhealthy"}})
+	assert.Regexp(T, "FF-CLI-0015.*dev_geth failed its healthcheck", err)
+
+	_, err = checkHealth([]*ContainerStatus{{Name: "dev_firefly_core_0", State: "exited"}})
+	assert.Regexp(T, "FF-CLI-0015.*dev_firefly_core_0 exited during startup", err)
+}
@@ -0,0 +1,188 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errcodes
+
+var (
+	DockerNotInstalled = register("FF-CLI-0001", "docker is not installed",
+		[]string{
+			"The docker CLI is not installed, or it is not on the PATH of the shell running ff.",
+		},
+		[]string{
+			"Install Docker Desktop or Docker Engine from https://docs.docker.com/get-docker/",
+			"Check that 'docker -v' works in the same shell.",
+		})
+
+	DockerComposeNotInstalled = register("FF-CLI-0002", "docker compose is not installed",
+		[]string{
+			"Neither the Docker Compose plugin nor the docker-compose CLI is installed, or they are not on the PATH of the shell running ff.",
+			"The installed docker-compose is older than 1.28, which does not support profiles.",
+		},
+		[]string{
+			"Install the Docker Compose plugin from https://docs.docker.com/compose/install/",
+			"Check that 'docker compose version' or 'docker-compose version' works in the same shell.",
+		})
+
+	DockerNotRunning = register("FF-CLI-0003", "the docker daemon is not running",
+		[]string{
+			"Docker Desktop or the docker service has not been started.",
+			"The current user does not have permission to use the docker socket.",
+		},
+		[]string{
+			"Start Docker Desktop, or run 'sudo systemctl start docker'.",
+			"On Linux, add your user to the docker group and log in again.",
+			"Check that 'docker ps' works in the same shell.",
+		})
+
+	StackNotFound = register("FF-CLI-0004", "the stack does not exist",
+		[]string{
+			"The stack name is misspelled.",
+			"The stack was removed, or was created with a different HOME directory.",
+		},
+		[]string{
+			"Run 'ff ls' to list the stacks that exist.",
+			"Create the stack with 'ff init'.",
+		})
+
+	StackAlreadyExists = register("FF-CLI-0005", "a stack with that name already exists",
+		[]string{
+			"'ff init' was run with the name of an existing stack.",
+		},
+		[]string{
+			"Choose a different stack name.",
+			"Remove the existing stack with 'ff remove <stack_name>', or run 'ff init' with --force to replace it.",
+		})
+
+	InvalidStackName = register("FF-CLI-0006", "the stack name is invalid",
+		[]string{
+			"The stack name is empty, or contains characters that cannot be used in docker container and volume names.",
+		},
+		[]string{
+			"Use a stack name made of letters, numbers, '_' and '-'.",
+		})
+
+	PortUnavailable = register("FF-CLI-0007", "a port needed by the stack is already in use",
+		[]string{
+			"Another stack is running and using the same ports.",
+			"Another process on the machine is listening on the port.",
+		},
+		[]string{
+			"Stop other stacks with 'ff stop <stack_name>'.",
+			"Find the process using the port with 'lsof -i :<port>' (macOS/Linux) or 'netstat -ano' (Windows).",
+			"Create the stack with a different --firefly-base-port and --services-base-port.",
+		})
+
+	DockerCommandFailed = register("FF-CLI-0008", "a docker command failed",
+		[]string{
+			"An image could not be pulled, for example because of network problems or registry rate limits.",
+			"A container failed to start, or exited with an error.",
+			"The machine has run out of disk space, or docker has run out of memory.",
+		},
+		[]string{
+			"Run the command again with -v, or with --log-file, to see the full output of docker.",
+			"Check the logs of the stack with 'ff logs <stack_name>'.",
+			"Free disk space with 'docker system prune'.",
+		})
+
+	FireFlyStartTimeout = register("FF-CLI-0009", "FireFly did not start in time",
+		[]string{
+			"FireFly core could not connect to its database, blockchain connector, or other plugins.",
+			"The machine is slow or heavily loaded, so starting the stack took longer than expected.",
+		},
+		[]string{
+			"Check the logs of FireFly core with 'ff logs <stack_name>'.",
+			"Give docker more CPU and memory.",
+		})
+
+	StackProtected = register("FF-CLI-0010", "the stack is protected",
+		[]string{
+			"The stack was protected with 'ff protect', to stop its data from being deleted by mistake.",
+		},
+		[]string{
+			"Remove the protection with 'ff protect <stack_name> --off'.",
+			"Run the command with the override flag shown in the error.",
+		})
+
+	StackFromNewerCLI = register("FF-CLI-0011", "the stack was created by a newer version of the CLI",
+		[]string{
+			"The stack files use a schema version that this version of the CLI does not understand.",
+		},
+		[]string{
+			"Upgrade the CLI to the version that created the stack, or a later one.",
+			"Restore the '.bak' files written when the stack was migrated, if the stack must be used with this version.",
+		})
+
+	ManifestUnavailable = register("FF-CLI-0012", "the version manifest could not be fetched",
+		[]string{
+			"The machine cannot reach GitHub or the container registry, for example because of a proxy or firewall.",
+			"The requested FireFly version does not exist.",
+		},
+		[]string{
+			"Check the version passed to --release.",
+			"Download the manifest.json of a FireFly release and pass it with --manifest.",
+		})
+
+	StackNotStarted = register("FF-CLI-0013", "the stack has not been started",
+		[]string{
+			"The command needs resources that are only created when the stack is first started.",
+		},
+		[]string{
+			"Start the stack with 'ff start <stack_name>', then run the command again.",
+		})
+
+	IncompatibleVersions = register("FF-CLI-0014", "the versions in the stack are known not to work together",
+		[]string{
+			"The manifest pins a connector, token connector or data exchange that is older than the FireFly core version needs.",
+			"A custom --manifest mixes components from different FireFly releases.",
+		},
+		[]string{
+			"Use the manifest of a single FireFly release, for example with --release.",
+			"Update the tags of the components named in the error in the stack's stack.json.",
+			"Pass --skip-compatibility-check to start the stack anyway.",
+		})
+
+	ServiceUnhealthy = register("FF-CLI-0015", "a service of the stack did not become healthy",
+		[]string{
+			"A container exited or failed its healthcheck, for example because of a bad config override.",
+			"The machine is too slow or short of memory for the services to start within the timeout.",
+		},
+		[]string{
+			"Check the logs of the service named in the error with 'ff logs <stack_name>'.",
+			"Give Docker more memory or CPU, or start fewer members.",
+		})
+
+	ImagePullFailed = register("FF-CLI-0016", "images of the stack could not be pulled",
+		[]string{
+			"The network connection dropped during a large download, even after retrying.",
+			"The registry is rate limiting pulls, or an image tag in the manifest does not exist.",
+		},
+		[]string{
+			"Run 'ff pull <stack_name>' again. Images that were already pulled are skipped, and docker keeps the layers it finished downloading.",
+			"Use --retries to retry each image more times.",
+			"Run 'docker login' if the registry limits anonymous pulls.",
+		})
+
+	RefreshRolledBack = register("FF-CLI-0017", "the stack did not pass its smoke test after a refresh, so it was rolled back",
+		[]string{
+			"A new nightly image has a bug, or does not work with the other images of the stack.",
+			"A service took longer to become healthy than the refresh waits for.",
+		},
+		[]string{
+			"Check the logs of the failed service with 'ff logs <stack_name> <service>'. The stack is running its previous images again.",
+			"Run 'ff refresh <stack_name>' again once a fixed image is published.",
+			"Use --no-rollback to leave the stack on the new images to debug it.",
+		})
+)
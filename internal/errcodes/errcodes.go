@@ -0,0 +1,103 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package errcodes gives the failures of the CLI stable codes, so that scripts can check for a
+// code rather than matching error text, and users can look up the causes and fixes of an error
+// with "ff explain <code>". Codes must never be reused or renumbered.
+package errcodes
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Entry struct {
+	Code    string
+	Summary string
+	Causes  []string
+	Fixes   []string
+}
+
+// CodedError is an error with the code of a catalogue entry
+type CodedError struct {
+	Entry *Entry
+	Err   error
+}
+
+func (e *CodedError) Error() string {
+	return fmt.Sprintf("[%s] %s", e.Entry.Code, e.Err.Error())
+}
+
+func (e *CodedError) Unwrap() error {
+	return e.Err
+}
+
+var catalogue = map[string]*Entry{}
+
+func register(code, summary string, causes, fixes []string) *Entry {
+	if _, ok := catalogue[code]; ok {
+		panic(fmt.Sprintf("duplicate error code %s", code))
+	}
+	e := &Entry{Code: code, Summary: summary, Causes: causes, Fixes: fixes}
+	catalogue[code] = e
+	return e
+}
+
+// New returns an error with the code of the entry, and a message formatted as with fmt.Errorf
+func New(entry *Entry, format string, a ...interface{}) error {
+	return &CodedError{Entry: entry, Err: fmt.Errorf(format, a...)}
+}
+
+// Wrap adds the code of the entry to an existing error
+func Wrap(entry *Entry, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &CodedError{Entry: entry, Err: err}
+}
+
+// CodeOf returns the code of the first coded error in the chain of err, or an empty string
+func CodeOf(err error) string {
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded.Entry.Code
+	}
+	return ""
+}
+
+// Lookup finds a catalogue entry. The "FF-CLI-" prefix is optional, and case is ignored.
+func Lookup(code string) (*Entry, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !strings.HasPrefix(code, "FF-CLI-") {
+		code = fmt.Sprintf("FF-CLI-%04s", code)
+	}
+	e, ok := catalogue[code]
+	return e, ok
+}
+
+// All returns every entry of the catalogue, ordered by code
+func All() []*Entry {
+	entries := make([]*Entry, 0, len(catalogue))
+	for _, e := range catalogue {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Code < entries[j].Code
+	})
+	return entries
+}
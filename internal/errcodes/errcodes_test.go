@@ -0,0 +1,50 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errcodes
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeOfWrappedError(t *testing.T) {
+	err := fmt.Errorf("failed to start: %w", New(PortUnavailable, "port %d is unavailable", 5000))
+	assert.Equal(t, "FF-CLI-0007", CodeOf(err))
+	assert.Equal(t, "failed to start: [FF-CLI-0007] port 5000 is unavailable", err.Error())
+	assert.Equal(t, "", CodeOf(fmt.Errorf("plain error")))
+}
+
+func TestLookup(t *testing.T) {
+	for _, code := range []string{"FF-CLI-0012", "ff-cli-0012", "12", "0012"} {
+		e, ok := Lookup(code)
+		assert.True(t, ok, code)
+		assert.Equal(t, ManifestUnavailable, e)
+	}
+	_, ok := Lookup("FF-CLI-9999")
+	assert.False(t, ok)
+}
+
+func TestCatalogueEntriesComplete(t *testing.T) {
+	for _, e := range All() {
+		assert.Regexp(t, `^FF-CLI-\d{4}$`, e.Code)
+		assert.NotEmpty(t, e.Summary, e.Code)
+		assert.NotEmpty(t, e.Causes, e.Code)
+		assert.NotEmpty(t, e.Fixes, e.Code)
+	}
+}
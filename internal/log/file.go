@@ -0,0 +1,130 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var levelNames = map[LogLevel]string{
+	Trace: "TRACE",
+	Debug: "DEBUG",
+	Info:  "INFO",
+	Warn:  "WARN",
+	Error: "ERROR",
+}
+
+// FileLogger writes every message, regardless of level, to a file with a timestamp and the level
+// of the message. It captures the full output of the CLI even when the console only shows a
+// spinner or a summary.
+type FileLogger struct {
+	mux  sync.Mutex
+	file *os.File
+}
+
+var logFile *FileLogger
+
+// OpenLogFile starts appending the log of this run to the given file. All loggers added to a
+// context with WithLogger also write to it, as does the docker package for every command it runs.
+func OpenLogFile(filename string) error {
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file %s: %s", filename, err)
+	}
+	logFile = &FileLogger{file: f}
+	logFile.Info(fmt.Sprintf("ff %s", strings.Join(os.Args[1:], " ")))
+	return nil
+}
+
+// LogFile returns the log file of this run, or nil if there isn't one. All methods of
+// FileLogger are safe to call on nil.
+func LogFile() *FileLogger {
+	return logFile
+}
+
+func (l *FileLogger) write(level LogLevel, s string) {
+	if l == nil {
+		return
+	}
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	timestamp := time.Now().Format("2006-01-02T15:04:05.000Z07:00")
+	for _, line := range strings.Split(strings.TrimRight(s, "\n"), "\n") {
+		fmt.Fprintf(l.file, "%s [%s] %s\n", timestamp, levelNames[level], line)
+	}
+}
+
+func (l *FileLogger) SetLogLevel(level LogLevel) {}
+
+func (l *FileLogger) Trace(s string) {
+	l.write(Trace, s)
+}
+
+func (l *FileLogger) Debug(s string) {
+	l.write(Debug, s)
+}
+
+func (l *FileLogger) Info(s string) {
+	l.write(Info, s)
+}
+
+func (l *FileLogger) Warn(s string) {
+	l.write(Warn, s)
+}
+
+func (l *FileLogger) Error(e error) {
+	l.write(Error, e.Error())
+}
+
+// teeLogger sends messages to the console logger, and to the log file
+type teeLogger struct {
+	console Logger
+	file    *FileLogger
+}
+
+func (l *teeLogger) SetLogLevel(level LogLevel) {
+	l.console.SetLogLevel(level)
+}
+
+func (l *teeLogger) Trace(s string) {
+	l.console.Trace(s)
+	l.file.Trace(s)
+}
+
+func (l *teeLogger) Debug(s string) {
+	l.console.Debug(s)
+	l.file.Debug(s)
+}
+
+func (l *teeLogger) Info(s string) {
+	l.console.Info(s)
+	l.file.Info(s)
+}
+
+func (l *teeLogger) Warn(s string) {
+	l.console.Warn(s)
+	l.file.Warn(s)
+}
+
+func (l *teeLogger) Error(e error) {
+	l.console.Error(e)
+	l.file.Error(e)
+}
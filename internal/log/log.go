@@ -45,6 +45,11 @@ type (
 )
 
 func WithLogger(ctx context.Context, log Logger) context.Context {
+	if logFile != nil {
+		if _, ok := log.(*teeLogger); !ok {
+			log = &teeLogger{console: log, file: logFile}
+		}
+	}
 	return context.WithValue(ctx, ctxLogKey{}, log)
 }
 
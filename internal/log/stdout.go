@@ -51,7 +51,7 @@ func (l *StdoutLogger) Warn(s string) {
 }
 
 func (l *StdoutLogger) Error(e error) {
-	if l.LogLevel <= Trace {
+	if l.LogLevel <= Error {
 		fmt.Println(e.Error())
 	}
 }
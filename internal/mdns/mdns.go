@@ -0,0 +1,318 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdns
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// ServiceType is the DNS-SD service type that FireFly stacks are advertised under
+const ServiceType = "_firefly._tcp.local."
+
+const ttl = 120
+
+var mdnsAddr = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+type Service struct {
+	Instance string
+	Host     string
+	IP       net.IP
+	Port     int
+	TXT      []string
+}
+
+func (svc *Service) instanceName() string {
+	return fmt.Sprintf("%s.%s", svc.Instance, ServiceType)
+}
+
+func (svc *Service) hostName() string {
+	return fmt.Sprintf("%s.local.", svc.Host)
+}
+
+// Announce advertises the service on the local network, answering any queries for the FireFly
+// service type, until the context is cancelled
+func Announce(ctx context.Context, svc *Service) error {
+	conn, err := net.ListenMulticastUDP("udp4", nil, mdnsAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for mDNS queries: %s", err)
+	}
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	response, err := svc.response(0)
+	if err != nil {
+		return err
+	}
+	// Send an unsolicited announcement so anyone already browsing sees the service straight away
+	if _, err := conn.WriteToUDP(response, mdnsAddr); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		id, ok := isQueryForService(buf[:n])
+		if !ok {
+			continue
+		}
+		if from.Port != mdnsAddr.Port {
+			// A one-shot query from a client that is not a full mDNS responder must be answered directly
+			response, err := svc.response(id)
+			if err != nil {
+				return err
+			}
+			_, err = conn.WriteToUDP(response, from)
+		} else {
+			_, err = conn.WriteToUDP(response, mdnsAddr)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Discover sends a query for the FireFly service type and returns every service that answers before the timeout
+func Discover(timeout time.Duration) ([]*Service, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	query, err := buildQuery()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.WriteToUDP(query, mdnsAddr); err != nil {
+		return nil, fmt.Errorf("failed to send mDNS query: %s", err)
+	}
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	services := map[string]*Service{}
+	order := []string{}
+	buf := make([]byte, 9000)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				break
+			}
+			return nil, err
+		}
+		for _, svc := range parseResponse(buf[:n]) {
+			if _, ok := services[svc.Instance]; !ok {
+				order = append(order, svc.Instance)
+			}
+			services[svc.Instance] = svc
+		}
+	}
+
+	result := make([]*Service, 0, len(order))
+	for _, instance := range order {
+		result = append(result, services[instance])
+	}
+	return result, nil
+}
+
+func isQueryForService(msg []byte) (uint16, bool) {
+	var p dnsmessage.Parser
+	header, err := p.Start(msg)
+	if err != nil || header.Response {
+		return 0, false
+	}
+	questions, err := p.AllQuestions()
+	if err != nil {
+		return 0, false
+	}
+	for _, q := range questions {
+		if (q.Type == dnsmessage.TypePTR || q.Type == dnsmessage.TypeALL) && strings.EqualFold(q.Name.String(), ServiceType) {
+			return header.ID, true
+		}
+	}
+	return 0, false
+}
+
+func buildQuery() ([]byte, error) {
+	name, err := dnsmessage.NewName(ServiceType)
+	if err != nil {
+		return nil, err
+	}
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: uint16(rand.Intn(65536))})
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(dnsmessage.Question{Name: name, Type: dnsmessage.TypePTR, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, err
+	}
+	return b.Finish()
+}
+
+func (svc *Service) response(id uint16) ([]byte, error) {
+	serviceType, err := dnsmessage.NewName(ServiceType)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := dnsmessage.NewName(svc.instanceName())
+	if err != nil {
+		return nil, err
+	}
+	host, err := dnsmessage.NewName(svc.hostName())
+	if err != nil {
+		return nil, err
+	}
+	header := func(name dnsmessage.Name) dnsmessage.ResourceHeader {
+		return dnsmessage.ResourceHeader{Name: name, Class: dnsmessage.ClassINET, TTL: ttl}
+	}
+
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: id, Response: true, Authoritative: true})
+	if err := b.StartAnswers(); err != nil {
+		return nil, err
+	}
+	if err := b.PTRResource(header(serviceType), dnsmessage.PTRResource{PTR: instance}); err != nil {
+		return nil, err
+	}
+	if err := b.SRVResource(header(instance), dnsmessage.SRVResource{Target: host, Port: uint16(svc.Port)}); err != nil {
+		return nil, err
+	}
+	if err := b.TXTResource(header(instance), dnsmessage.TXTResource{TXT: svc.TXT}); err != nil {
+		return nil, err
+	}
+	if ip4 := svc.IP.To4(); ip4 != nil {
+		var a [4]byte
+		copy(a[:], ip4)
+		if err := b.AResource(header(host), dnsmessage.AResource{A: a}); err != nil {
+			return nil, err
+		}
+	}
+	return b.Finish()
+}
+
+func parseResponse(msg []byte) []*Service {
+	var p dnsmessage.Parser
+	header, err := p.Start(msg)
+	if err != nil || !header.Response {
+		return nil
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		return nil
+	}
+
+	instances := map[string]*Service{}
+	hosts := map[string]net.IP{}
+	targets := map[string]string{}
+	getInstance := func(name string) *Service {
+		if _, ok := instances[name]; !ok {
+			instances[name] = &Service{Instance: strings.TrimSuffix(name, "."+ServiceType)}
+		}
+		return instances[name]
+	}
+
+	// Responders may put the records in either the answer or additional section
+	for section := 0; section < 3; section++ {
+		for {
+			var h dnsmessage.ResourceHeader
+			switch section {
+			case 0:
+				h, err = p.AnswerHeader()
+			case 1:
+				h, err = p.AuthorityHeader()
+			default:
+				h, err = p.AdditionalHeader()
+			}
+			if err == dnsmessage.ErrSectionDone {
+				break
+			}
+			if err != nil {
+				return nil
+			}
+			name := h.Name.String()
+			switch h.Type {
+			case dnsmessage.TypePTR:
+				r, err := p.PTRResource()
+				if err != nil {
+					return nil
+				}
+				if strings.EqualFold(name, ServiceType) {
+					getInstance(r.PTR.String())
+				}
+			case dnsmessage.TypeSRV:
+				r, err := p.SRVResource()
+				if err != nil {
+					return nil
+				}
+				svc := getInstance(name)
+				svc.Port = int(r.Port)
+				svc.Host = strings.TrimSuffix(r.Target.String(), ".local.")
+				targets[name] = r.Target.String()
+			case dnsmessage.TypeTXT:
+				r, err := p.TXTResource()
+				if err != nil {
+					return nil
+				}
+				getInstance(name).TXT = r.TXT
+			case dnsmessage.TypeA:
+				r, err := p.AResource()
+				if err != nil {
+					return nil
+				}
+				hosts[name] = net.IP(r.A[:])
+			default:
+				if err := skip(&p, section); err != nil {
+					return nil
+				}
+			}
+		}
+	}
+
+	services := []*Service{}
+	for name, svc := range instances {
+		if !strings.HasSuffix(strings.ToLower(name), ServiceType) || svc.Port == 0 {
+			continue
+		}
+		svc.IP = hosts[targets[name]]
+		services = append(services, svc)
+	}
+	return services
+}
+
+func skip(p *dnsmessage.Parser, section int) error {
+	switch section {
+	case 0:
+		return p.SkipAnswer()
+	case 1:
+		return p.SkipAuthority()
+	default:
+		return p.SkipAdditional()
+	}
+}
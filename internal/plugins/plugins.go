@@ -0,0 +1,169 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugins
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+)
+
+// Prefix is the prefix of the executable name for a plugin, so a plugin named "seed"
+// is an executable called "ff-seed", which is run with "ff seed"
+const Prefix = "ff-"
+
+type Plugin struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// List finds all of the plugins in the plugins directory and on the PATH. If the same plugin
+// is found more than once, the first one wins, with the plugins directory searched first.
+func List() ([]*Plugin, error) {
+	dirs := append([]string{constants.PluginsDir}, filepath.SplitList(os.Getenv("PATH"))...)
+	found := make(map[string]*Plugin)
+	for _, dir := range dirs {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			// Missing or unreadable directories on the PATH are skipped
+			continue
+		}
+		for _, f := range files {
+			name, ok := pluginName(f.Name())
+			if !ok || f.IsDir() || !isExecutable(f) {
+				continue
+			}
+			if _, exists := found[name]; !exists {
+				found[name] = &Plugin{Name: name, Path: filepath.Join(dir, f.Name())}
+			}
+		}
+	}
+	plugins := make([]*Plugin, 0, len(found))
+	for _, p := range found {
+		plugins = append(plugins, p)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins, nil
+}
+
+// Find returns the plugin with the given name, or nil if it is not installed
+func Find(name string) (*Plugin, error) {
+	plugins, err := List()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range plugins {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+// Install copies a plugin executable from a local path or an http(s) URL into the plugins directory
+func Install(source string) (*Plugin, error) {
+	filename := filepath.Base(source)
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		filename = source[strings.LastIndex(source, "/")+1:]
+	}
+	name, ok := pluginName(filename)
+	if !ok {
+		return nil, fmt.Errorf("plugin executables must be named '%s<name>', but got '%s'", Prefix, filename)
+	}
+	if err := os.MkdirAll(constants.PluginsDir, 0755); err != nil {
+		return nil, err
+	}
+
+	var reader io.ReadCloser
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		resp, err := http.Get(source)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to download plugin from %s [%d]", source, resp.StatusCode)
+		}
+		reader = resp.Body
+	} else {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		reader = f
+	}
+	defer reader.Close()
+
+	dest := filepath.Join(constants.PluginsDir, filename)
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return nil, err
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, reader); err != nil {
+		return nil, err
+	}
+	return &Plugin{Name: name, Path: dest}, nil
+}
+
+// Run executes the plugin with the given args, connected to the current stdin, stdout and stderr. The plugin
+// is told where the stacks directory and the CLI binary are through environment variables, so it can read
+// stack state or call back into the CLI. The exit code of the plugin is returned.
+func Run(p *Plugin, args []string) (int, error) {
+	cmd := exec.Command(p.Path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), fmt.Sprintf("FIREFLY_STACKS_DIR=%s", constants.StacksDir))
+	if self, err := os.Executable(); err == nil {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("FIREFLY_CLI=%s", self))
+	}
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return exitErr.ExitCode(), nil
+		}
+		return 1, err
+	}
+	return 0, nil
+}
+
+func pluginName(filename string) (string, bool) {
+	if !strings.HasPrefix(filename, Prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(filename, Prefix)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, ".exe")
+	}
+	return name, name != ""
+}
+
+func isExecutable(f os.FileInfo) bool {
+	if runtime.GOOS == "windows" {
+		return strings.HasSuffix(f.Name(), ".exe")
+	}
+	return f.Mode()&0111 != 0
+}
@@ -0,0 +1,92 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package random
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+// Epoch is the time that a stack created with --deterministic uses wherever the current time would be recorded
+var Epoch = time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// seededReader produces the SHA-256 of the seed, the label and a block counter, one block at a time
+type seededReader struct {
+	seed    int64
+	label   string
+	counter uint64
+	buf     []byte
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			h := sha256.New()
+			_ = binary.Write(h, binary.BigEndian, r.seed)
+			h.Write([]byte(r.label))
+			h.Write([]byte{0})
+			_ = binary.Write(h, binary.BigEndian, r.counter)
+			r.buf = h.Sum(nil)
+			r.counter++
+		}
+		c := copy(p[n:], r.buf)
+		r.buf = r.buf[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// Reader returns the source of the random bytes of a generated key, token or identifier. For a stack
+// created with --deterministic the bytes are derived from the seed of the stack and the label, so each
+// label always gets the same bytes for the same seed. They are predictable, so must only be used for
+// tests and demos. Otherwise the bytes come from crypto/rand.
+func Reader(stack *types.Stack, label string) io.Reader {
+	if stack == nil || stack.Seed == nil {
+		return rand.Reader
+	}
+	return &seededReader{seed: *stack.Seed, label: label}
+}
+
+// Bytes reads n bytes from the Reader for the label
+func Bytes(stack *types.Stack, label string, n int) []byte {
+	b := make([]byte, n)
+	_, _ = io.ReadFull(Reader(stack, label), b)
+	return b
+}
+
+// UUID returns a version 4 UUID from the Reader for the label
+func UUID(stack *types.Stack, label string) string {
+	b := Bytes(stack, label, 16)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// Now returns the current time, or Epoch for a stack created with --deterministic
+func Now(stack *types.Stack) time.Time {
+	if stack != nil && stack.Seed != nil {
+		return Epoch
+	}
+	return time.Now()
+}
@@ -0,0 +1,50 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package random
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeededReader(t *testing.T) {
+	seed := int64(42)
+	stack := &types.Stack{Seed: &seed}
+
+	a := Bytes(stack, "members/0/key", 100)
+	assert.Equal(t, a, Bytes(stack, "members/0/key", 100))
+	assert.NotEqual(t, a, Bytes(stack, "members/1/key", 100))
+	// Reading in smaller chunks gives the same stream
+	assert.Equal(t, a[:32], Bytes(stack, "members/0/key", 32))
+
+	other := int64(43)
+	assert.NotEqual(t, a, Bytes(&types.Stack{Seed: &other}, "members/0/key", 100))
+
+	assert.Equal(t, UUID(stack, "proposals/0"), UUID(stack, "proposals/0"))
+	assert.Regexp(t, "^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$", UUID(stack, "proposals/0"))
+	assert.Equal(t, Epoch, Now(stack))
+}
+
+func TestUnseededReader(t *testing.T) {
+	stack := &types.Stack{}
+	assert.Equal(t, rand.Reader, Reader(stack, "members/0/key"))
+	assert.NotEqual(t, Bytes(stack, "members/0/key", 32), Bytes(stack, "members/0/key", 32))
+	assert.NotEqual(t, Epoch, Now(stack))
+}
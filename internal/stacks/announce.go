@@ -0,0 +1,90 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/mdns"
+)
+
+// GetAnnouncement returns the mDNS service that advertises the API, UI and sandbox of each member
+// of the stack at the given address. If no address is given, the address of the default route is used.
+func (s *StackManager) GetAnnouncement(address string) (*mdns.Service, error) {
+	if s.Stack.BindAddress == "" || net.ParseIP(s.Stack.BindAddress).IsLoopback() {
+		return nil, fmt.Errorf("stack '%s' is only reachable from this machine. create it with --bind-address 0.0.0.0 to announce it", s.Stack.Name)
+	}
+
+	var ip net.IP
+	if address != "" {
+		ip = net.ParseIP(address)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid address '%s'", address)
+		}
+	} else {
+		var err error
+		if ip, err = getLANAddress(); err != nil {
+			return nil, err
+		}
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	// Only the first label is used, so the name can be published under .local
+	hostname = strings.Split(hostname, ".")[0]
+
+	txt := []string{
+		fmt.Sprintf("stack=%s", s.Stack.Name),
+		fmt.Sprintf("members=%d", len(s.Stack.Members)),
+	}
+	for _, member := range s.Stack.Members {
+		if s.Stack.APIToken(member) != "" {
+			txt = append(txt, "auth=basic")
+			break
+		}
+	}
+	for _, member := range s.Stack.Members {
+		txt = append(txt, fmt.Sprintf("api%s=http://%s:%d/api/v1", member.ID, ip, member.ExposedFireflyPort))
+		txt = append(txt, fmt.Sprintf("ui%s=http://%s:%d/ui", member.ID, ip, member.ExposedFireflyPort))
+		if s.Stack.SandboxEnabled {
+			txt = append(txt, fmt.Sprintf("sandbox%s=http://%s:%d", member.ID, ip, member.ExposedSandboxPort))
+		}
+	}
+
+	return &mdns.Service{
+		Instance: fmt.Sprintf("%s-%s", s.Stack.Name, hostname),
+		Host:     hostname,
+		IP:       ip,
+		Port:     s.Stack.Members[0].ExposedFireflyPort,
+		TXT:      txt,
+	}, nil
+}
+
+func getLANAddress() (net.IP, error) {
+	// Connecting a UDP socket doesn't send anything, but picks the address of the outbound interface
+	conn, err := net.Dial("udp4", "224.0.0.251:5353")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the LAN address of this machine. use --address to set it: %s", err)
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
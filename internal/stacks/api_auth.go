@@ -0,0 +1,187 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/internal/core"
+	"github.com/hyperledger/firefly-cli/internal/random"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/blowfish"
+)
+
+const bcryptCost = bcrypt.DefaultCost
+
+// bcryptEncoding is the unpadded base64 alphabet that bcrypt hashes are written in
+var bcryptEncoding = base64.NewEncoding("./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789").WithPadding(base64.NoPadding)
+
+// bcryptMagic is encrypted with the expanded key to give the hash
+var bcryptMagic = []byte("OrpheanBeholderScryDoubt")
+
+func GenerateAPIAuthToken(r io.Reader) string {
+	token := make([]byte, 16)
+	_, _ = io.ReadFull(r, token)
+	return hex.EncodeToString(token)
+}
+
+// hashPassword returns the bcrypt hash of a password. On a deterministic stack the salt is derived from
+// the seed and the label, so the password files are the same on every run.
+func hashPassword(stack *types.Stack, label, password string) ([]byte, error) {
+	if stack.Seed == nil {
+		return bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	}
+	return hashPasswordWithSalt(random.Reader(stack, label), password)
+}
+
+// hashPasswordWithSalt returns the $2a$ bcrypt hash of a password, the same as bcrypt.GenerateFromPassword
+// does, except that the salt is read from r. golang.org/x/crypto/bcrypt always takes its salt from
+// crypto/rand.
+func hashPasswordWithSalt(r io.Reader, password string) ([]byte, error) {
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(r, salt); err != nil {
+		return nil, err
+	}
+	// The trailing NULL is part of the key, for compatibility with the C implementations
+	key := append([]byte(password), 0)
+	c, err := blowfish.NewSaltedCipher(key, salt)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < 1<<bcryptCost; i++ {
+		blowfish.ExpandKey(key, c)
+		blowfish.ExpandKey(salt, c)
+	}
+	cipherData := make([]byte, len(bcryptMagic))
+	copy(cipherData, bcryptMagic)
+	for i := 0; i < len(cipherData); i += 8 {
+		for j := 0; j < 64; j++ {
+			c.Encrypt(cipherData[i:i+8], cipherData[i:i+8])
+		}
+	}
+	// Only 23 of the 24 encrypted bytes are written, again for compatibility
+	return []byte(fmt.Sprintf("$2a$%02d$%s%s", bcryptCost, bcryptEncoding.EncodeToString(salt), bcryptEncoding.EncodeToString(cipherData[:23]))), nil
+}
+
+// writeAPIPasswordFile writes the htpasswd style files that FireFly core uses to check
+// basic auth credentials on its API. Members with their own token get their own file.
+func (s *StackManager) writeAPIPasswordFile() error {
+	files := make(map[string]string)
+	for _, member := range s.Stack.Members {
+		if token := s.Stack.APIToken(member); token != "" {
+			files[s.Stack.APIPasswordFileName(member)] = token
+		}
+	}
+	for filename, token := range files {
+		hash, err := hashPassword(s.Stack, fmt.Sprintf("api-passwords/%s", filename), token)
+		if err != nil {
+			return err
+		}
+		content := fmt.Sprintf("%s:%s\n", constants.APIAuthUsername, hash)
+		for _, dir := range s.configDirs() {
+			if err := ioutil.WriteFile(filepath.Join(dir, filename), []byte(content), 0755); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setAPIAuth sets the credentials the CLI sends to the FireFly API of each member
+func (s *StackManager) setAPIAuth() {
+	if s.Stack.APIAuthToken != "" {
+		core.SetBasicAuth(constants.APIAuthUsername, s.Stack.APIAuthToken)
+	}
+	for _, member := range s.Stack.Members {
+		if member.APIToken != "" {
+			core.SetBasicAuthForPort(member.ExposedFireflyPort, constants.APIAuthUsername, member.APIToken)
+		}
+	}
+}
+
+// GetAPIToken returns the password the FireFly API of a member requires
+func (s *StackManager) GetAPIToken(memberIndex int) (string, error) {
+	member, err := s.getMember(memberIndex)
+	if err != nil {
+		return "", err
+	}
+	token := s.Stack.APIToken(member)
+	if token == "" {
+		return "", fmt.Errorf("the FireFly API of stack '%s' does not require a token. create the stack with --member-api-tokens or --api-auth to require one", s.Stack.Name)
+	}
+	return token, nil
+}
+
+// RotateAPIToken replaces the token of a member with a new one, and restarts FireFly core so the old
+// token stops working. On a stack where all members share a token, the shared token is replaced and
+// every member is restarted.
+func (s *StackManager) RotateAPIToken(memberIndex int) (string, error) {
+	member, err := s.getMember(memberIndex)
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.GetAPIToken(memberIndex); err != nil {
+		return "", err
+	}
+	token := GenerateAPIAuthToken(rand.Reader)
+	members := []*types.Organization{member}
+	if member.APIToken != "" {
+		member.APIToken = token
+	} else {
+		s.Stack.APIAuthToken = token
+		members = s.Stack.Members
+	}
+	if err := s.writeAPIPasswordFile(); err != nil {
+		return "", err
+	}
+	if err := s.writeStackJSON(); err != nil {
+		return "", err
+	}
+	if err := s.writeAppEnv(); err != nil {
+		return "", err
+	}
+	s.setAPIAuth()
+
+	hasBeenRun, err := s.Stack.HasRunBefore()
+	if err != nil || !hasBeenRun {
+		return token, err
+	}
+	var services []string
+	for _, m := range members {
+		if m.External {
+			s.Log.Warn(fmt.Sprintf("member %s runs outside docker. restart its FireFly core to use the new token", m.ID))
+			continue
+		}
+		services = append(services, fmt.Sprintf("firefly_core_%s", m.ID))
+	}
+	if len(services) > 0 {
+		s.Log.Info(fmt.Sprintf("restarting %s to load the new token", strings.Join(services, ", ")))
+		if err := s.runDockerComposeCommand(append([]string{"restart"}, services...)...); err != nil {
+			return "", err
+		}
+	}
+	return token, nil
+}
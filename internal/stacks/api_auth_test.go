@@ -0,0 +1,90 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestWriteAPIPasswordFileMemberTokens(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "init", "config"), 0755))
+	s := &StackManager{Stack: &types.Stack{
+		Name:    "authtest",
+		InitDir: filepath.Join(dir, "init"),
+		Members: []*types.Organization{
+			{ID: "0", APIToken: "token0"},
+			{ID: "1", APIToken: "token1"},
+		},
+	}}
+	assert.NoError(t, s.writeAPIPasswordFile())
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "init", "config", "api_users_1"))
+	assert.NoError(t, err)
+	parts := strings.SplitN(strings.TrimSpace(string(b)), ":", 2)
+	assert.Equal(t, "firefly", parts[0])
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(parts[1]), []byte("token1")))
+	assert.NoFileExists(t, filepath.Join(dir, "init", "config", "api_users"))
+}
+
+func TestGetAPIToken(t *testing.T) {
+	s := &StackManager{Stack: &types.Stack{
+		Name:         "authtest",
+		APIAuthToken: "shared",
+		Members: []*types.Organization{
+			{ID: "0"},
+			{ID: "1", APIToken: "token1"},
+		},
+	}}
+	token, err := s.GetAPIToken(0)
+	assert.NoError(t, err)
+	assert.Equal(t, "shared", token)
+	token, err = s.GetAPIToken(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "token1", token)
+
+	s.Stack.APIAuthToken = ""
+	_, err = s.GetAPIToken(0)
+	assert.Regexp(t, "does not require a token", err)
+}
+
+func TestHashPassword(t *testing.T) {
+	seed := int64(42)
+	stack := &types.Stack{Seed: &seed}
+	hash, err := hashPassword(stack, "api-passwords/api_users", "token0")
+	assert.NoError(t, err)
+	assert.NoError(t, bcrypt.CompareHashAndPassword(hash, []byte("token0")))
+	assert.Error(t, bcrypt.CompareHashAndPassword(hash, []byte("token1")))
+	cost, err := bcrypt.Cost(hash)
+	assert.NoError(t, err)
+	assert.Equal(t, bcrypt.DefaultCost, cost)
+
+	again, err := hashPassword(stack, "api-passwords/api_users", "token0")
+	assert.NoError(t, err)
+	assert.Equal(t, hash, again)
+	other, err := hashPassword(&types.Stack{}, "api-passwords/api_users", "token0")
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, other)
+}
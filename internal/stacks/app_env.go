@@ -0,0 +1,86 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+// GetAppEnv returns the contents of a dotenv file containing the connection settings
+// an application needs to talk to the given member of the stack
+func (s *StackManager) GetAppEnv(memberIndex int) (string, error) {
+	member, err := s.getMember(memberIndex)
+	if err != nil {
+		return "", err
+	}
+
+	vars := [][2]string{
+		{"FIREFLY_STACK_NAME", s.Stack.Name},
+		{"FIREFLY_MEMBER", member.ID},
+		{"FIREFLY_API_URL", fmt.Sprintf("http://127.0.0.1:%d/api/v1", member.ExposedFireflyPort)},
+		{"FIREFLY_WS_URL", fmt.Sprintf("ws://127.0.0.1:%d/ws", member.ExposedFireflyPort)},
+		{"FIREFLY_NAMESPACE", "default"},
+		{"FIREFLY_ORG_NAME", member.OrgName},
+		{"FIREFLY_NODE_NAME", member.NodeName},
+	}
+	if token := s.Stack.APIToken(member); token != "" {
+		vars = append(vars, [2]string{"FIREFLY_API_USERNAME", constants.APIAuthUsername}, [2]string{"FIREFLY_API_PASSWORD", token})
+	}
+	if member.Account != nil {
+		vars = append(vars, [2]string{"FIREFLY_ORG_KEY", s.blockchainProvider.GetOrgConfig(s.Stack, member).Key})
+	}
+	if member.TesseraPublicKey != "" {
+		vars = append(vars, [2]string{"FIREFLY_TESSERA_PUBLIC_KEY", member.TesseraPublicKey})
+	}
+	vars = append(vars, [2]string{"FIREFLY_CONNECTOR_URL", s.blockchainProvider.GetConnectorExternalURL(member)})
+	if s.Stack.BlockchainProvider.Equals(types.BlockchainProviderEthereum) {
+		vars = append(vars, [2]string{"FIREFLY_BLOCKCHAIN_RPC_URL", fmt.Sprintf("http://127.0.0.1:%d", s.Stack.ExposedBlockchainPort)})
+	}
+	switch {
+	case s.Stack.MessageQueue.Equals(types.MessageQueueNATS):
+		vars = append(vars, [2]string{"NATS_URL", fmt.Sprintf("nats://127.0.0.1:%d", s.Stack.ExposedMessageQueuePort)})
+	case s.Stack.MessageQueue.Equals(types.MessageQueueRedis):
+		vars = append(vars, [2]string{"REDIS_URL", fmt.Sprintf("redis://127.0.0.1:%d", s.Stack.ExposedMessageQueuePort)})
+	}
+	if member.External {
+		vars = append(vars, [2]string{"FIREFLY_CORE_CONFIG", s.ExternalCoreConfigFile(member)})
+	}
+	if s.Stack.SandboxEnabled {
+		vars = append(vars, [2]string{"FIREFLY_SANDBOX_URL", fmt.Sprintf("http://127.0.0.1:%d", member.ExposedSandboxPort)})
+	}
+
+	sb := strings.Builder{}
+	for _, v := range vars {
+		sb.WriteString(fmt.Sprintf("%s=%s\n", v[0], v[1]))
+	}
+	return sb.String(), nil
+}
+
+// writeAppEnv writes an app.env file for the first member to the stack directory
+func (s *StackManager) writeAppEnv() error {
+	env, err := s.GetAppEnv(0)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(s.Stack.StackDir, "app.env"), []byte(env), 0755)
+}
@@ -0,0 +1,235 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/core"
+	"github.com/hyperledger/firefly-cli/internal/random"
+)
+
+const (
+	ProposalPending  = "pending"
+	ProposalApproved = "approved"
+	ProposalRejected = "rejected"
+)
+
+type ProposalVote struct {
+	Member   string    `json:"member"`
+	Approved bool      `json:"approved"`
+	Time     time.Time `json:"time"`
+}
+
+// DeploymentProposal is a contract deployment that is held until enough members of the stack approve it
+type DeploymentProposal struct {
+	ID          string          `json:"id"`
+	Contract    string          `json:"contract"`
+	File        string          `json:"file"`
+	FileSHA256  string          `json:"fileSha256"`
+	Deployer    string          `json:"deployer"`
+	Required    int             `json:"required"`
+	Status      string          `json:"status"`
+	ApprovalURL string          `json:"approvalURL,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	Votes       []*ProposalVote `json:"votes"`
+
+	mux sync.Mutex
+}
+
+// NewDeploymentProposal records a pending deployment of a contract by a member, which needs the given
+// number of approvals. The proposal is kept in the approvals directory of the stack as an audit trail.
+func (s *StackManager) NewDeploymentProposal(filename, contractName string, memberIndex, required int) (*DeploymentProposal, error) {
+	member, err := s.getMember(memberIndex)
+	if err != nil {
+		return nil, err
+	}
+	if required < 1 || required > len(s.Stack.Members) {
+		return nil, fmt.Errorf("the number of approvals must be between 1 and the number of members in the stack (%d)", len(s.Stack.Members))
+	}
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(b)
+	// The number of earlier proposals keeps the IDs of a stack created with --deterministic unique
+	existing, _ := ioutil.ReadDir(filepath.Join(s.Stack.StackDir, "approvals"))
+	p := &DeploymentProposal{
+		ID:         random.UUID(s.Stack, fmt.Sprintf("proposals/%d", len(existing))),
+		Contract:   contractName,
+		File:       filename,
+		FileSHA256: hex.EncodeToString(hash[:]),
+		Deployer:   member.ID,
+		Required:   required,
+		Status:     ProposalPending,
+		CreatedAt:  random.Now(s.Stack),
+		Votes:      []*ProposalVote{},
+	}
+	return p, s.writeProposal(p)
+}
+
+func (s *StackManager) proposalFile(id string) string {
+	return filepath.Join(s.Stack.StackDir, "approvals", fmt.Sprintf("%s.json", id))
+}
+
+func (s *StackManager) writeProposal(p *DeploymentProposal) error {
+	if err := os.MkdirAll(filepath.Join(s.Stack.StackDir, "approvals"), 0755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(p, "", " ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.proposalFile(p.ID), b, 0755)
+}
+
+// ReadProposal loads a deployment proposal of the stack
+func (s *StackManager) ReadProposal(id string) (*DeploymentProposal, error) {
+	b, err := ioutil.ReadFile(s.proposalFile(id))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("deployment proposal '%s' does not exist in stack '%s'", id, s.Stack.Name)
+	} else if err != nil {
+		return nil, err
+	}
+	var p *DeploymentProposal
+	return p, json.Unmarshal(b, &p)
+}
+
+// Vote records the decision of a member. A single rejection rejects the proposal, and it is approved
+// once the required number of members have approved it. Each member can only vote once.
+func (s *StackManager) Vote(p *DeploymentProposal, memberIndex int, approve bool) error {
+	member, err := s.getMember(memberIndex)
+	if err != nil {
+		return err
+	}
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.Status != ProposalPending {
+		return fmt.Errorf("deployment proposal '%s' is already %s", p.ID, p.Status)
+	}
+	approvals := 0
+	for _, v := range p.Votes {
+		if v.Member == member.ID {
+			return fmt.Errorf("member %s has already voted on deployment proposal '%s'", member.ID, p.ID)
+		}
+		if v.Approved {
+			approvals++
+		}
+	}
+	p.Votes = append(p.Votes, &ProposalVote{Member: member.ID, Approved: approve, Time: random.Now(s.Stack)})
+	if !approve {
+		p.Status = ProposalRejected
+	} else if approvals+1 >= p.Required {
+		p.Status = ProposalApproved
+	}
+	return s.writeProposal(p)
+}
+
+type voteRequest struct {
+	Member  int  `json:"member"`
+	Approve bool `json:"approve"`
+}
+
+// WaitForApprovals serves an API on the given port that members call to vote on the proposal, and returns
+// once the proposal is approved. An error is returned if it is rejected, or the timeout passes first.
+//
+//	POST /proposals/<id>/votes {"member": 1, "approve": true}
+func (s *StackManager) WaitForApprovals(p *DeploymentProposal, port int, timeout time.Duration) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return err
+	}
+	p.ApprovalURL = fmt.Sprintf("http://%s/proposals/%s/votes", listener.Addr(), p.ID)
+	if err := s.writeProposal(p); err != nil {
+		listener.Close()
+		return err
+	}
+
+	decided := make(chan struct{})
+	var once sync.Once
+	mux := http.NewServeMux()
+	mux.HandleFunc(fmt.Sprintf("/proposals/%s/votes", p.ID), func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var req voteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := s.Vote(p, req.Member, req.Approve); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if req.Approve {
+			s.Log.Info(fmt.Sprintf("member %d approved the deployment", req.Member))
+		} else {
+			s.Log.Info(fmt.Sprintf("member %d rejected the deployment", req.Member))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		p.mux.Lock()
+		json.NewEncoder(w).Encode(p)
+		status := p.Status
+		p.mux.Unlock()
+		if status != ProposalPending {
+			once.Do(func() { close(decided) })
+		}
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Shutdown(context.Background())
+
+	select {
+	case <-decided:
+	case <-time.After(timeout):
+		return fmt.Errorf("timed out after %s waiting for approval of deployment proposal '%s'", timeout, p.ID)
+	}
+	if p.Status == ProposalRejected {
+		return fmt.Errorf("deployment proposal '%s' was rejected", p.ID)
+	}
+	return nil
+}
+
+// SendVote calls the approval API of a pending proposal on behalf of a member
+func (s *StackManager) SendVote(id string, memberIndex int, approve bool) (*DeploymentProposal, error) {
+	p, err := s.ReadProposal(id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != ProposalPending {
+		return nil, fmt.Errorf("deployment proposal '%s' is already %s", p.ID, p.Status)
+	}
+	if p.ApprovalURL == "" || !strings.HasPrefix(p.ApprovalURL, "http") {
+		return nil, fmt.Errorf("deployment proposal '%s' is not waiting for approvals over the API", p.ID)
+	}
+	var result *DeploymentProposal
+	err = core.Request(http.MethodPost, p.ApprovalURL, &voteRequest{Member: memberIndex, Approve: approve}, &result)
+	return result, err
+}
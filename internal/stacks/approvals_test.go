@@ -0,0 +1,90 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newApprovalsTestStackManager(t *testing.T) (*StackManager, string) {
+	dir := t.TempDir()
+	contract := filepath.Join(dir, "contract.json")
+	assert.NoError(t, ioutil.WriteFile(contract, []byte(`{}`), 0755))
+	s := &StackManager{
+		ctx: context.Background(),
+		Log: &log.StdoutLogger{LogLevel: log.Error},
+		Stack: &types.Stack{
+			Name:     "test",
+			StackDir: dir,
+			Members:  []*types.Organization{{ID: "0"}, {ID: "1"}, {ID: "2"}},
+		},
+	}
+	return s, contract
+}
+
+func TestVote(t *testing.T) {
+	s, contract := newApprovalsTestStackManager(t)
+	p, err := s.NewDeploymentProposal(contract, "simple_storage", 0, 2)
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Vote(p, 1, true))
+	assert.Equal(t, ProposalPending, p.Status)
+	assert.Regexp(t, "already voted", s.Vote(p, 1, true))
+	assert.NoError(t, s.Vote(p, 2, true))
+	assert.Equal(t, ProposalApproved, p.Status)
+	assert.Regexp(t, "already approved", s.Vote(p, 0, false))
+
+	saved, err := s.ReadProposal(p.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, ProposalApproved, saved.Status)
+	assert.Len(t, saved.Votes, 2)
+
+	_, err = s.NewDeploymentProposal(contract, "simple_storage", 0, 4)
+	assert.Regexp(t, "between 1 and the number of members", err)
+}
+
+func TestWaitForApprovalsRejected(t *testing.T) {
+	s, contract := newApprovalsTestStackManager(t)
+	p, err := s.NewDeploymentProposal(contract, "simple_storage", 0, 2)
+	assert.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		done <- s.WaitForApprovals(p, 0, 5*time.Second)
+	}()
+	for {
+		if saved, _ := s.ReadProposal(p.ID); saved != nil && saved.ApprovalURL != "" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	_, err = s.SendVote(p.ID, 1, true)
+	assert.NoError(t, err)
+	_, err = s.SendVote(p.ID, 1, true)
+	assert.Regexp(t, "409", err)
+	_, err = s.SendVote(p.ID, 2, false)
+	assert.NoError(t, err)
+	assert.Regexp(t, "was rejected", <-done)
+}
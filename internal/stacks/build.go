@@ -0,0 +1,139 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+)
+
+// resolveServices returns the compose services matching name. A name like "firefly_core" matches
+// the service for every member, while "firefly_core_0" matches only the first member's service.
+func (s *StackManager) resolveServices(compose *docker.DockerComposeConfig, name string) ([]string, error) {
+	if _, ok := compose.Services[name]; ok {
+		return []string{name}, nil
+	}
+	services := []string{}
+	for _, member := range s.Stack.Members {
+		serviceName := fmt.Sprintf("%s_%s", name, member.ID)
+		if _, ok := compose.Services[serviceName]; ok {
+			services = append(services, serviceName)
+		}
+	}
+	if len(services) == 0 {
+		available := make([]string, 0, len(compose.Services))
+		for serviceName := range compose.Services {
+			available = append(available, serviceName)
+		}
+		sort.Strings(available)
+		return nil, fmt.Errorf("service '%s' not found in stack '%s'. available services are: %s", name, s.Stack.Name, strings.Join(available, ", "))
+	}
+	return services, nil
+}
+
+func (s *StackManager) localImageTag(serviceName string) string {
+	return strings.ToLower(fmt.Sprintf("firefly-cli-local/%s-%s:latest", s.Stack.Name, serviceName))
+}
+
+// BuildLocalImage builds a docker image from a local build context, and switches the named service(s) in the
+// stack over to use it. If the stack has been started before, only the affected containers are recreated.
+func (s *StackManager) BuildLocalImage(serviceName, buildContext, dockerfile string) error {
+	compose := s.buildDockerCompose()
+	services, err := s.resolveServices(compose, serviceName)
+	if err != nil {
+		return err
+	}
+
+	buildContext, err = expandHomeDir(buildContext)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(buildContext); err != nil {
+		return fmt.Errorf("build context '%s' not found: %s", buildContext, err)
+	}
+
+	// All matching services share one image, named after the first
+	tag := s.localImageTag(serviceName)
+	args := []string{"build", "-t", tag}
+	if dockerfile != "" {
+		if dockerfile, err = expandHomeDir(dockerfile); err != nil {
+			return err
+		}
+		args = append(args, "-f", dockerfile)
+	}
+	args = append(args, buildContext)
+	s.Log.Info(fmt.Sprintf("building image %s from %s", tag, buildContext))
+	if err := docker.RunDockerCommand(s.ctx, s.Stack.StackDir, args...); err != nil {
+		return err
+	}
+
+	if s.Stack.LocalImages == nil {
+		s.Stack.LocalImages = make(map[string]string)
+	}
+	for _, service := range services {
+		s.Stack.LocalImages[service] = tag
+	}
+	return s.applyLocalImages(services)
+}
+
+// RevertLocalImage switches the named service(s) back to the image from the version manifest
+func (s *StackManager) RevertLocalImage(serviceName string) error {
+	compose := s.buildDockerCompose()
+	services, err := s.resolveServices(compose, serviceName)
+	if err != nil {
+		return err
+	}
+	for _, service := range services {
+		delete(s.Stack.LocalImages, service)
+	}
+	return s.applyLocalImages(services)
+}
+
+func (s *StackManager) applyLocalImages(services []string) error {
+	if err := s.writeStackJSON(); err != nil {
+		return err
+	}
+	if err := s.writeDockerCompose(s.buildDockerCompose()); err != nil {
+		return err
+	}
+	hasBeenRun, err := s.Stack.HasRunBefore()
+	if err != nil {
+		return err
+	}
+	if !hasBeenRun {
+		s.Log.Info(fmt.Sprintf("the stack has not been started yet - the new image will be used for %s on the first run", strings.Join(services, ", ")))
+		return nil
+	}
+	s.Log.Info(fmt.Sprintf("recreating %s", strings.Join(services, ", ")))
+	return s.runDockerComposeCommand(append([]string{"up", "-d", "--no-deps"}, services...)...)
+}
+
+func expandHomeDir(p string) (string, error) {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		p = filepath.Join(home, strings.TrimPrefix(p, "~"))
+	}
+	return filepath.Abs(p)
+}
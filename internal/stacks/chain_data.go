@@ -0,0 +1,93 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+// applyGenesisOptions uses the chain ID from a custom genesis for the stack, and returns the private
+// keys of any accounts exported along with a chain, so the members of the new stack sign with the
+// same keys as the stack the chain came from
+func (s *StackManager) applyGenesisOptions(options *types.InitOptions) ([]string, error) {
+	genesisPath := options.GenesisPath
+	if options.ChainDataPath != "" {
+		genesisPath = filepath.Join(options.ChainDataPath, ethereum.ChainDataGenesisFile)
+	}
+	if genesisPath == "" {
+		return nil, nil
+	}
+
+	genesis, err := ethereum.ReadGenesis(genesisPath)
+	if err != nil {
+		return nil, err
+	}
+	if chainID, ok := ethereum.GetGenesisChainID(genesis); ok {
+		s.Stack.ChainIDPtr = &chainID
+	}
+
+	if options.ChainDataPath == "" {
+		return nil, nil
+	}
+	accounts, err := ethereum.ReadChainDataAccounts(options.ChainDataPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	keys := make([]string, len(accounts))
+	for i, account := range accounts {
+		keys[i] = account.PrivateKey
+	}
+	return keys, nil
+}
+
+// ExportChain writes the blocks, genesis and member accounts of the stack's chain to a directory
+// that another stack can be created from with --chain-data. The blockchain node is stopped while
+// the blocks are exported.
+func (s *StackManager) ExportChain(outputDir string) (string, error) {
+	outputDir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", err
+	}
+	if err := s.blockchainProvider.ExportChain(outputDir); err != nil {
+		return "", err
+	}
+
+	accounts := make([]interface{}, 0, len(s.Stack.Members))
+	for _, member := range s.Stack.Members {
+		accounts = append(accounts, member.Account)
+	}
+	b, err := json.MarshalIndent(accounts, "", " ")
+	if err != nil {
+		return "", err
+	}
+	if err := ioutil.WriteFile(filepath.Join(outputDir, ethereum.ChainDataAccountsFile), b, 0755); err != nil {
+		return "", err
+	}
+	return outputDir, nil
+}
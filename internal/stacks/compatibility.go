@@ -0,0 +1,213 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/errcodes"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+type semver struct {
+	major, minor, patch int
+}
+
+var semverRegex = regexp.MustCompile(`^v?(\d+)\.(\d+)(?:\.(\d+))?(?:[-+].*)?$`)
+
+func parseSemver(tag string) (*semver, bool) {
+	match := semverRegex.FindStringSubmatch(tag)
+	if match == nil {
+		return nil, false
+	}
+	v := &semver{}
+	v.major, _ = strconv.Atoi(match[1])
+	v.minor, _ = strconv.Atoi(match[2])
+	v.patch, _ = strconv.Atoi(match[3])
+	return v, true
+}
+
+func (v *semver) less(o *semver) bool {
+	if v.major != o.major {
+		return v.major < o.major
+	}
+	if v.minor != o.minor {
+		return v.minor < o.minor
+	}
+	return v.patch < o.patch
+}
+
+func (v *semver) String() string {
+	return fmt.Sprintf("v%d.%d.%d", v.major, v.minor, v.patch)
+}
+
+// compatibilityMatrix holds, for each minor release of FireFly core, the oldest version of this CLI that
+// can set it up, and the oldest version of each component that works with it. Components are named as in
+// the manifest. Add a row for each new FireFly release.
+var compatibilityMatrix = []struct {
+	core     string
+	cli      string
+	requires map[string]string
+}{
+	{"v1.0", "v1.0.0", map[string]string{
+		"ethconnect":          "v3.1.0",
+		"fabconnect":          "v0.9.0",
+		"dataexchange-https":  "v1.0.0",
+		"tokens-erc1155":      "v1.0.0",
+		"tokens-erc20-erc721": "v1.0.0",
+		"signer":              "v0.9.0",
+	}},
+	{"v1.1", "v1.1.0", map[string]string{
+		"ethconnect":          "v3.2.0",
+		"evmconnect":          "v1.1.0",
+		"fabconnect":          "v0.9.0",
+		"dataexchange-https":  "v1.1.0",
+		"tokens-erc1155":      "v1.1.0",
+		"tokens-erc20-erc721": "v1.1.0",
+		"signer":              "v1.1.0",
+	}},
+	{"v1.2", "v1.2.0", map[string]string{
+		"ethconnect":          "v3.2.0",
+		"evmconnect":          "v1.2.0",
+		"fabconnect":          "v0.9.0",
+		"dataexchange-https":  "v1.2.0",
+		"tokens-erc1155":      "v1.2.0",
+		"tokens-erc20-erc721": "v1.2.0",
+		"signer":              "v1.1.0",
+	}},
+}
+
+// stackComponents returns the manifest entries of the components that the stack runs, by manifest name
+func stackComponents(stack *types.Stack) map[string]*types.ManifestEntry {
+	m := stack.VersionManifest
+	components := map[string]*types.ManifestEntry{}
+	switch {
+	case stack.BlockchainConnector.Equals(types.BlockchainConnectorEthconnect):
+		components["ethconnect"] = m.Ethconnect
+	case stack.BlockchainConnector.Equals(types.BlockchainConnectorEvmconnect):
+		components["evmconnect"] = m.Evmconnect
+	case stack.BlockchainConnector.Equals(types.BlockchainConnectorFabconnect):
+		components["fabconnect"] = m.Fabconnect
+	}
+	if stack.BlockchainNodeProvider.Equals(types.BlockchainNodeProviderBesu) || stack.BlockchainNodeProvider.Equals(types.BlockchainNodeProviderQuorum) || stack.BlockchainNodeProvider.Equals(types.BlockchainNodeProviderRemoteRPC) {
+		components["signer"] = m.Signer
+	}
+	if !stack.DisableDataExchange {
+		components["dataexchange-https"] = m.DataExchange
+	}
+	for _, tp := range stack.TokenProviders {
+		switch {
+		case tp.Equals(types.TokenProviderERC1155):
+			components["tokens-erc1155"] = m.TokensERC1155
+		case tp.Equals(types.TokenProviderERC20_ERC721):
+			components["tokens-erc20-erc721"] = m.TokensERC20ERC721
+		}
+	}
+	for name, entry := range components {
+		// Locally built images have no meaningful version
+		if entry == nil || entry.Local {
+			delete(components, name)
+		}
+	}
+	return components
+}
+
+// CheckCompatibility compares the versions in the manifest of the stack, and the version of the CLI, with
+// the compatibility matrix. Components, or a CLI, older than the FireFly core version needs are returned as
+// an error. Versions that can't be checked, such as "latest", and FireFly releases newer than this CLI
+// knows about, are returned as warnings. A CLI built from source has no release version, so it is not checked.
+func CheckCompatibility(stack *types.Stack, cliVersion string) (warnings []string, err error) {
+	if stack.VersionManifest == nil || stack.VersionManifest.FireFly == nil || stack.VersionManifest.FireFly.Local {
+		return nil, nil
+	}
+	coreTag := stack.VersionManifest.FireFly.Tag
+	components := stackComponents(stack)
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	core, ok := parseSemver(coreTag)
+	if !ok {
+		pinned := []string{}
+		for _, name := range names {
+			if _, ok := parseSemver(components[name].Tag); ok {
+				pinned = append(pinned, fmt.Sprintf("%s %s", name, components[name].Tag))
+			}
+		}
+		if len(pinned) > 0 {
+			warnings = append(warnings, fmt.Sprintf("FireFly core is '%s', so it cannot be checked against the pinned versions of %s. Mixing a moving core tag with pinned connectors often fails in obscure ways", coreTag, strings.Join(pinned, ", ")))
+		}
+		return warnings, nil
+	}
+
+	var requires map[string]string
+	var minCLI string
+	known := ""
+	for _, row := range compatibilityMatrix {
+		rowVersion, _ := parseSemver(row.core)
+		if rowVersion.major == core.major && rowVersion.minor == core.minor {
+			requires = row.requires
+			minCLI = row.cli
+		}
+		known = row.core
+	}
+	if requires == nil {
+		latest, _ := parseSemver(known)
+		if latest.less(core) {
+			warnings = append(warnings, fmt.Sprintf("FireFly core %s is newer than the releases this version of the CLI knows about (up to %s.x). Upgrade the CLI if the stack does not start", coreTag, known))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("FireFly core %s is older than the releases this version of the CLI knows about", coreTag))
+		}
+		return warnings, nil
+	}
+
+	problems := []string{}
+	if cli, ok := parseSemver(cliVersion); ok {
+		if minVersion, _ := parseSemver(minCLI); cli.less(minVersion) {
+			problems = append(problems, fmt.Sprintf("the CLI %s (FireFly core %s needs %s or later)", cliVersion, coreTag, minCLI))
+		}
+	}
+	for _, name := range names {
+		min, ok := requires[name]
+		if !ok {
+			continue
+		}
+		tag := components[name].Tag
+		version, ok := parseSemver(tag)
+		if !ok {
+			if tag == "" {
+				tag = "sha256:" + components[name].SHA
+			}
+			warnings = append(warnings, fmt.Sprintf("%s '%s' cannot be checked against FireFly core %s, which needs %s or later", name, tag, coreTag, min))
+			continue
+		}
+		minVersion, _ := parseSemver(min)
+		if version.less(minVersion) {
+			problems = append(problems, fmt.Sprintf("%s %s (FireFly core %s needs %s or later)", name, tag, coreTag, min))
+		}
+	}
+	if len(problems) > 0 {
+		return warnings, errcodes.New(errcodes.IncompatibleVersions, "these components are too old for the FireFly core version of the stack: %s", strings.Join(problems, "; "))
+	}
+	return warnings, nil
+}
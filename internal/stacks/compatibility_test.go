@@ -0,0 +1,87 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/internal/errcodes"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCompatibilityTestStack(core, ethconnect, tokens string) *types.Stack {
+	return &types.Stack{
+		BlockchainConnector:    types.BlockchainConnectorEthconnect,
+		BlockchainNodeProvider: types.BlockchainNodeProviderGeth,
+		TokenProviders:         []fftypes.FFEnum{types.TokenProviderERC20_ERC721},
+		VersionManifest: &types.VersionManifest{
+			FireFly:           &types.ManifestEntry{Tag: core},
+			Ethconnect:        &types.ManifestEntry{Tag: ethconnect},
+			DataExchange:      &types.ManifestEntry{Tag: "v1.1.2"},
+			TokensERC20ERC721: &types.ManifestEntry{Tag: tokens},
+			TokensERC1155:     &types.ManifestEntry{Tag: "v0.1.0"},
+		},
+	}
+}
+
+func TestCheckCompatibilityOK(t *testing.T) {
+	warnings, err := CheckCompatibility(newCompatibilityTestStack("v1.1.3", "v3.2.4", "v1.1.0-rc.1"), "")
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestCheckCompatibilityTooOld(t *testing.T) {
+	_, err := CheckCompatibility(newCompatibilityTestStack("v1.1.3", "v3.1.9", "v1.0.2"), "")
+	assert.Equal(t, "FF-CLI-0014", errcodes.CodeOf(err))
+	assert.Regexp(t, "ethconnect v3.1.9 .* tokens-erc20-erc721 v1.0.2", err)
+}
+
+func TestCheckCompatibilityMovingTags(t *testing.T) {
+	warnings, err := CheckCompatibility(newCompatibilityTestStack("latest", "v3.1.9", "latest"), "")
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 1)
+	assert.Regexp(t, "ethconnect v3.1.9", warnings[0])
+	assert.NotRegexp(t, "tokens", warnings[0])
+
+	warnings, err = CheckCompatibility(newCompatibilityTestStack("v1.1.0", "v3.2.0", "latest"), "")
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 1)
+	assert.Regexp(t, "tokens-erc20-erc721 'latest'", warnings[0])
+}
+
+func TestCheckCompatibilityUnknownCore(t *testing.T) {
+	warnings, err := CheckCompatibility(newCompatibilityTestStack("v9.0.0", "v3.1.0", "v1.0.0"), "")
+	assert.NoError(t, err)
+	assert.Regexp(t, "newer than the releases", warnings[0])
+}
+
+func TestCheckCompatibilityCLI(t *testing.T) {
+	stack := newCompatibilityTestStack("v1.1.3", "v3.2.4", "v1.1.0")
+	warnings, err := CheckCompatibility(stack, "v1.1.2")
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	_, err = CheckCompatibility(stack, "v1.0.5")
+	assert.Equal(t, "FF-CLI-0014", errcodes.CodeOf(err))
+	assert.Regexp(t, `the CLI v1.0.5 \(FireFly core v1.1.3 needs v1.1.0 or later\)`, err)
+
+	// A CLI built from source is not checked
+	_, err = CheckCompatibility(stack, "(devel)")
+	assert.NoError(t, err)
+}
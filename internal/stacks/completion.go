@@ -0,0 +1,85 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+var memberServiceRegex = regexp.MustCompile(`^(.+)_\d+$`)
+
+// ServiceNames returns the services in the generated docker compose file of a stack. With groups set,
+// the names that apply to every member, such as "firefly_core", are included too. The stack is not
+// loaded, so this is quick enough for shell completion.
+func ServiceNames(stackName string, groups bool) ([]string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(constants.StacksDir, stackName, "docker-compose.yml"))
+	if err != nil {
+		return nil, err
+	}
+	var compose docker.DockerComposeConfig
+	if err := yaml.Unmarshal(b, &compose); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(compose.Services))
+	groupCounts := make(map[string]int)
+	for name := range compose.Services {
+		names = append(names, name)
+		if match := memberServiceRegex.FindStringSubmatch(name); match != nil {
+			groupCounts[match[1]]++
+		}
+	}
+	if groups {
+		for group, count := range groupCounts {
+			if _, ok := compose.Services[group]; !ok && count > 1 {
+				names = append(names, group)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// MemberIndices returns the index of each member of a stack, read from its stack.json
+func MemberIndices(stackName string) ([]string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(constants.StacksDir, stackName, "stack.json"))
+	if err != nil {
+		return nil, err
+	}
+	var stack types.Stack
+	if err := json.Unmarshal(b, &stack); err != nil {
+		return nil, err
+	}
+	indices := make([]string, 0, len(stack.Members))
+	for i, member := range stack.Members {
+		index := i
+		if member.Index != nil {
+			index = *member.Index
+		}
+		indices = append(indices, fmt.Sprint(index))
+	}
+	return indices, nil
+}
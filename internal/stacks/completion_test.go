@@ -0,0 +1,53 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceNamesAndMemberIndices(t *testing.T) {
+	stacksDir := constants.StacksDir
+	defer func() { constants.StacksDir = stacksDir }()
+	constants.StacksDir = t.TempDir()
+	stackDir := filepath.Join(constants.StacksDir, "dev")
+	assert.NoError(t, os.MkdirAll(stackDir, 0755))
+	compose := "services:\n  firefly_core_0: {}\n  firefly_core_1: {}\n  geth: {}\n  tokens_0_0: {}\n"
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(stackDir, "docker-compose.yml"), []byte(compose), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(stackDir, "stack.json"), []byte(`{"members":[{"id":"0","index":0},{"id":"1","index":1}]}`), 0755))
+
+	names, err := ServiceNames("dev", false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"firefly_core_0", "firefly_core_1", "geth", "tokens_0_0"}, names)
+
+	names, err = ServiceNames("dev", true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"firefly_core", "firefly_core_0", "firefly_core_1", "geth", "tokens_0_0"}, names)
+
+	indices, err := MemberIndices("dev")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "1"}, indices)
+
+	_, err = ServiceNames("missing", false)
+	assert.Error(t, err)
+}
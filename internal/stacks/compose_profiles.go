@@ -0,0 +1,76 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+)
+
+// startComposeProfiles starts only the services in the given compose profiles, without their dependencies
+// from other profiles. For example, starting just "core" and "blockchain" leaves out the token connectors.
+func (s *StackManager) startComposeProfiles(profiles []string) error {
+	enabled := make(map[string]bool)
+	for _, profile := range profiles {
+		enabled[profile] = true
+	}
+	services := []string{}
+	for serviceName, service := range s.buildDockerCompose().Services {
+		for _, profile := range service.Profiles {
+			if enabled[profile] {
+				services = append(services, serviceName)
+				break
+			}
+		}
+	}
+	if len(services) == 0 {
+		return fmt.Errorf("no services in stack '%s' belong to profiles: %s", s.Stack.Name, strings.Join(profiles, ", "))
+	}
+	sort.Strings(services)
+
+	endPhase := s.startPhase("start containers")
+	if enabled[docker.ProfileBlockchain] {
+		if err := s.blockchainProvider.PreStart(); err != nil {
+			return err
+		}
+	}
+	s.Log.Info(fmt.Sprintf("starting %s", strings.Join(services, ", ")))
+	if err := s.runDockerComposeCommand(append([]string{"up", "-d", "--no-deps"}, services...)...); err != nil {
+		return err
+	}
+	if enabled[docker.ProfileBlockchain] {
+		if err := s.blockchainProvider.PostStart(false); err != nil {
+			return err
+		}
+	}
+	endPhase()
+
+	if enabled[docker.ProfileCore] {
+		endPhase = s.startPhase("readiness checks")
+		if err := s.ensureFireflyNodesUp(true); err != nil {
+			return err
+		}
+		if err := s.ensureWebSocketsUp(); err != nil {
+			return err
+		}
+		endPhase()
+	}
+	return s.writeAppEnv()
+}
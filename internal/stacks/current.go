@@ -0,0 +1,117 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+)
+
+// CurrentStack returns the stack that commands default to when they are not given one, and the file
+// it was read from. A workspace file in the working directory or one of its parents takes precedence
+// over the stack chosen with "ff use". The name is empty if there is no current stack.
+func CurrentStack() (name string, source string, err error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", "", err
+	}
+	if name, source, err := findWorkspaceStack(dir); err != nil || name != "" {
+		return name, source, err
+	}
+	name, err = readStackName(constants.CurrentStackFile)
+	if err != nil || name == "" {
+		return "", "", err
+	}
+	return name, constants.CurrentStackFile, nil
+}
+
+// findWorkspaceStack looks for a workspace file in dir and each of its parents
+func findWorkspaceStack(dir string) (string, string, error) {
+	for {
+		filename := filepath.Join(dir, constants.WorkspaceFileName)
+		// The ~/.firefly directory of the CLI has the same name, so only files count
+		if info, err := os.Stat(filename); err == nil && info.Mode().IsRegular() {
+			name, err := readStackName(filename)
+			if err != nil || name == "" {
+				return "", "", err
+			}
+			return name, filename, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", nil
+		}
+		dir = parent
+	}
+}
+
+// readStackName returns the first line of a file that is not blank or a # comment
+func readStackName(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line, nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+// SetCurrentStack makes a stack the one commands default to, outside of any workspace
+func SetCurrentStack(name string) error {
+	exists, err := CheckExists(name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("stack '%s' does not exist", name)
+	}
+	if err := os.MkdirAll(filepath.Dir(constants.CurrentStackFile), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(constants.CurrentStackFile, []byte(name+"\n"), 0644)
+}
+
+// ClearCurrentStack forgets the stack chosen with "ff use"
+func ClearCurrentStack() error {
+	if err := os.Remove(constants.CurrentStackFile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// clearCurrentStackIf forgets the stack chosen with "ff use" if it is the given one
+func clearCurrentStackIf(name string) error {
+	current, err := readStackName(constants.CurrentStackFile)
+	if err != nil || current != name {
+		return err
+	}
+	return ClearCurrentStack()
+}
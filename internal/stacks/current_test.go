@@ -0,0 +1,74 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrentStack(t *testing.T) {
+	stacksDir, currentStackFile := constants.StacksDir, constants.CurrentStackFile
+	defer func() { constants.StacksDir, constants.CurrentStackFile = stacksDir, currentStackFile }()
+	home := t.TempDir()
+	constants.StacksDir = filepath.Join(home, ".firefly", "stacks")
+	constants.CurrentStackFile = filepath.Join(home, ".firefly", "current_stack")
+	for _, name := range []string{"dev", "demo"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(constants.StacksDir, name), 0755))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(constants.StacksDir, name, "stack.json"), []byte(`{}`), 0755))
+	}
+
+	wd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(wd) }()
+	// A project under the home directory, whose ~/.firefly directory is not a workspace file
+	project := filepath.Join(home, "project", "src")
+	assert.NoError(t, os.MkdirAll(project, 0755))
+	assert.NoError(t, os.Chdir(project))
+
+	name, _, err := CurrentStack()
+	assert.NoError(t, err)
+	assert.Empty(t, name)
+
+	assert.Regexp(t, "stack 'missing' does not exist", SetCurrentStack("missing"))
+	assert.NoError(t, SetCurrentStack("dev"))
+	name, source, err := CurrentStack()
+	assert.NoError(t, err)
+	assert.Equal(t, "dev", name)
+	assert.Equal(t, constants.CurrentStackFile, source)
+
+	// The workspace file of a parent directory takes precedence
+	workspaceFile := filepath.Join(home, "project", constants.WorkspaceFileName)
+	assert.NoError(t, ioutil.WriteFile(workspaceFile, []byte("# the stack of this project\n\ndemo\n"), 0644))
+	name, source, err = CurrentStack()
+	assert.NoError(t, err)
+	assert.Equal(t, "demo", name)
+	assert.Equal(t, workspaceFile, source)
+
+	assert.NoError(t, os.Remove(workspaceFile))
+	assert.NoError(t, clearCurrentStackIf("demo"))
+	name, _, _ = CurrentStack()
+	assert.Equal(t, "dev", name)
+	assert.NoError(t, clearCurrentStackIf("dev"))
+	name, _, _ = CurrentStack()
+	assert.Empty(t, name)
+	assert.NoError(t, ClearCurrentStack())
+}
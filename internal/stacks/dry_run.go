@@ -0,0 +1,102 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+var dryRunPrintableExtensions = map[string]bool{
+	".yml":  true,
+	".yaml": true,
+	".json": true,
+	".env":  true,
+}
+
+// InitStackDryRun generates all of the files for a new stack in a temporary directory,
+// prints them to stdout, and then removes them again. Nothing is written to the stacks directory.
+func (s *StackManager) InitStackDryRun(stackName string, memberCount int, options *types.InitOptions) error {
+	tmpDir, err := ioutil.TempDir("", "firefly-dry-run-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := s.initStack(tmpDir, stackName, memberCount, options); err != nil {
+		return err
+	}
+	return printStackFiles(s.Stack.StackDir, tmpDir, constants.StacksDir)
+}
+
+// StartStackDryRun prints the docker-compose.yml for the stack, along with the docker
+// commands that would be run to pull images and bring the containers up.
+func (s *StackManager) StartStackDryRun() error {
+	composeFile := filepath.Join(s.Stack.StackDir, "docker-compose.yml")
+	if _, err := os.Stat(composeFile); os.IsNotExist(err) {
+		// A stack with the old file structure, whose compose file is only moved when it really starts
+		composeFile = filepath.Join(s.Stack.RuntimeDir, "docker-compose.yml")
+	}
+	b, err := ioutil.ReadFile(composeFile)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("# %s\n%s\n", composeFile, string(b))
+
+	hasBeenRun, err := s.Stack.HasRunBefore()
+	if err != nil {
+		return err
+	}
+	fmt.Print("# docker commands\n")
+	if !hasBeenRun {
+		if err := s.PullStack(&types.PullOptions{}); err != nil {
+			return err
+		}
+	}
+	if err := s.runDockerComposeCommand("up", "-d"); err != nil {
+		return err
+	}
+	if !hasBeenRun {
+		fmt.Print("\n# first time setup steps (blockchain initialization, contract deployment and identity registration) are not shown in dry-run mode\n")
+	}
+	return nil
+}
+
+func printStackFiles(stackDir, tmpDir, stacksDir string) error {
+	return filepath.Walk(stackDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !dryRunPrintableExtensions[filepath.Ext(p)] {
+			return nil
+		}
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		// Show the paths the files would have been written to, rather than the temporary directory
+		content := strings.ReplaceAll(string(b), tmpDir, stacksDir)
+		fmt.Printf("# %s\n%s\n\n", strings.Replace(p, tmpDir, stacksDir, 1), content)
+		return nil
+	})
+}
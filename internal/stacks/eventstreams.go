@@ -0,0 +1,69 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+func (s *StackManager) getMember(memberIndex int) (*types.Organization, error) {
+	if memberIndex < 0 || memberIndex >= len(s.Stack.Members) {
+		return nil, fmt.Errorf("member %d does not exist in stack '%s'", memberIndex, s.Stack.Name)
+	}
+	return s.Stack.Members[memberIndex], nil
+}
+
+// ListEventStreams returns the event streams and listeners configured in the blockchain connector for a member
+func (s *StackManager) ListEventStreams(memberIndex int) ([]*types.EventStream, error) {
+	member, err := s.getMember(memberIndex)
+	if err != nil {
+		return nil, err
+	}
+	return s.blockchainProvider.ListEventStreams(member)
+}
+
+// ResetEventStreams resets the checkpoint of every listener in the member's blockchain connector back to
+// fromBlock. If listenerID is set, only that listener is reset. The number of listeners reset is returned.
+func (s *StackManager) ResetEventStreams(memberIndex int, listenerID, fromBlock string) (int, error) {
+	member, err := s.getMember(memberIndex)
+	if err != nil {
+		return 0, err
+	}
+	streams, err := s.blockchainProvider.ListEventStreams(member)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, stream := range streams {
+		for _, listener := range stream.Listeners {
+			if listenerID != "" && listener.ID != listenerID {
+				continue
+			}
+			s.Log.Info(fmt.Sprintf("resetting listener %s on event stream %s to block %s", listener.ID, stream.ID, fromBlock))
+			if err := s.blockchainProvider.ResetEventStreamListener(member, stream.ID, listener.ID, fromBlock); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+	if listenerID != "" && count == 0 {
+		return 0, fmt.Errorf("listener '%s' not found for member %d", listenerID, memberIndex)
+	}
+	return count, nil
+}
@@ -0,0 +1,186 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// SetExternalCores moves the FireFly core of the given members out of the docker compose stack, so they
+// can be run as local processes, and moves the core of every other member back into the stack. The core
+// config of each member that moves is updated to reach the other services of the stack from where it runs.
+func (s *StackManager) SetExternalCores(memberIndices []int) error {
+	external := make(map[int]bool, len(memberIndices))
+	for _, index := range memberIndices {
+		if _, err := s.getMember(index); err != nil {
+			return err
+		}
+		external[index] = true
+	}
+	runBefore, err := s.Stack.HasRunBefore()
+	if err != nil {
+		return err
+	}
+	if !runBefore && len(external) >= len(s.Stack.Members) {
+		return fmt.Errorf("at least one FireFly core must run in a container the first time the stack starts, to extract and deploy the smart contracts")
+	}
+
+	moved := make(map[string]*types.Organization)
+	for _, member := range s.Stack.Members {
+		if member.External != external[*member.Index] {
+			member.External = external[*member.Index]
+			moved[member.ID] = member
+		}
+	}
+	if len(moved) == 0 {
+		return nil
+	}
+
+	generated := make(map[string]map[string]interface{}, len(moved))
+	for id, member := range moved {
+		b, err := yaml.Marshal(s.generateCoreConfig(member))
+		if err != nil {
+			return err
+		}
+		var config map[string]interface{}
+		if err := yaml.Unmarshal(b, &config); err != nil {
+			return err
+		}
+		generated[id] = config
+	}
+	if err := s.updateCoreConfigs(func(config map[string]interface{}, member *types.Organization) {
+		if _, ok := moved[member.ID]; !ok {
+			return
+		}
+		mergeCoreEndpoints(config, generated[member.ID])
+		for containerPath, hostPath := range s.corePaths(member) {
+			if member.External {
+				replacePathPrefix(config, containerPath, hostPath)
+			} else {
+				replacePathPrefix(config, hostPath, containerPath)
+			}
+		}
+	}); err != nil {
+		return err
+	}
+	if s.Stack.PrometheusEnabled {
+		if err := s.writePrometheusConfig(); err != nil {
+			return err
+		}
+	}
+	if err := s.writeStackJSON(); err != nil {
+		return err
+	}
+	if err := s.writeDockerCompose(s.buildDockerCompose()); err != nil {
+		return err
+	}
+
+	// Remove the containers of the cores that now run outside the stack. Compose would otherwise leave them running.
+	for _, member := range moved {
+		if member.External {
+			s.Log.Info(fmt.Sprintf("removing the FireFly core container of member %s", member.ID))
+			containerName := fmt.Sprintf("%s_firefly_core_%s", s.Stack.ResourcePrefix(), member.ID)
+			if err := docker.RunDockerCommand(s.ctx, s.Stack.StackDir, "rm", "-f", containerName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ExternalCoreConfigFile returns the path of the config file to start the FireFly core of a member with,
+// when it runs outside the stack
+func (s *StackManager) ExternalCoreConfigFile(member *types.Organization) string {
+	return filepath.Join(s.Stack.RuntimeDir, "config", fmt.Sprintf("firefly_core_%s.yml", member.ID))
+}
+
+// corePaths maps the paths that files are mounted at in the FireFly core container of a member
+// to the paths of the same files in the runtime directory
+func (s *StackManager) corePaths(member *types.Organization) map[string]string {
+	configDir := filepath.Join(s.Stack.RuntimeDir, "config")
+	return map[string]string{
+		constants.APIPasswordFile:   filepath.Join(configDir, s.Stack.APIPasswordFileName(member)),
+		constants.TenantPasswordDir: filepath.Join(configDir, "tenants"),
+	}
+}
+
+// mergeCoreEndpoints merges each plugin of a newly generated core config into the plugin with the same
+// name in an existing one, which keeps anything added to the existing config such as contract addresses
+func mergeCoreEndpoints(config, generated map[string]interface{}) {
+	plugins, _ := config["plugins"].(map[string]interface{})
+	if plugins == nil {
+		plugins = make(map[string]interface{})
+		config["plugins"] = plugins
+	}
+	generatedPlugins, _ := generated["plugins"].(map[string]interface{})
+	for pluginType, list := range generatedPlugins {
+		generatedList, _ := list.([]interface{})
+		existingList, _ := plugins[pluginType].([]interface{})
+		for _, g := range generatedList {
+			generatedPlugin, _ := g.(map[string]interface{})
+			merged := false
+			for _, e := range existingList {
+				if existingPlugin, ok := e.(map[string]interface{}); ok && existingPlugin["name"] == generatedPlugin["name"] {
+					mergeMaps(existingPlugin, generatedPlugin)
+					merged = true
+				}
+			}
+			if !merged {
+				existingList = append(existingList, generatedPlugin)
+			}
+		}
+		plugins[pluginType] = existingList
+	}
+}
+
+func mergeMaps(dst, src map[string]interface{}) {
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeMaps(dstMap, srcMap)
+		} else {
+			dst[k] = v
+		}
+	}
+}
+
+// replacePathPrefix replaces the prefix of every string value in a config that starts with it
+func replacePathPrefix(v interface{}, from, to string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			v[k] = replacePathPrefix(child, from, to)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = replacePathPrefix(child, from, to)
+		}
+	case string:
+		if v == from || strings.HasPrefix(v, from+"/") {
+			return to + strings.TrimPrefix(v, from)
+		}
+	}
+	return v
+}
@@ -0,0 +1,55 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/blockchain/fabric"
+)
+
+// EnrollFabricUser issues a new identity from the Fabric CA of a member's org, and adds it to the
+// accounts of the stack
+func (s *StackManager) EnrollFabricUser(memberIndex int, user, userType string) (string, error) {
+	fabricProvider, ok := s.blockchainProvider.(*fabric.FabricProvider)
+	if !ok {
+		return "", fmt.Errorf("stack '%s' is not a fabric stack", s.Stack.Name)
+	}
+	if memberIndex < 0 || memberIndex >= len(s.Stack.Members) {
+		return "", fmt.Errorf("invalid org index %d. the stack has %d members", memberIndex, len(s.Stack.Members))
+	}
+	member := s.Stack.Members[memberIndex]
+	if member.External {
+		return "", fmt.Errorf("cannot enroll users for member %d, as it is an external process", memberIndex)
+	}
+
+	account, err := fabricProvider.EnrollUser(member, user, userType)
+	if err != nil {
+		return "", err
+	}
+	s.Stack.State.Accounts = append(s.Stack.State.Accounts, account)
+	if err := s.writeStackStateJSON(s.Stack.RuntimeDir); err != nil {
+		return "", err
+	}
+
+	b, err := json.MarshalIndent(account, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,201 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+// Topology is a diagram of the services of a stack, grouped by the member they belong to
+type Topology struct {
+	Name    string
+	Network string
+	Groups  []*TopologyGroup
+	// Edges go from a service to a service it depends on, or shares the network namespace of
+	Edges [][2]string
+}
+
+type TopologyGroup struct {
+	ID       string
+	Label    string
+	Services []*TopologyService
+}
+
+type TopologyService struct {
+	Name     string
+	Image    string
+	Ports    []string
+	External bool
+}
+
+// memberOfServiceRegex matches the services of a member, such as "postgres_0", "tokens_0_1" or "latency_ipfs_0"
+var memberOfServiceRegex = regexp.MustCompile(`^.+?_(\d+)(_\d+)?$`)
+
+// GraphFormats are the formats a topology can be rendered in
+var GraphFormats = []string{"dot", "mermaid", "svg"}
+
+// Topology builds a diagram of the services, port mappings and dependencies of the stack from its docker compose config
+func (s *StackManager) Topology() *Topology {
+	return buildTopology(s.Stack, s.buildDockerCompose())
+}
+
+func buildTopology(stack *types.Stack, compose *docker.DockerComposeConfig) *Topology {
+	t := &Topology{
+		Name:    stack.Name,
+		Network: fmt.Sprintf("%s_default", stack.ResourcePrefix()),
+	}
+	shared := &TopologyGroup{ID: "shared", Label: "shared services"}
+	groups := make(map[string]*TopologyGroup)
+	for _, member := range stack.Members {
+		group := &TopologyGroup{
+			ID:    fmt.Sprintf("member_%s", member.ID),
+			Label: fmt.Sprintf("member %s: %s / %s", member.ID, member.OrgName, member.NodeName),
+		}
+		if member.External {
+			group.Services = append(group.Services, &TopologyService{
+				Name:     fmt.Sprintf("firefly_core_%s", member.ID),
+				Image:    "local process",
+				Ports:    []string{fmt.Sprint(member.ExposedFireflyPort), fmt.Sprint(member.ExposedFireflyAdminSPIPort)},
+				External: true,
+			})
+		}
+		groups[member.ID] = group
+		t.Groups = append(t.Groups, group)
+	}
+
+	names := make([]string, 0, len(compose.Services))
+	for name := range compose.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		service := compose.Services[name]
+		image := service.Image
+		if image == "" && service.Build != "" {
+			image = "built locally"
+		}
+		group := shared
+		if match := memberOfServiceRegex.FindStringSubmatch(name); match != nil && groups[match[1]] != nil {
+			group = groups[match[1]]
+		}
+		group.Services = append(group.Services, &TopologyService{Name: name, Image: image, Ports: service.Ports})
+
+		dependencies := make([]string, 0, len(service.DependsOn)+1)
+		for dependency := range service.DependsOn {
+			dependencies = append(dependencies, dependency)
+		}
+		if strings.HasPrefix(service.NetworkMode, "service:") {
+			dependencies = append(dependencies, strings.TrimPrefix(service.NetworkMode, "service:"))
+		}
+		sort.Strings(dependencies)
+		for _, dependency := range dependencies {
+			t.Edges = append(t.Edges, [2]string{name, dependency})
+		}
+	}
+	if len(shared.Services) > 0 {
+		t.Groups = append([]*TopologyGroup{shared}, t.Groups...)
+	}
+	return t
+}
+
+// Render writes the topology in one of the GraphFormats
+func (t *Topology) Render(format string) (string, error) {
+	switch format {
+	case "dot":
+		return t.DOT(), nil
+	case "mermaid":
+		return t.Mermaid(), nil
+	case "svg":
+		return t.SVG(), nil
+	default:
+		return "", fmt.Errorf("unknown graph format '%s'. valid formats are: %s", format, strings.Join(GraphFormats, ", "))
+	}
+}
+
+func (s *TopologyService) lines() []string {
+	lines := []string{s.Name}
+	if s.Image != "" {
+		lines = append(lines, s.Image)
+	}
+	return append(lines, s.Ports...)
+}
+
+// DOT renders the topology in the Graphviz DOT language, with a cluster for each member
+func (t *Topology) DOT() string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "digraph %q {\n", t.Name)
+	fmt.Fprintf(sb, "  label=%q;\n", fmt.Sprintf("%s (network %s)", t.Name, t.Network))
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, style=rounded, fontname=Helvetica, fontsize=10];\n")
+	for _, group := range t.Groups {
+		fmt.Fprintf(sb, "  subgraph %q {\n", "cluster_"+group.ID)
+		fmt.Fprintf(sb, "    label=%q;\n", group.Label)
+		for _, service := range group.Services {
+			style := ""
+			if service.External {
+				style = ", style=\"rounded,dashed\""
+			}
+			fmt.Fprintf(sb, "    %q [label=%q%s];\n", service.Name, strings.Join(service.lines(), "\n"), style)
+		}
+		sb.WriteString("  }\n")
+	}
+	for _, edge := range t.Edges {
+		fmt.Fprintf(sb, "  %q -> %q;\n", edge[0], edge[1])
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+var mermaidIDRegex = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+func mermaidID(name string) string {
+	return mermaidIDRegex.ReplaceAllString(name, "_")
+}
+
+func mermaidLabel(lines []string) string {
+	return strings.ReplaceAll(strings.Join(lines, "<br/>"), `"`, "#quot;")
+}
+
+// Mermaid renders the topology as a Mermaid flowchart, with a subgraph for each member
+func (t *Topology) Mermaid() string {
+	sb := &strings.Builder{}
+	sb.WriteString("flowchart LR\n")
+	for _, group := range t.Groups {
+		fmt.Fprintf(sb, "  subgraph %s[\"%s\"]\n", mermaidID(group.ID), mermaidLabel([]string{group.Label}))
+		for _, service := range group.Services {
+			fmt.Fprintf(sb, "    %s[\"%s\"]\n", mermaidID(service.Name), mermaidLabel(service.lines()))
+		}
+		sb.WriteString("  end\n")
+	}
+	for _, edge := range t.Edges {
+		fmt.Fprintf(sb, "  %s --> %s\n", mermaidID(edge[0]), mermaidID(edge[1]))
+	}
+	for _, group := range t.Groups {
+		for _, service := range group.Services {
+			if service.External {
+				fmt.Fprintf(sb, "  style %s stroke-dasharray: 5 5\n", mermaidID(service.Name))
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,115 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+const (
+	svgMargin      = 20
+	svgTitleHeight = 40
+	svgBoxWidth    = 240
+	svgLineHeight  = 14
+	svgBoxPadding  = 8
+	svgBoxGap      = 12
+	svgGroupPad    = 16
+	svgGroupHeader = 24
+	svgColumnGap   = 60
+)
+
+type svgBox struct {
+	x, y, height int
+}
+
+func (b svgBox) midY() int {
+	return b.y + b.height/2
+}
+
+// SVG renders the topology as a standalone SVG image, with a column for the shared services and for each member
+func (t *Topology) SVG() string {
+	body := &strings.Builder{}
+	boxes := make(map[string]svgBox)
+	width, height := svgMargin, svgMargin+svgTitleHeight
+	for i, group := range t.Groups {
+		groupX := svgMargin + i*(svgBoxWidth+2*svgGroupPad+svgColumnGap)
+		groupY := svgMargin + svgTitleHeight
+		y := groupY + svgGroupHeader
+		services := &strings.Builder{}
+		for _, service := range group.Services {
+			lines := service.lines()
+			box := svgBox{x: groupX + svgGroupPad, y: y, height: len(lines)*svgLineHeight + 2*svgBoxPadding}
+			boxes[service.Name] = box
+			dash := ""
+			if service.External {
+				dash = ` stroke-dasharray="5 5"`
+			}
+			fmt.Fprintf(services, `  <rect x="%d" y="%d" width="%d" height="%d" rx="6" fill="#ffffff" stroke="#1f6feb"%s/>`+"\n", box.x, box.y, svgBoxWidth, box.height, dash)
+			for j, line := range lines {
+				weight := ""
+				if j == 0 {
+					weight = ` font-weight="bold"`
+				}
+				fmt.Fprintf(services, `  <text x="%d" y="%d"%s>%s</text>`+"\n", box.x+svgBoxPadding, box.y+svgBoxPadding+(j+1)*svgLineHeight-3, weight, html.EscapeString(line))
+			}
+			y += box.height + svgBoxGap
+		}
+		groupHeight := y - groupY + svgGroupPad - svgBoxGap
+		fmt.Fprintf(body, `  <rect x="%d" y="%d" width="%d" height="%d" rx="10" fill="#f3f6fa" stroke="#8c959f"/>`+"\n", groupX, groupY, svgBoxWidth+2*svgGroupPad, groupHeight)
+		fmt.Fprintf(body, `  <text x="%d" y="%d" font-weight="bold">%s</text>`+"\n", groupX+svgGroupPad, groupY+17, html.EscapeString(group.Label))
+		body.WriteString(services.String())
+		width = groupX + svgBoxWidth + 2*svgGroupPad + svgMargin
+		if groupY+groupHeight+svgMargin > height {
+			height = groupY + groupHeight + svgMargin
+		}
+	}
+
+	edges := &strings.Builder{}
+	for _, edge := range t.Edges {
+		from, ok1 := boxes[edge[0]]
+		to, ok2 := boxes[edge[1]]
+		if !ok1 || !ok2 {
+			continue
+		}
+		var x1, x2, c1, c2 int
+		switch {
+		case from.x == to.x:
+			// Services in the same column are joined by a curve on their left side
+			x1, x2, c1, c2 = from.x, to.x, from.x-40, to.x-40
+		case from.x < to.x:
+			x1, x2 = from.x+svgBoxWidth, to.x
+			c1, c2 = x1+svgColumnGap, x2-svgColumnGap
+		default:
+			x1, x2 = from.x, to.x+svgBoxWidth
+			c1, c2 = x1-svgColumnGap, x2+svgColumnGap
+		}
+		fmt.Fprintf(edges, `  <path d="M %d %d C %d %d, %d %d, %d %d" fill="none" stroke="#6e7781" marker-end="url(#arrow)"/>`+"\n", x1, from.midY(), c1, from.midY(), c2, to.midY(), x2, to.midY())
+	}
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif" font-size="11">`+"\n", width, height, width, height)
+	sb.WriteString(`  <defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="6" markerHeight="6" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z" fill="#6e7781"/></marker></defs>` + "\n")
+	fmt.Fprintf(sb, `  <rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+	fmt.Fprintf(sb, `  <text x="%d" y="%d" font-size="16" font-weight="bold">%s</text>`+"\n", svgMargin, svgMargin+16, html.EscapeString(t.Name))
+	fmt.Fprintf(sb, `  <text x="%d" y="%d" fill="#57606a">network %s</text>`+"\n", svgMargin, svgMargin+32, html.EscapeString(t.Network))
+	sb.WriteString(body.String())
+	sb.WriteString(edges.String())
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
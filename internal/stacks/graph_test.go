@@ -0,0 +1,99 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTopology() *Topology {
+	index0, index1 := 0, 1
+	stack := &types.Stack{
+		Name: "graphtest",
+		Members: []*types.Organization{
+			{ID: "0", Index: &index0, OrgName: "org_0", NodeName: "node_0"},
+			{ID: "1", Index: &index1, OrgName: "org_1", NodeName: "node_1", External: true, ExposedFireflyPort: 5001, ExposedFireflyAdminSPIPort: 5201},
+		},
+	}
+	compose := &docker.DockerComposeConfig{
+		Services: map[string]*docker.Service{
+			"geth":                   {Image: "ethereum/client-go", Ports: []string{"127.0.0.1:5100:8545"}},
+			"firefly_core_0":         {Image: "ghcr.io/hyperledger/firefly", Ports: []string{"127.0.0.1:5000:5000"}, DependsOn: map[string]map[string]string{"postgres_0": {}, "tokens_0_0": {}}},
+			"postgres_0":             {Image: "postgres"},
+			"tokens_0_0":             {Image: "tokens"},
+			"tokens_1_0":             {Image: "tokens"},
+			"dataexchange_1":         {Build: "./dx"},
+			"latency_dataexchange_1": {Image: "nicolaka/netshoot", NetworkMode: "service:dataexchange_1"},
+		},
+	}
+	return buildTopology(stack, compose)
+}
+
+func TestBuildTopology(t *testing.T) {
+	topology := newTestTopology()
+	assert.Equal(t, "graphtest_default", topology.Network)
+	assert.Len(t, topology.Groups, 3)
+
+	groupServices := func(g *TopologyGroup) []string {
+		names := []string{}
+		for _, s := range g.Services {
+			names = append(names, s.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"geth"}, groupServices(topology.Groups[0]))
+	assert.Equal(t, []string{"firefly_core_0", "postgres_0", "tokens_0_0"}, groupServices(topology.Groups[1]))
+	assert.Equal(t, []string{"firefly_core_1", "dataexchange_1", "latency_dataexchange_1", "tokens_1_0"}, groupServices(topology.Groups[2]))
+	assert.True(t, topology.Groups[2].Services[0].External)
+	assert.Equal(t, "built locally", topology.Groups[2].Services[1].Image)
+	assert.Equal(t, [][2]string{
+		{"firefly_core_0", "postgres_0"},
+		{"firefly_core_0", "tokens_0_0"},
+		{"latency_dataexchange_1", "dataexchange_1"},
+	}, topology.Edges)
+}
+
+func TestRenderTopology(t *testing.T) {
+	topology := newTestTopology()
+
+	dot, err := topology.Render("dot")
+	assert.NoError(t, err)
+	assert.Contains(t, dot, `subgraph "cluster_member_0" {`)
+	assert.Contains(t, dot, `"firefly_core_0" -> "postgres_0";`)
+	assert.Contains(t, dot, `"firefly_core_1" [label="firefly_core_1\nlocal process\n5001\n5201", style="rounded,dashed"];`)
+
+	mermaid, err := topology.Render("mermaid")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(mermaid, "flowchart LR\n"))
+	assert.Contains(t, mermaid, `geth["geth<br/>ethereum/client-go<br/>127.0.0.1:5100:8545"]`)
+	assert.Contains(t, mermaid, "latency_dataexchange_1 --> dataexchange_1")
+
+	svg, err := topology.Render("svg")
+	assert.NoError(t, err)
+	assert.NoError(t, xml.Unmarshal([]byte(svg), new(interface{})))
+	assert.Contains(t, svg, "member 1: org_1 / node_1")
+	assert.Equal(t, 3, strings.Count(svg, "marker-end"))
+
+	_, err = topology.Render("png")
+	assert.Regexp(t, "unknown graph format 'png'", err)
+}
@@ -0,0 +1,76 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func failingInitOptions(t *testing.T) *types.InitOptions {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "manifest.json")
+	entry := `{"image":"example","tag":"latest"}`
+	manifest := fmt.Sprintf(`{"firefly":%[1]s,"ethconnect":%[1]s,"evmconnect":%[1]s,"fabconnect":%[1]s,"dataexchange-https":%[1]s,"tokens-erc1155":%[1]s,"tokens-erc20-erc721":%[1]s,"signer":%[1]s}`, entry)
+	assert.NoError(t, ioutil.WriteFile(manifestPath, []byte(manifest), 0755))
+	// Extra core config that does not render fails init after the stack directory is written
+	coreConfigPath := filepath.Join(dir, "core.yml")
+	assert.NoError(t, ioutil.WriteFile(coreConfigPath, []byte("{{ .Unclosed"), 0755))
+	return &types.InitOptions{
+		OrgNames:               []string{"org_0"},
+		NodeNames:              []string{"node_0"},
+		DatabaseProvider:       types.DatabaseSelectionSQLite.String(),
+		BlockchainProvider:     types.BlockchainProviderEthereum.String(),
+		BlockchainNodeProvider: types.BlockchainNodeProviderGeth.String(),
+		BlockchainConnector:    types.BlockchainConnectorEvmconnect.String(),
+		IPFSMode:               types.IPFSModePrivate.String(),
+		ManifestPath:           manifestPath,
+		ExtraCoreConfigPath:    coreConfigPath,
+		ServicesBasePort:       5100,
+		FireFlyBasePort:        5000,
+	}
+}
+
+func TestInitStackCleansUpAfterFailure(t *testing.T) {
+	stacksDir := constants.StacksDir
+	defer func() { constants.StacksDir = stacksDir }()
+	constants.StacksDir = t.TempDir()
+	ctx := log.WithLogger(context.Background(), &log.StdoutLogger{LogLevel: log.Error})
+
+	err := NewStackManager(ctx).InitStack("failed", 1, failingInitOptions(t))
+	assert.Regexp(t, "failed to parse config template", err)
+	_, err = os.Stat(filepath.Join(constants.StacksDir, "failed"))
+	assert.True(t, os.IsNotExist(err))
+
+	options := failingInitOptions(t)
+	options.KeepFailed = true
+	assert.Error(t, NewStackManager(ctx).InitStack("kept", 1, options))
+	assert.DirExists(t, filepath.Join(constants.StacksDir, "kept", "init"))
+
+	// A directory that was there before init is never removed
+	assert.Error(t, NewStackManager(ctx).InitStack("kept", 1, failingInitOptions(t)))
+	assert.DirExists(t, filepath.Join(constants.StacksDir, "kept", "init"))
+}
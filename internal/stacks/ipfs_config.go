@@ -17,13 +17,13 @@
 package stacks
 
 import (
-	"crypto/rand"
 	"encoding/hex"
+	"io"
 )
 
-func GenerateSwarmKey() string {
+func GenerateSwarmKey(r io.Reader) string {
 	key := make([]byte, 32)
-	rand.Read(key)
+	_, _ = io.ReadFull(r, key)
 	hexKey := hex.EncodeToString(key)
 	return "/key/swarm/psk/1.0.0/\n/base16/\n" + hexKey
 }
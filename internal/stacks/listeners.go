@@ -0,0 +1,58 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/hyperledger/firefly-cli/internal/core"
+)
+
+// ContractListener is the part of a FireFly contract listener needed to find it in the blockchain connector
+type ContractListener struct {
+	ID        string `json:"id"`
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace"`
+	BackendID string `json:"backendId"`
+}
+
+// BackfillListener replays the events of an existing FireFly contract listener from fromBlock, by resetting
+// the checkpoint of the matching listener in the member's blockchain connector. FireFly keeps the listener
+// and its subscriptions, and skips any events it has already recorded. The listener is found by ID or name.
+func (s *StackManager) BackfillListener(memberIndex int, namespace, listener, fromBlock string) (*ContractListener, error) {
+	if _, err := strconv.ParseUint(fromBlock, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid block number '%s'", fromBlock)
+	}
+	member, err := s.getMember(memberIndex)
+	if err != nil {
+		return nil, err
+	}
+	var contractListener *ContractListener
+	listenerURL := fmt.Sprintf("http://127.0.0.1:%d/api/v1/namespaces/%s/contracts/listeners/%s", member.ExposedFireflyPort, url.PathEscape(namespace), url.PathEscape(listener))
+	if err := core.Request("GET", listenerURL, nil, &contractListener); err != nil {
+		return nil, fmt.Errorf("failed to get contract listener '%s' from member %d: %s", listener, memberIndex, err)
+	}
+	if contractListener == nil || contractListener.BackendID == "" {
+		return nil, fmt.Errorf("contract listener '%s' has no listener in the blockchain connector", listener)
+	}
+	if _, err := s.ResetEventStreams(memberIndex, contractListener.BackendID, fromBlock); err != nil {
+		return nil, err
+	}
+	return contractListener, nil
+}
@@ -0,0 +1,48 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackfillListenerInvalidBlock(t *testing.T) {
+	s := &StackManager{Stack: &types.Stack{Members: []*types.Organization{{ID: "0"}}}}
+	_, err := s.BackfillListener(0, "default", "l1", "latest")
+	assert.Regexp(t, "invalid block number 'latest'", err)
+}
+
+func TestBackfillListenerNoBackendID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/namespaces/default/contracts/listeners/l1", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"l1","namespace":"default"}`))
+	}))
+	defer server.Close()
+	port, _ := strconv.Atoi(server.URL[strings.LastIndex(server.URL, ":")+1:])
+
+	s := &StackManager{Stack: &types.Stack{Members: []*types.Organization{{ID: "0", ExposedFireflyPort: port}}}}
+	_, err := s.BackfillListener(0, "default", "l1", "100")
+	assert.Regexp(t, "has no listener in the blockchain connector", err)
+}
@@ -0,0 +1,150 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/hyperledger/firefly-cli/internal/errcodes"
+	"github.com/hyperledger/firefly-cli/internal/log"
+)
+
+// StackSchemaVersion is the version of the stack.json and stackState.json files written by this
+// version of the CLI. Files without a schemaVersion are version 0. Whenever the format of
+// either file changes, bump this and add a migration for each file below.
+const StackSchemaVersion = 1
+
+// A migration upgrades the raw JSON of a file from the version at its index to the next version.
+// Each list must have StackSchemaVersion entries.
+type migration struct {
+	description string
+	migrate     func(doc map[string]interface{}) error
+}
+
+var stackMigrations = []migration{
+	{"record the blockchain node, connector and version manifest defaults of old stacks", migrateStackV0},
+}
+
+var stackStateMigrations = []migration{
+	{"replace null deployed contracts and accounts with empty lists", migrateStackStateV0},
+}
+
+// migrateFile upgrades a stack file to the current schema version. The original file is kept
+// as a backup next to it, before the upgraded file is written. A dry run writes neither.
+func migrateFile(l log.Logger, filename string, migrations []migration, dryRun bool) ([]byte, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %s", filename, err)
+	}
+	version := 0
+	if v, ok := doc["schemaVersion"].(float64); ok {
+		version = int(v)
+	}
+	if version > StackSchemaVersion {
+		return nil, errcodes.New(errcodes.StackFromNewerCLI, "%s has schema version %d, but this version of the CLI only supports up to version %d. please upgrade the CLI", filename, version, StackSchemaVersion)
+	}
+	if version == StackSchemaVersion {
+		return b, nil
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", filename, version)
+	if !dryRun {
+		if err := ioutil.WriteFile(backup, b, 0755); err != nil {
+			return nil, err
+		}
+	}
+	for ; version < StackSchemaVersion; version++ {
+		l.Debug(fmt.Sprintf("migrating %s to schema version %d: %s", filename, version+1, migrations[version].description))
+		if err := migrations[version].migrate(doc); err != nil {
+			return nil, fmt.Errorf("failed to migrate %s to schema version %d: %s", filename, version+1, err)
+		}
+	}
+	doc["schemaVersion"] = version
+
+	if b, err = json.MarshalIndent(doc, "", " "); err != nil {
+		return nil, err
+	}
+	if dryRun {
+		// The migrated stack is only used in memory, and upgraded for real the next time it is loaded
+		return b, nil
+	}
+	if err := ioutil.WriteFile(filename, b, 0755); err != nil {
+		return nil, err
+	}
+	// Written to stderr so commands whose output is parsed, such as "ff env", are not affected
+	fmt.Fprintf(os.Stderr, "upgraded %s to schema version %d. the original file was saved as %s\n", filename, version, backup)
+	return b, nil
+}
+
+func migrateStackV0(stack map[string]interface{}) error {
+	// The node used to be set as the blockchain provider
+	switch stack["blockchainProvider"] {
+	case "geth", "besu":
+		stack["blockchainNodeProvider"] = stack["blockchainProvider"]
+		stack["blockchainProvider"] = "ethereum"
+	}
+
+	// Ethconnect and fabconnect were the only connectors before the connector was configurable
+	if connector, _ := stack["blockchainConnector"].(string); connector == "" {
+		switch stack["blockchainProvider"] {
+		case "ethereum":
+			stack["blockchainConnector"] = "ethconnect"
+		case "fabric":
+			stack["blockchainConnector"] = "fabconnect"
+		}
+	}
+
+	// Stacks created before the version manifest existed used the latest images
+	manifest, ok := stack["versionManifest"].(map[string]interface{})
+	if !ok {
+		manifest = map[string]interface{}{
+			"firefly":             map[string]interface{}{"image": "ghcr.io/hyperledger/firefly", "tag": "latest"},
+			"ethconnect":          map[string]interface{}{"image": "ghcr.io/hyperledger/firefly-ethconnect", "tag": "latest"},
+			"fabconnect":          map[string]interface{}{"image": "ghcr.io/hyperledger/firefly-fabconnect", "tag": "latest"},
+			"dataexchange-https":  map[string]interface{}{"image": "ghcr.io/hyperledger/firefly-dataexchange-https", "tag": "latest"},
+			"tokens-erc1155":      map[string]interface{}{"image": "ghcr.io/hyperledger/firefly-tokens-erc1155", "tag": "latest"},
+			"tokens-erc20-erc721": map[string]interface{}{"image": "ghcr.io/hyperledger/firefly-tokens-erc20-erc721", "tag": "latest"},
+		}
+		stack["versionManifest"] = manifest
+	}
+	// The signer was hardcoded before it was part of the manifest
+	if manifest["signer"] == nil {
+		manifest["signer"] = map[string]interface{}{"image": "ghcr.io/hyperledger/firefly-signer", "tag": "v0.9.6"}
+	}
+
+	// The chain ID was fixed before it could be customized
+	if _, ok := stack["chainID"]; !ok && stack["blockchainProvider"] == "ethereum" {
+		stack["chainID"] = 2021
+	}
+	return nil
+}
+
+func migrateStackStateV0(state map[string]interface{}) error {
+	for _, key := range []string{"deployedContracts", "accounts"} {
+		if state[key] == nil {
+			state[key] = []interface{}{}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationsCoverSchemaVersion(t *testing.T) {
+	assert.Len(t, stackMigrations, StackSchemaVersion)
+	assert.Len(t, stackStateMigrations, StackSchemaVersion)
+}
+
+func TestMigrateStackV0(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "stack.json")
+	original := []byte(`{"name":"old","blockchainProvider":"geth","members":[{"id":"0"}]}`)
+	assert.NoError(t, ioutil.WriteFile(filename, original, 0755))
+
+	l := &log.StdoutLogger{LogLevel: log.Error}
+	b, err := migrateFile(l, filename, stackMigrations, false)
+	assert.NoError(t, err)
+
+	var stack *types.Stack
+	assert.NoError(t, json.Unmarshal(b, &stack))
+	assert.Equal(t, StackSchemaVersion, stack.SchemaVersion)
+	assert.Equal(t, "ethereum", stack.BlockchainProvider.String())
+	assert.Equal(t, "geth", stack.BlockchainNodeProvider.String())
+	assert.Equal(t, "ethconnect", stack.BlockchainConnector.String())
+	assert.Equal(t, "ghcr.io/hyperledger/firefly-signer", stack.VersionManifest.Signer.Image)
+	assert.Equal(t, int64(2021), stack.ChainID())
+
+	backup, err := ioutil.ReadFile(filename + ".v0.bak")
+	assert.NoError(t, err)
+	assert.Equal(t, original, backup)
+
+	// A second load leaves the migrated file alone
+	b2, err := migrateFile(l, filename, stackMigrations, false)
+	assert.NoError(t, err)
+	assert.Equal(t, b, b2)
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "stack.json")
+	original := []byte(`{"name":"old","blockchainProvider":"geth","members":[{"id":"0"}]}`)
+	assert.NoError(t, ioutil.WriteFile(filename, original, 0755))
+
+	b, err := migrateFile(&log.StdoutLogger{LogLevel: log.Error}, filename, stackMigrations, true)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"blockchainNodeProvider": "geth"`)
+
+	onDisk, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, original, onDisk)
+	_, err = os.Stat(filename + ".v0.bak")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestMigrateNewerSchemaVersion(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "stack.json")
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(`{"schemaVersion":999}`), 0755))
+	_, err := migrateFile(&log.StdoutLogger{LogLevel: log.Error}, filename, stackMigrations, false)
+	assert.Regexp(t, "please upgrade the CLI", err)
+}
@@ -0,0 +1,216 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+)
+
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type PostmanCollection struct {
+	Info     PostmanInfo        `json:"info"`
+	Auth     *PostmanAuth       `json:"auth,omitempty"`
+	Variable []*PostmanVariable `json:"variable"`
+	Item     []*PostmanItem     `json:"item"`
+}
+
+type PostmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+type PostmanAuth struct {
+	Type  string             `json:"type"`
+	Basic []*PostmanVariable `json:"basic,omitempty"`
+}
+
+type PostmanVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Type  string `json:"type,omitempty"`
+}
+
+// PostmanItem is either a folder of items, or a single request
+type PostmanItem struct {
+	Name    string          `json:"name"`
+	Item    []*PostmanItem  `json:"item,omitempty"`
+	Request *PostmanRequest `json:"request,omitempty"`
+	Auth    *PostmanAuth    `json:"auth,omitempty"`
+}
+
+type PostmanRequest struct {
+	Method string             `json:"method"`
+	Header []*PostmanVariable `json:"header"`
+	Body   *PostmanBody       `json:"body,omitempty"`
+	URL    *PostmanURL        `json:"url"`
+}
+
+type PostmanBody struct {
+	Mode    string                 `json:"mode"`
+	Raw     string                 `json:"raw"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+type PostmanURL struct {
+	Raw   string             `json:"raw"`
+	Host  []string           `json:"host"`
+	Path  []string           `json:"path"`
+	Query []*PostmanVariable `json:"query,omitempty"`
+}
+
+func postmanBasicAuth(password string) *PostmanAuth {
+	return &PostmanAuth{
+		Type: "basic",
+		Basic: []*PostmanVariable{
+			{Key: "username", Value: constants.APIAuthUsername, Type: "string"},
+			{Key: "password", Value: password, Type: "string"},
+		},
+	}
+}
+
+// PostmanCollection returns a Postman collection with a folder of requests for each member of the
+// stack, covering the status, messages, tokens and contracts APIs. The base URL of each member and
+// the namespace are collection variables, so they can be changed in one place.
+func (s *StackManager) PostmanCollection() *PostmanCollection {
+	collection := &PostmanCollection{
+		Info: PostmanInfo{
+			Name:        fmt.Sprintf("FireFly stack %s", s.Stack.Name),
+			Description: fmt.Sprintf("Requests for the members of the FireFly stack '%s'. Generated by ff export postman.", s.Stack.Name),
+			Schema:      postmanSchema,
+		},
+		Variable: []*PostmanVariable{{Key: "namespace", Value: "default"}},
+		Item:     []*PostmanItem{},
+	}
+	if s.Stack.APIAuthToken != "" {
+		collection.Auth = postmanBasicAuth(s.Stack.APIAuthToken)
+	}
+
+	for _, member := range s.Stack.Members {
+		urlVar := fmt.Sprintf("member%s_url", member.ID)
+		collection.Variable = append(collection.Variable, &PostmanVariable{
+			Key:   urlVar,
+			Value: fmt.Sprintf("http://127.0.0.1:%d", member.ExposedFireflyPort),
+		})
+		ns := "/api/v1/namespaces/{{namespace}}"
+		folder := func(name string, requests ...*PostmanItem) *PostmanItem {
+			return &PostmanItem{Name: name, Item: requests}
+		}
+		req := func(name, method, path, body string) *PostmanItem {
+			return postmanRequest(urlVar, name, method, path, body)
+		}
+
+		items := []*PostmanItem{
+			folder("Status",
+				req("Get status", "GET", ns+"/status", ""),
+				req("List organizations", "GET", ns+"/network/organizations", ""),
+			),
+		}
+		messages := []*PostmanItem{
+			req("List messages", "GET", ns+"/messages?limit=25", ""),
+			req("List events", "GET", ns+"/events?limit=25", ""),
+		}
+		if s.Stack.MultipartyEnabled {
+			messages = append(messages, req("Send broadcast", "POST", ns+"/messages/broadcast", `{"data":[{"value":"hello from `+member.OrgName+`"}]}`))
+			if len(s.Stack.Members) > 1 {
+				messages = append(messages, req("Send private message", "POST", ns+"/messages/private", s.privateMessageBody(member.OrgName)))
+			}
+		}
+		items = append(items, folder("Messages", messages...))
+		if len(s.Stack.TokenProviders) > 0 {
+			items = append(items, folder("Tokens",
+				req("List token pools", "GET", ns+"/tokens/pools", ""),
+				req("Create token pool", "POST", ns+"/tokens/pools", `{"name":"testpool","type":"fungible"}`),
+				req("Mint tokens", "POST", ns+"/tokens/mint", `{"pool":"testpool","amount":"10"}`),
+				req("List token balances", "GET", ns+"/tokens/balances", ""),
+				req("List token transfers", "GET", ns+"/tokens/transfers?limit=25", ""),
+			))
+		}
+		items = append(items, folder("Contracts",
+			req("List contract interfaces", "GET", ns+"/contracts/interfaces", ""),
+			req("List contract APIs", "GET", ns+"/apis", ""),
+			req("List contract listeners", "GET", ns+"/contracts/listeners", ""),
+		))
+		memberFolder := folder(fmt.Sprintf("Member %s (%s)", member.ID, member.OrgName), items...)
+		if member.APIToken != "" {
+			memberFolder.Auth = postmanBasicAuth(member.APIToken)
+		}
+		collection.Item = append(collection.Item, memberFolder)
+	}
+	return collection
+}
+
+// privateMessageBody addresses a private message from one org to every other org of the stack
+func (s *StackManager) privateMessageBody(from string) string {
+	members := []string{}
+	for _, member := range s.Stack.Members {
+		if member.OrgName != from {
+			members = append(members, fmt.Sprintf(`{"identity":"%s"}`, member.OrgName))
+		}
+	}
+	return fmt.Sprintf(`{"data":[{"value":"hello from %s"}],"group":{"members":[%s]}}`, from, strings.Join(members, ","))
+}
+
+func postmanRequest(urlVar, name, method, path, body string) *PostmanItem {
+	pathOnly := path
+	var query []*PostmanVariable
+	if i := strings.Index(path, "?"); i >= 0 {
+		pathOnly = path[:i]
+		for _, param := range strings.Split(path[i+1:], "&") {
+			kv := strings.SplitN(param, "=", 2)
+			v := &PostmanVariable{Key: kv[0]}
+			if len(kv) == 2 {
+				v.Value = kv[1]
+			}
+			query = append(query, v)
+		}
+	}
+	r := &PostmanRequest{
+		Method: method,
+		Header: []*PostmanVariable{},
+		URL: &PostmanURL{
+			Raw:   fmt.Sprintf("{{%s}}%s", urlVar, path),
+			Host:  []string{fmt.Sprintf("{{%s}}", urlVar)},
+			Path:  strings.Split(strings.TrimPrefix(pathOnly, "/"), "/"),
+			Query: query,
+		},
+	}
+	if body != "" {
+		r.Header = append(r.Header, &PostmanVariable{Key: "Content-Type", Value: "application/json"})
+		r.Body = &PostmanBody{
+			Mode:    "raw",
+			Raw:     body,
+			Options: map[string]interface{}{"raw": map[string]string{"language": "json"}},
+		}
+	}
+	return &PostmanItem{Name: name, Request: r}
+}
+
+// WritePostmanCollection writes the Postman collection of the stack to a file
+func (s *StackManager) WritePostmanCollection(filename string) error {
+	b, err := json.MarshalIndent(s.PostmanCollection(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, b, 0644)
+}
@@ -0,0 +1,74 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostmanCollection(t *testing.T) {
+	s := &StackManager{Stack: &types.Stack{
+		Name:              "dev",
+		MultipartyEnabled: true,
+		APIAuthToken:      "secret",
+		Members: []*types.Organization{
+			{ID: "0", OrgName: "org_0", ExposedFireflyPort: 5000},
+			{ID: "1", OrgName: "org_1", ExposedFireflyPort: 5001},
+		},
+	}}
+	c := s.PostmanCollection()
+	assert.Equal(t, postmanSchema, c.Info.Schema)
+	assert.Equal(t, "basic", c.Auth.Type)
+	assert.Equal(t, "secret", c.Auth.Basic[1].Value)
+	assert.Equal(t, &PostmanVariable{Key: "member1_url", Value: "http://127.0.0.1:5001"}, c.Variable[2])
+	assert.Len(t, c.Item, 2)
+
+	member := c.Item[0]
+	assert.Equal(t, "Member 0 (org_0)", member.Name)
+	folders := []string{}
+	for _, f := range member.Item {
+		folders = append(folders, f.Name)
+	}
+	// No token providers, so no tokens folder
+	assert.Equal(t, []string{"Status", "Messages", "Contracts"}, folders)
+
+	messages := member.Item[1].Item
+	assert.Equal(t, "List messages", messages[0].Name)
+	assert.Equal(t, "{{member0_url}}/api/v1/namespaces/{{namespace}}/messages?limit=25", messages[0].Request.URL.Raw)
+	assert.Equal(t, []string{"api", "v1", "namespaces", "{{namespace}}", "messages"}, messages[0].Request.URL.Path)
+	assert.Equal(t, []*PostmanVariable{{Key: "limit", Value: "25"}}, messages[0].Request.URL.Query)
+	private := messages[3]
+	assert.Equal(t, "POST", private.Request.Method)
+	assert.Equal(t, `{"data":[{"value":"hello from org_0"}],"group":{"members":[{"identity":"org_1"}]}}`, private.Request.Body.Raw)
+}
+
+func TestPostmanCollectionMemberTokens(t *testing.T) {
+	s := &StackManager{Stack: &types.Stack{
+		Name: "dev",
+		Members: []*types.Organization{
+			{ID: "0", OrgName: "org_0", ExposedFireflyPort: 5000, APIToken: "token0"},
+			{ID: "1", OrgName: "org_1", ExposedFireflyPort: 5001, APIToken: "token1"},
+		},
+	}}
+	c := s.PostmanCollection()
+	assert.Nil(t, c.Auth)
+	assert.Equal(t, "token0", c.Item[0].Auth.Basic[1].Value)
+	assert.Equal(t, "token1", c.Item[1].Auth.Basic[1].Value)
+}
@@ -0,0 +1,163 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+)
+
+type ServiceProfile struct {
+	Container         string  `json:"container"`
+	Samples           int     `json:"samples"`
+	AvgCPUPercent     float64 `json:"avgCpuPercent"`
+	MaxCPUPercent     float64 `json:"maxCpuPercent"`
+	AvgMemoryBytes    float64 `json:"avgMemoryBytes"`
+	MaxMemoryBytes    float64 `json:"maxMemoryBytes"`
+	NetRxBytes        float64 `json:"netRxBytes"`
+	NetTxBytes        float64 `json:"netTxBytes"`
+	BlockReadBytes    float64 `json:"blockReadBytes"`
+	BlockWrittenBytes float64 `json:"blockWrittenBytes"`
+}
+
+type ProfileReport struct {
+	Stack     string            `json:"stack"`
+	StartTime time.Time         `json:"startTime"`
+	EndTime   time.Time         `json:"endTime"`
+	Samples   int               `json:"samples"`
+	Services  []*ServiceProfile `json:"services"`
+}
+
+// ProfileStack samples the resource usage of every running container in the stack until the duration has
+// elapsed, and returns a report with the heaviest services first. Network and disk figures are the amount
+// transferred during the sampling window.
+func (s *StackManager) ProfileStack(duration, interval time.Duration) (*ProfileReport, error) {
+	containers, err := docker.ListRunningContainers(s.ctx, fmt.Sprintf("%s_", s.Stack.ResourcePrefix()))
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no running containers found for stack '%s'", s.Stack.Name)
+	}
+
+	report := &ProfileReport{
+		Stack:     s.Stack.Name,
+		StartTime: time.Now(),
+	}
+	first := make(map[string]*docker.ContainerStats)
+	last := make(map[string]*docker.ContainerStats)
+	profiles := make(map[string]*ServiceProfile)
+	deadline := report.StartTime.Add(duration)
+	for {
+		sampleStart := time.Now()
+		stats, err := docker.GetContainerStats(s.ctx, containers)
+		if err != nil {
+			return nil, err
+		}
+		report.Samples++
+		for _, stat := range stats {
+			p, ok := profiles[stat.Name]
+			if !ok {
+				p = &ServiceProfile{Container: stat.Name}
+				profiles[stat.Name] = p
+				first[stat.Name] = stat
+			}
+			last[stat.Name] = stat
+			p.Samples++
+			p.AvgCPUPercent += stat.CPUPercent
+			p.AvgMemoryBytes += stat.MemoryBytes
+			if stat.CPUPercent > p.MaxCPUPercent {
+				p.MaxCPUPercent = stat.CPUPercent
+			}
+			if stat.MemoryBytes > p.MaxMemoryBytes {
+				p.MaxMemoryBytes = stat.MemoryBytes
+			}
+		}
+		s.Log.Info(fmt.Sprintf("collected sample %d of resource usage for %d containers", report.Samples, len(stats)))
+		if time.Now().Add(interval).After(deadline) {
+			break
+		}
+		time.Sleep(interval - time.Since(sampleStart))
+	}
+	report.EndTime = time.Now()
+
+	for name, p := range profiles {
+		p.AvgCPUPercent /= float64(p.Samples)
+		p.AvgMemoryBytes /= float64(p.Samples)
+		p.NetRxBytes = last[name].NetRxBytes - first[name].NetRxBytes
+		p.NetTxBytes = last[name].NetTxBytes - first[name].NetTxBytes
+		p.BlockReadBytes = last[name].BlockRead - first[name].BlockRead
+		p.BlockWrittenBytes = last[name].BlockWritten - first[name].BlockWritten
+		report.Services = append(report.Services, p)
+	}
+	sort.Slice(report.Services, func(i, j int) bool {
+		if report.Services[i].AvgCPUPercent == report.Services[j].AvgCPUPercent {
+			return report.Services[i].AvgMemoryBytes > report.Services[j].AvgMemoryBytes
+		}
+		return report.Services[i].AvgCPUPercent > report.Services[j].AvgCPUPercent
+	})
+	return report, nil
+}
+
+// WriteProfileReport saves the report as JSON in the profiles directory of the stack, and returns the file path
+func (s *StackManager) WriteProfileReport(report *ProfileReport) (string, error) {
+	profilesDir := filepath.Join(s.Stack.StackDir, "profiles")
+	if err := os.MkdirAll(profilesDir, 0755); err != nil {
+		return "", err
+	}
+	b, err := json.MarshalIndent(report, "", " ")
+	if err != nil {
+		return "", err
+	}
+	filename := filepath.Join(profilesDir, fmt.Sprintf("profile_%s.json", report.StartTime.Format("20060102_150405")))
+	return filename, ioutil.WriteFile(filename, b, 0755)
+}
+
+func PrintProfileReport(report *ProfileReport) {
+	fmt.Printf("Resource usage for stack '%s' over %s (%d samples):\n\n", report.Stack, report.EndTime.Sub(report.StartTime).Round(time.Second), report.Samples)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CONTAINER\tAVG CPU\tMAX CPU\tAVG MEM\tMAX MEM\tNET RX / TX\tBLOCK READ / WRITE")
+	for _, p := range report.Services {
+		fmt.Fprintf(w, "%s\t%.2f%%\t%.2f%%\t%s\t%s\t%s / %s\t%s / %s\n",
+			p.Container,
+			p.AvgCPUPercent, p.MaxCPUPercent,
+			formatBytes(p.AvgMemoryBytes), formatBytes(p.MaxMemoryBytes),
+			formatBytes(p.NetRxBytes), formatBytes(p.NetTxBytes),
+			formatBytes(p.BlockReadBytes), formatBytes(p.BlockWrittenBytes),
+		)
+	}
+	w.Flush()
+	fmt.Print("\n")
+}
+
+func formatBytes(b float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	i := 0
+	for b >= 1024 && i < len(units)-1 {
+		b /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.1f%s", b, units[i])
+}
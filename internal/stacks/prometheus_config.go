@@ -1,6 +1,10 @@
 package stacks
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+)
 
 type GlobalConfig struct {
 	ScrapeInterval string `yaml:"scrape_interval,omitempty"`
@@ -41,8 +45,8 @@ func (s *StackManager) GeneratePrometheusConfig() *PrometheusConfig {
 		},
 	}
 
-	for i, member := range s.Stack.Members {
-		config.ScrapeConfigs[0].StaticConfigs[0].Targets = append(config.ScrapeConfigs[0].StaticConfigs[0].Targets, fmt.Sprintf("firefly_core_%d:%d", i, member.ExposedFireflyMetricsPort))
+	for _, member := range s.Stack.Members {
+		config.ScrapeConfigs[0].StaticConfigs[0].Targets = append(config.ScrapeConfigs[0].StaticConfigs[0].Targets, fmt.Sprintf("%s:%d", docker.CoreHost(member), member.ExposedFireflyMetricsPort))
 	}
 
 	return config
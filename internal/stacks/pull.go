@@ -0,0 +1,83 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/errcodes"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+var pullRetryDelay = 2 * time.Second
+
+const maxPullRetryDelay = 30 * time.Second
+
+// pullBackoff returns how long to wait before retrying a pull that has failed the given number of times
+func pullBackoff(failures int) time.Duration {
+	delay := pullRetryDelay
+	for i := 1; i < failures && delay < maxPullRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxPullRetryDelay {
+		delay = maxPullRetryDelay
+	}
+	return delay
+}
+
+// isPinned returns whether an image reference is a digest, which always refers to the same image
+func isPinned(image string) bool {
+	return strings.Contains(image, "@sha256:")
+}
+
+// pullImages pulls each image, retrying with backoff. Docker keeps the layers that finished
+// downloading, so each retry only fetches what is missing. Images pinned by digest that are already
+// present are skipped, so that a failed pull can be re-run cheaply. A failed image does not stop
+// the others from being pulled, and all failures are reported together at the end.
+func (s *StackManager) pullImages(images []string, options *types.PullOptions) error {
+	var failed []string
+	for _, image := range images {
+		if isPinned(image) && docker.ImageExists(s.ctx, image) {
+			s.Log.Info(fmt.Sprintf("'%s' is already present", image))
+			continue
+		}
+		if err := s.pullImage(image, options.Retries); err != nil {
+			s.Log.Warn(fmt.Sprintf("failed to pull '%s': %s", image, err))
+			failed = append(failed, image)
+		}
+	}
+	if len(failed) > 0 {
+		return errcodes.New(errcodes.ImagePullFailed, "failed to pull %d of %d images: %s. Run 'ff pull %s' to retry", len(failed), len(images), strings.Join(failed, ", "), s.Stack.Name)
+	}
+	return nil
+}
+
+func (s *StackManager) pullImage(image string, retries int) error {
+	for attempt := 0; ; attempt++ {
+		s.Log.Info(fmt.Sprintf("pulling '%s'", image))
+		err := docker.RunDockerCommand(s.ctx, s.Stack.InitDir, "pull", image)
+		if err == nil || attempt >= retries {
+			return err
+		}
+		delay := pullBackoff(attempt + 1)
+		s.Log.Info(fmt.Sprintf("pull of '%s' failed, retrying in %s (%d of %d)", image, delay, attempt+1, retries))
+		time.Sleep(delay)
+	}
+}
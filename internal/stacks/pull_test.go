@@ -0,0 +1,38 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullBackoff(t *testing.T) {
+	assert.Equal(t, 2*time.Second, pullBackoff(1))
+	assert.Equal(t, 4*time.Second, pullBackoff(2))
+	assert.Equal(t, 16*time.Second, pullBackoff(4))
+	assert.Equal(t, 30*time.Second, pullBackoff(5))
+	assert.Equal(t, 30*time.Second, pullBackoff(20))
+}
+
+func TestIsPinned(t *testing.T) {
+	assert.True(t, isPinned("ghcr.io/hyperledger/firefly@sha256:0123abcd"))
+	assert.False(t, isPinned("ghcr.io/hyperledger/firefly:v1.2.0"))
+	assert.False(t, isPinned("postgres"))
+}
@@ -0,0 +1,430 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/core"
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/errcodes"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+// The path inside each postgres container that the database is dumped to before a refresh
+const refreshDumpPath = "/tmp/firefly_refresh.sql"
+
+// The path of the SQLite database inside a FireFly core container, which is not on a volume, so it
+// does not survive the container being recreated with a new image
+const coreSQLitePath = "/etc/firefly/db"
+
+// Refresh updates a stack that has been started to the latest release of the channel it was created from,
+// and to the latest images for the tags in its manifest. It pulls them and recreates the services that
+// changed. FireFly core migrates its database when it starts. If the
+// stack then fails its smoke test, the previous images and the FireFly core databases are restored. A
+// stack that is already up to date is left alone, so it is safe to run on a schedule.
+func (s *StackManager) Refresh(options *types.RefreshOptions) (changes []string, err error) {
+	if s.Stack.VersionManifest == nil {
+		return nil, fmt.Errorf("the stack has no version manifest")
+	}
+	if hasBeenRun, err := s.Stack.HasRunBefore(); err != nil {
+		return nil, err
+	} else if !hasBeenRun {
+		return nil, fmt.Errorf("stack '%s' has not been started yet", s.Stack.Name)
+	}
+	unlock, err := s.lockRefresh()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	previousManifest := &types.VersionManifest{}
+	b, err := json.Marshal(s.Stack.VersionManifest)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, previousManifest); err != nil {
+		return nil, err
+	}
+	previousImages := s.localImageIDs()
+
+	if s.Stack.ReleaseChannel != "" {
+		s.Log.Info(fmt.Sprintf("checking for a new release on the %s channel", s.Stack.ReleaseChannel))
+		latest, err := core.GetManifestForReleaseChannel(s.Stack.ReleaseChannel)
+		if err != nil {
+			return nil, errcodes.Wrap(errcodes.ManifestUnavailable, err)
+		}
+		changes = updateManifest(s.Stack.VersionManifest, latest)
+	}
+	s.Log.Info("checking for new images")
+	digestChanges, err := core.ResolveDigests(s.Stack.VersionManifest, docker.GetImageDigest)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, digestChanges...)
+	if err := s.writeStackJSON(); err != nil {
+		return nil, err
+	}
+	if err := s.writeDockerCompose(s.buildDockerCompose()); err != nil {
+		return nil, err
+	}
+	s.Log.Info("pulling images")
+	if err := s.runDockerComposeCommand("pull", "--ignore-pull-failures"); err != nil {
+		return nil, err
+	}
+	retagged := changedImages(previousImages, s.localImageIDs())
+	for _, image := range retagged {
+		changes = append(changes, fmt.Sprintf("%s (new image)", image))
+	}
+	if len(changes) == 0 && !options.Force {
+		return nil, nil
+	}
+
+	if err := s.dumpDatabases(); err != nil {
+		return changes, err
+	}
+	err = s.recreateAndTest()
+	if err == nil {
+		return changes, nil
+	}
+	if options.NoRollback {
+		return changes, fmt.Errorf("the stack did not pass its smoke test after the refresh, and was left running the new images: %s", err)
+	}
+
+	s.Log.Info(fmt.Sprintf("rolling back after the stack failed its smoke test: %s", err))
+	if rollbackErr := s.rollbackRefresh(previousManifest, previousImages, retagged); rollbackErr != nil {
+		return changes, fmt.Errorf("the stack did not pass its smoke test after the refresh (%s), and rolling it back failed: %s", err, rollbackErr)
+	}
+	return changes, errcodes.New(errcodes.RefreshRolledBack, "the stack did not pass its smoke test after the refresh, so its previous images were restored: %s", err)
+}
+
+// updateManifest moves the entries of a manifest to the ones in the latest manifest of its release
+// channel, and returns a description of each entry that changed. Local images are left alone.
+func updateManifest(manifest, latest *types.VersionManifest) []string {
+	changes := []string{}
+	current := manifestFields(manifest)
+	for i, entry := range manifestFields(latest) {
+		previous := *current[i]
+		if *entry == nil || (previous != nil && previous.Local) {
+			continue
+		}
+		if previous == nil || previous.GetDockerImageString() != (*entry).GetDockerImageString() {
+			changes = append(changes, fmt.Sprintf("%s %s -> %s", (*entry).Image, describeManifestEntry(previous), describeManifestEntry(*entry)))
+			*current[i] = *entry
+		}
+	}
+	return changes
+}
+
+// manifestFields returns the address of each entry of a manifest, so it can be replaced
+func manifestFields(m *types.VersionManifest) []**types.ManifestEntry {
+	return []**types.ManifestEntry{
+		&m.FireFly,
+		&m.Ethconnect,
+		&m.Evmconnect,
+		&m.Fabconnect,
+		&m.DataExchange,
+		&m.TokensERC1155,
+		&m.TokensERC20ERC721,
+		&m.Signer,
+	}
+}
+
+func describeManifestEntry(entry *types.ManifestEntry) string {
+	switch {
+	case entry == nil:
+		return "(none)"
+	case entry.Tag != "" && len(entry.SHA) > 12:
+		return fmt.Sprintf("%s (%s)", entry.Tag, entry.SHA[:12])
+	case entry.Tag != "":
+		return entry.Tag
+	case len(entry.SHA) > 12:
+		return entry.SHA[:12]
+	default:
+		return entry.SHA
+	}
+}
+
+// lockRefresh stops two scheduled refreshes of the same stack from overlapping
+func (s *StackManager) lockRefresh() (func(), error) {
+	lockFile := filepath.Join(s.Stack.StackDir, "refresh.lock")
+	f, err := os.OpenFile(lockFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if os.IsExist(err) {
+		return nil, fmt.Errorf("another refresh of stack '%s' is running. if it is not, delete %s", s.Stack.Name, lockFile)
+	} else if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(f, "%d\n", os.Getpid())
+	f.Close()
+	return func() { os.Remove(lockFile) }, nil
+}
+
+// localImageIDs returns the ID of the image on this machine for each tag used by the stack. Images
+// referenced by digest are left out, as they are restored from the manifest instead.
+func (s *StackManager) localImageIDs() map[string]string {
+	ids := make(map[string]string)
+	for _, service := range s.buildDockerCompose().Services {
+		if service.Image == "" || strings.Contains(service.Image, "@") {
+			continue
+		}
+		if id, err := docker.GetImageID(s.ctx, service.Image); err == nil {
+			ids[service.Image] = id
+		}
+	}
+	return ids
+}
+
+// changedImages returns the tags that were moved to a different image
+func changedImages(before, after map[string]string) []string {
+	changed := []string{}
+	for image, id := range before {
+		if after[image] != "" && after[image] != id {
+			changed = append(changed, image)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func (s *StackManager) recreateAndTest() error {
+	s.Log.Info("recreating the services with new images")
+	if err := s.recreateServices(); err != nil {
+		return err
+	}
+	if err := s.waitForHealthyServices(false); err != nil {
+		return err
+	}
+	if err := s.ensureFireflyNodesUp(false); err != nil {
+		return err
+	}
+	return s.SmokeTest()
+}
+
+// SmokeTest checks that the FireFly API of each member responds, that its org and node are still
+// registered in multiparty mode, and that its WebSocket accepts a subscription
+func (s *StackManager) SmokeTest() error {
+	s.Log.Info("running the smoke test")
+	for _, member := range s.Stack.Members {
+		var status struct {
+			Org struct {
+				Registered bool `json:"registered"`
+			} `json:"org"`
+			Node struct {
+				Registered bool `json:"registered"`
+			} `json:"node"`
+		}
+		url := fmt.Sprintf("http://127.0.0.1:%d/api/v1/status", member.ExposedFireflyPort)
+		if err := core.RequestWithRetry(s.ctx, http.MethodGet, url, nil, &status); err != nil {
+			return fmt.Errorf("the FireFly API of member %s did not respond: %s", member.ID, err)
+		}
+		if s.Stack.MultipartyEnabled && (!status.Org.Registered || !status.Node.Registered) {
+			return fmt.Errorf("the org and node of member %s are not registered", member.ID)
+		}
+	}
+	return s.ensureWebSocketsUp()
+}
+
+// recreateServices brings the stack up on the images in its compose file. A SQLite database is lost
+// when its FireFly core container is recreated, so the copy taken by dumpDatabases is put into the
+// new container before it starts.
+func (s *StackManager) recreateServices() error {
+	if !s.Stack.Database.Equals(types.DatabaseSelectionSQLite) {
+		return s.runDockerComposeCommand("up", "-d")
+	}
+	if err := s.runDockerComposeCommand("up", "--no-start"); err != nil {
+		return err
+	}
+	if err := s.restoreSQLiteDatabases(); err != nil {
+		return err
+	}
+	return s.runDockerComposeCommand("up", "-d")
+}
+
+// forceRecreate recreates some services of a running stack, without touching their dependencies. When
+// they include a FireFly core on a SQLite stack, the databases are copied out of the cores first, and
+// into the new containers before they start, so they are not lost with the old containers.
+func (s *StackManager) forceRecreate(services ...string) error {
+	recreateArgs := append([]string{"--no-deps", "--force-recreate"}, services...)
+	if !s.Stack.Database.Equals(types.DatabaseSelectionSQLite) || !includesCoreService(services) {
+		return s.runDockerComposeCommand(append([]string{"up", "-d"}, recreateArgs...)...)
+	}
+	if err := s.backupSQLiteDatabases(); err != nil {
+		return err
+	}
+	if err := s.runDockerComposeCommand(append([]string{"up", "--no-start"}, recreateArgs...)...); err != nil {
+		return err
+	}
+	if err := s.restoreSQLiteDatabases(); err != nil {
+		return err
+	}
+	// Every core was stopped for the backup, not only the recreated ones
+	start := append([]string{"up", "-d", "--no-deps"}, services...)
+	for _, service := range s.coreServices() {
+		if !includesService(services, service) {
+			start = append(start, service)
+		}
+	}
+	return s.runDockerComposeCommand(start...)
+}
+
+func includesCoreService(services []string) bool {
+	for _, service := range services {
+		if strings.HasPrefix(service, "firefly_core_") {
+			return true
+		}
+	}
+	return false
+}
+
+func includesService(services []string, service string) bool {
+	for _, s := range services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+// restoreSQLiteDatabases copies the backed up SQLite database of each member into its FireFly core
+// container, which must not be running
+func (s *StackManager) restoreSQLiteDatabases() error {
+	for _, member := range s.Stack.Members {
+		if member.External {
+			continue
+		}
+		container := fmt.Sprintf("%s_firefly_core_%s", s.Stack.ResourcePrefix(), member.ID)
+		s.Log.Info(fmt.Sprintf("restoring the database in %s", container))
+		if err := docker.RunDockerCommand(s.ctx, ".", "cp", s.sqliteBackupPath(member), container+":"+coreSQLitePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sqliteBackupPath is where the SQLite database of a member is kept while its container is recreated
+func (s *StackManager) sqliteBackupPath(member *types.Organization) string {
+	return filepath.Join(s.Stack.StackDir, "refresh", fmt.Sprintf("firefly_core_%s.db", member.ID))
+}
+
+// backupSQLiteDatabases copies the SQLite database out of each FireFly core container. The cores are
+// stopped first, so the copy is consistent. They are recreated straight after anyway.
+func (s *StackManager) backupSQLiteDatabases() error {
+	coreServices := s.coreServices()
+	if len(coreServices) == 0 {
+		return nil
+	}
+	if err := s.runDockerComposeCommand(append([]string{"stop"}, coreServices...)...); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(s.Stack.StackDir, "refresh"), 0755); err != nil {
+		return err
+	}
+	for _, member := range s.Stack.Members {
+		if member.External {
+			continue
+		}
+		container := fmt.Sprintf("%s_firefly_core_%s", s.Stack.ResourcePrefix(), member.ID)
+		s.Log.Info(fmt.Sprintf("copying the database out of %s", container))
+		if err := docker.RunDockerCommand(s.ctx, ".", "cp", container+":"+coreSQLitePath, s.sqliteBackupPath(member)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *StackManager) postgresContainers() []string {
+	if !s.Stack.Database.Equals(types.DatabaseSelectionPostgres) {
+		return nil
+	}
+	containers := make([]string, 0, len(s.Stack.Members))
+	for _, member := range s.Stack.Members {
+		containers = append(containers, fmt.Sprintf("%s_postgres_%s", s.Stack.ResourcePrefix(), member.ID))
+	}
+	return containers
+}
+
+// dumpDatabases dumps the FireFly core database of each member inside its postgres container, or copies
+// its SQLite database out of the core container, so a rollback can undo the migrations a newer FireFly
+// core applies when it starts
+func (s *StackManager) dumpDatabases() error {
+	if s.Stack.Database.Equals(types.DatabaseSelectionSQLite) {
+		return s.backupSQLiteDatabases()
+	}
+	containers := s.postgresContainers()
+	if len(containers) == 0 {
+		return nil
+	}
+	// The stack may be stopped when the refresh runs on a schedule
+	services := make([]string, 0, len(s.Stack.Members))
+	for _, member := range s.Stack.Members {
+		services = append(services, fmt.Sprintf("postgres_%s", member.ID))
+	}
+	if err := s.runDockerComposeCommand(append([]string{"up", "-d", "--no-deps"}, services...)...); err != nil {
+		return err
+	}
+	if err := docker.WaitForHealthy(s.ctx, containers, healthyTimeout); err != nil {
+		return err
+	}
+	for _, container := range containers {
+		s.Log.Info(fmt.Sprintf("dumping the database in %s", container))
+		if err := docker.RunDockerCommand(s.ctx, ".", "exec", container, "pg_dump", "-U", "postgres", "--clean", "--if-exists", "-f", refreshDumpPath, "postgres"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *StackManager) rollbackRefresh(previousManifest *types.VersionManifest, previousImages map[string]string, retagged []string) error {
+	s.Stack.VersionManifest = previousManifest
+	if err := s.writeStackJSON(); err != nil {
+		return err
+	}
+	if err := s.writeDockerCompose(s.buildDockerCompose()); err != nil {
+		return err
+	}
+	for _, image := range retagged {
+		if err := docker.RunDockerCommand(s.ctx, ".", "tag", previousImages[image], image); err != nil {
+			return err
+		}
+	}
+	// FireFly core has to be stopped while its database is restored
+	if coreServices := s.coreServices(); len(coreServices) > 0 {
+		if err := s.runDockerComposeCommand(append([]string{"stop"}, coreServices...)...); err != nil {
+			return err
+		}
+	}
+	for _, container := range s.postgresContainers() {
+		s.Log.Info(fmt.Sprintf("restoring the database in %s", container))
+		if err := docker.RunDockerCommand(s.ctx, ".", "exec", container, "psql", "-U", "postgres", "-q", "-f", refreshDumpPath, "postgres"); err != nil {
+			return err
+		}
+	}
+	// The SQLite databases are put back as the FireFly core containers are recreated on the old images
+	if err := s.recreateServices(); err != nil {
+		return err
+	}
+	if err := s.waitForHealthyServices(false); err != nil {
+		return err
+	}
+	return s.ensureFireflyNodesUp(false)
+}
@@ -0,0 +1,57 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"errors"
+
+	"github.com/hyperledger/firefly-cli/internal/core"
+	"github.com/hyperledger/firefly-cli/internal/docker"
+)
+
+// RefreshImages resolves each image tag in the stack's manifest to the digest it points at now,
+// and regenerates the docker compose file to use the new digests. This is how a stack on the
+// "head" release channel picks up newer builds. It returns a description of each image that changed.
+func (s *StackManager) RefreshImages() ([]string, error) {
+	if s.Stack.VersionManifest == nil {
+		return nil, errors.New("the stack has no version manifest")
+	}
+	changes, err := core.ResolveDigests(s.Stack.VersionManifest, docker.GetImageDigest)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return changes, nil
+	}
+	if err := s.writeStackJSON(); err != nil {
+		return nil, err
+	}
+	if err := s.writeDockerCompose(s.buildDockerCompose()); err != nil {
+		return nil, err
+	}
+	hasBeenRun, err := s.Stack.HasRunBefore()
+	if err != nil {
+		return nil, err
+	}
+	if hasBeenRun {
+		s.Log.Info("pulling the refreshed images")
+		if err := s.runDockerComposeCommand("pull", "--ignore-pull-failures"); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
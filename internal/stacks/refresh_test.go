@@ -0,0 +1,70 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangedImages(t *testing.T) {
+	before := map[string]string{"postgres": "sha256:aaa", "ipfs/go-ipfs:v0.10.0": "sha256:bbb", "removed": "sha256:ccc"}
+	after := map[string]string{"postgres": "sha256:ddd", "ipfs/go-ipfs:v0.10.0": "sha256:bbb"}
+	assert.Equal(t, []string{"postgres"}, changedImages(before, after))
+}
+
+func TestLockRefresh(t *testing.T) {
+	s := newSettingsTestManager(t)
+	s.Stack.StackDir = t.TempDir()
+	unlock, err := s.lockRefresh()
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(s.Stack.StackDir, "refresh.lock"))
+
+	_, err = s.lockRefresh()
+	assert.Regexp(t, "another refresh of stack 'settingstest' is running", err)
+
+	unlock()
+	_, err = os.Stat(filepath.Join(s.Stack.StackDir, "refresh.lock"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestUpdateManifest(t *testing.T) {
+	manifest := &types.VersionManifest{
+		FireFly:      &types.ManifestEntry{Image: "ghcr.io/hyperledger/firefly", Tag: "v1.2.0", SHA: "aaaaaaaaaaaaaaaa"},
+		Ethconnect:   &types.ManifestEntry{Image: "ghcr.io/hyperledger/firefly-ethconnect", Tag: "v3.2.0", SHA: "bbbbbbbbbbbbbbbb"},
+		DataExchange: &types.ManifestEntry{Image: "dx", Local: true},
+	}
+	latest := &types.VersionManifest{
+		FireFly:      &types.ManifestEntry{Image: "ghcr.io/hyperledger/firefly", Tag: "v1.3.0", SHA: "cccccccccccccccc"},
+		Ethconnect:   &types.ManifestEntry{Image: "ghcr.io/hyperledger/firefly-ethconnect", Tag: "v3.2.0", SHA: "bbbbbbbbbbbbbbbb"},
+		Evmconnect:   &types.ManifestEntry{Image: "ghcr.io/hyperledger/firefly-evmconnect", Tag: "v1.3.0"},
+		DataExchange: &types.ManifestEntry{Image: "ghcr.io/hyperledger/firefly-dataexchange-https", Tag: "v1.3.0"},
+	}
+	changes := updateManifest(manifest, latest)
+	assert.Equal(t, []string{
+		"ghcr.io/hyperledger/firefly v1.2.0 (aaaaaaaaaaaa) -> v1.3.0 (cccccccccccc)",
+		"ghcr.io/hyperledger/firefly-evmconnect (none) -> v1.3.0",
+	}, changes)
+	assert.Equal(t, "v1.3.0", manifest.FireFly.Tag)
+	assert.Equal(t, "v1.3.0", manifest.Evmconnect.Tag)
+	// A local image is kept
+	assert.Equal(t, "dx", manifest.DataExchange.Image)
+}
@@ -0,0 +1,197 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+)
+
+// Files bigger than this are left out of the report, as they are data rather than config
+const maxReportFileSize = 1 << 20
+
+// Directories in the stack that hold keys or data rather than config
+var reportSkipDirs = map[string]bool{
+	"keystore":  true,
+	"profiles":  true,
+	"chaincode": true,
+}
+
+type reportWriter struct {
+	zip      *zip.Writer
+	problems []string
+}
+
+func (r *reportWriter) add(name string, content []byte) error {
+	w, err := r.zip.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
+
+// addDockerOutput runs a docker command and adds its output to the report. Failures are noted in the
+// report rather than returned, as the stack is often broken when a report is needed.
+func (r *reportWriter) addDockerOutput(s *StackManager, name string, redact func([]byte) ([]byte, error), args ...string) error {
+	out, err := docker.RunDockerCommandBuffered(s.ctx, "", args...)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("docker %s: %s", strings.Join(args, " "), err))
+		return nil
+	}
+	content := []byte(out)
+	if redact != nil {
+		if content, err = redact(content); err != nil {
+			r.problems = append(r.problems, fmt.Sprintf("%s: %s", name, err))
+			return nil
+		}
+	}
+	return r.add(name, content)
+}
+
+func redactTextBytes(b []byte) ([]byte, error) {
+	return []byte(redactText(string(b))), nil
+}
+
+// CreateReport writes a zip archive for attaching to bug reports. It holds the config files of the stack,
+// the docker inspect output and recent logs of each container, the versions in use and details of the
+// host. Passwords, tokens and keys are redacted.
+func (s *StackManager) CreateReport(filename string, options *types.ReportOptions) (err error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		f.Close()
+		if err != nil {
+			os.Remove(filename)
+		}
+	}()
+	r := &reportWriter{zip: zip.NewWriter(f)}
+
+	if err := s.addStackFilesToReport(r); err != nil {
+		return err
+	}
+
+	versions := map[string]interface{}{
+		"cli":      options.CLIVersion,
+		"manifest": s.Stack.VersionManifest,
+	}
+	b, err := json.MarshalIndent(versions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := r.add("versions.json", b); err != nil {
+		return err
+	}
+	if err := r.addDockerOutput(s, "docker/version.txt", nil, "version"); err != nil {
+		return err
+	}
+	if err := r.addDockerOutput(s, "docker/info.txt", redactTextBytes, "info"); err != nil {
+		return err
+	}
+
+	host := map[string]interface{}{
+		"os":        runtime.GOOS,
+		"arch":      runtime.GOARCH,
+		"cpus":      runtime.NumCPU(),
+		"goVersion": runtime.Version(),
+		"time":      time.Now().Format(time.RFC3339),
+	}
+	b, err = json.MarshalIndent(host, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := r.add("host.json", b); err != nil {
+		return err
+	}
+
+	prefix := fmt.Sprintf("%s_", s.Stack.ResourcePrefix())
+	containers, err := docker.ListContainers(s.ctx, prefix)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("listing containers: %s", err))
+	}
+	for _, container := range containers {
+		service := strings.TrimPrefix(container, prefix)
+		if err := r.addDockerOutput(s, fmt.Sprintf("containers/%s/inspect.json", service), redactJSON, "inspect", container); err != nil {
+			return err
+		}
+		if err := r.addDockerOutput(s, fmt.Sprintf("containers/%s/logs.txt", service), redactTextBytes, "logs", "--timestamps", "--tail", fmt.Sprint(options.LogLines), container); err != nil {
+			return err
+		}
+	}
+
+	if len(r.problems) > 0 {
+		if err := r.add("problems.txt", []byte(strings.Join(r.problems, "\n")+"\n")); err != nil {
+			return err
+		}
+	}
+	return r.zip.Close()
+}
+
+// addStackFilesToReport adds the JSON and YAML files from the stack directory, with their secrets redacted
+func (s *StackManager) addStackFilesToReport(r *reportWriter) error {
+	return filepath.Walk(s.Stack.StackDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if reportSkipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Size() > maxReportFileSize {
+			return nil
+		}
+		var redact func([]byte) ([]byte, error)
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			redact = redactJSON
+		case ".yml", ".yaml":
+			redact = redactYAML
+		default:
+			return nil
+		}
+		rel, err := filepath.Rel(s.Stack.StackDir, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(filepath.Join("stack", rel))
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		content, err := redact(b)
+		if err != nil {
+			// Leave out files that can't be parsed, as their secrets can't be found reliably
+			r.problems = append(r.problems, fmt.Sprintf("%s: left out as it could not be parsed: %s", name, err))
+			return nil
+		}
+		return r.add(name, content)
+	})
+}
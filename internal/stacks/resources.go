@@ -0,0 +1,115 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+)
+
+// resourceHeadroomMB is left over for the OS of the docker VM and for spikes during startup
+const resourceHeadroomMB = 1024
+
+type serviceEstimate struct {
+	match    string
+	memoryMB int
+	milliCPU int
+}
+
+// serviceEstimates are rough needs of each kind of service in an idle dev stack. The first entry
+// whose match is part of the service name is used.
+var serviceEstimates = []serviceEstimate{
+	{match: "firefly_core", memoryMB: 256, milliCPU: 250},
+	{match: "besu", memoryMB: 1024, milliCPU: 500},
+	{match: "geth", memoryMB: 512, milliCPU: 500},
+	{match: "quorum", memoryMB: 768, milliCPU: 500},
+	{match: "anvil", memoryMB: 256, milliCPU: 250},
+	{match: "ethsigner", memoryMB: 256, milliCPU: 100},
+	{match: "tessera", memoryMB: 512, milliCPU: 100},
+	{match: "peer", memoryMB: 512, milliCPU: 250},
+	{match: "orderer", memoryMB: 256, milliCPU: 100},
+	{match: "ipfs", memoryMB: 256, milliCPU: 100},
+	{match: "postgres", memoryMB: 128, milliCPU: 100},
+	{match: "prometheus", memoryMB: 256, milliCPU: 100},
+	{match: "tokens", memoryMB: 192, milliCPU: 100},
+	{match: "ca", memoryMB: 64, milliCPU: 50},
+}
+
+const defaultServiceMemoryMB, defaultServiceMilliCPU = 128, 100
+
+// EstimateResources returns the approximate memory in MB and CPU in thousandths of a core that the
+// services of a compose file need
+func EstimateResources(compose *docker.DockerComposeConfig) (memoryMB int, milliCPU int) {
+	for name := range compose.Services {
+		serviceMemory, serviceCPU := defaultServiceMemoryMB, defaultServiceMilliCPU
+		for _, estimate := range serviceEstimates {
+			if strings.Contains(name, estimate.match) {
+				serviceMemory, serviceCPU = estimate.memoryMB, estimate.milliCPU
+				break
+			}
+		}
+		memoryMB += serviceMemory
+		milliCPU += serviceCPU
+	}
+	return memoryMB, milliCPU
+}
+
+func resourceAdvisories(stackName string, resources *docker.EngineResources, memoryMB, milliCPU int) []string {
+	neededGB := int(math.Ceil(float64(memoryMB+resourceHeadroomMB) / 1024))
+	neededCPUs := int(math.Ceil(float64(milliCPU) / 1000))
+	if neededCPUs < 2 {
+		neededCPUs = 2
+	}
+	lowMemory := resources.MemoryBytes > 0 && resources.MemoryBytes < int64(neededGB)*1024*1024*1024
+	lowCPU := resources.CPUs > 0 && resources.CPUs < neededCPUs
+	if !lowMemory && !lowCPU {
+		return nil
+	}
+
+	var advisories []string
+	if lowMemory {
+		advisories = append(advisories, fmt.Sprintf("docker has %.1f GB of memory, but stack '%s' needs about %d GB. Containers may be killed when they run out of memory", float64(resources.MemoryBytes)/(1024*1024*1024), stackName, neededGB))
+	}
+	if lowCPU {
+		advisories = append(advisories, fmt.Sprintf("docker has %d CPUs, but stack '%s' needs about %d. Services may be slow to start and fail their healthchecks", resources.CPUs, stackName, neededCPUs))
+	}
+	switch {
+	case resources.IsWSL2():
+		advisories = append(advisories, fmt.Sprintf("to give WSL2 more resources, add the following to %%UserProfile%%\\.wslconfig and run 'wsl --shutdown':\n[wsl2]\nmemory=%dGB\nprocessors=%d", neededGB, neededCPUs))
+	case resources.IsDockerDesktop():
+		advisories = append(advisories, fmt.Sprintf("to give Docker Desktop more resources, set Memory to at least %d GB and CPUs to at least %d under Settings > Resources > Advanced", neededGB, neededCPUs))
+	default:
+		advisories = append(advisories, "stop other containers, or create the stack with fewer members")
+	}
+	return advisories
+}
+
+// CheckResources compares the memory and CPUs available to docker with the estimated needs of the
+// stack, and returns advisories when they are too low. Failing to query docker is not an error,
+// as the check is only advice.
+func (s *StackManager) CheckResources() []string {
+	resources, err := docker.GetEngineResources(s.ctx)
+	if err != nil || resources == nil {
+		s.Log.Debug(fmt.Sprintf("unable to check docker resources: %v", err))
+		return nil
+	}
+	memoryMB, milliCPU := EstimateResources(s.buildDockerCompose())
+	return resourceAdvisories(s.Stack.Name, resources, memoryMB, milliCPU)
+}
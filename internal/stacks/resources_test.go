@@ -0,0 +1,59 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateResources(t *testing.T) {
+	compose := &docker.DockerComposeConfig{
+		Services: map[string]*docker.Service{
+			"firefly_core_0": {},
+			"geth":           {},
+			"dataexchange_0": {},
+		},
+	}
+	memoryMB, milliCPU := EstimateResources(compose)
+	assert.Equal(t, 256+512+128, memoryMB)
+	assert.Equal(t, 250+500+100, milliCPU)
+}
+
+func TestResourceAdvisoriesEnough(t *testing.T) {
+	resources := &docker.EngineResources{MemoryBytes: 8 << 30, CPUs: 4}
+	assert.Empty(t, resourceAdvisories("dev", resources, 2048, 1500))
+}
+
+func TestResourceAdvisoriesWSL2(t *testing.T) {
+	resources := &docker.EngineResources{MemoryBytes: 2 << 30, CPUs: 1, KernelVersion: "5.15.90.1-microsoft-standard-WSL2", OperatingSystem: "Docker Desktop"}
+	advisories := resourceAdvisories("dev", resources, 3000, 2500)
+	assert.Len(t, advisories, 3)
+	assert.Contains(t, advisories[0], "needs about 4 GB")
+	assert.Contains(t, advisories[1], "needs about 3")
+	assert.True(t, strings.HasSuffix(advisories[2], "[wsl2]\nmemory=4GB\nprocessors=3"))
+}
+
+func TestResourceAdvisoriesDockerDesktop(t *testing.T) {
+	resources := &docker.EngineResources{MemoryBytes: 2 << 30, CPUs: 8, OperatingSystem: "Docker Desktop", KernelVersion: "6.4.16-linuxkit"}
+	advisories := resourceAdvisories("dev", resources, 3000, 1000)
+	assert.Len(t, advisories, 2)
+	assert.Contains(t, advisories[1], "Settings > Resources > Advanced")
+}
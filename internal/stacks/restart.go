@@ -0,0 +1,72 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RestartServices restarts the named services, leaving the rest of the stack running. Unless noDeps is set,
+// any services that depend on them are restarted too, so for example restarting a member's connector also
+// restarts that member's FireFly core. If no services are named, the whole stack is restarted.
+func (s *StackManager) RestartServices(serviceNames []string, noDeps bool) ([]string, error) {
+	if len(serviceNames) == 0 {
+		return nil, s.runDockerComposeCommand("restart")
+	}
+
+	compose := s.buildDockerCompose()
+	toRestart := make(map[string]bool)
+	for _, name := range serviceNames {
+		services, err := s.resolveServices(compose, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, service := range services {
+			toRestart[service] = true
+		}
+	}
+
+	if !noDeps {
+		// Keep adding dependents until there are no new ones
+		for added := true; added; {
+			added = false
+			for serviceName, service := range compose.Services {
+				if toRestart[serviceName] {
+					continue
+				}
+				for dependency := range service.DependsOn {
+					if toRestart[dependency] {
+						s.Log.Info(fmt.Sprintf("%s depends on %s so it will also be restarted", serviceName, dependency))
+						toRestart[serviceName] = true
+						added = true
+						break
+					}
+				}
+			}
+		}
+	}
+
+	services := make([]string, 0, len(toRestart))
+	for service := range toRestart {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	s.Log.Info(fmt.Sprintf("restarting %s", strings.Join(services, ", ")))
+	return services, s.runDockerComposeCommand(append([]string{"restart"}, services...)...)
+}
@@ -0,0 +1,50 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"github.com/hyperledger/firefly-cli/internal/docker"
+)
+
+// RestartPolicyUnlessStopped makes docker start the containers of a stack again when the daemon
+// restarts, unless the stack was stopped first
+const RestartPolicyUnlessStopped = "unless-stopped"
+
+// SetRestartPolicy sets the docker restart policy of every container of the stack. An empty policy
+// removes it. Existing containers are updated in place, and the compose file is regenerated for
+// containers created later.
+func (s *StackManager) SetRestartPolicy(policy string) error {
+	s.Stack.RestartPolicy = policy
+	if err := s.writeStackJSON(); err != nil {
+		return err
+	}
+	if err := s.writeDockerCompose(s.buildDockerCompose()); err != nil {
+		return err
+	}
+	containers, err := docker.ListContainers(s.ctx, s.Stack.ResourcePrefix()+"_")
+	if err != nil {
+		return err
+	}
+	if len(containers) == 0 {
+		return nil
+	}
+	if policy == "" {
+		policy = "no"
+	}
+	args := append([]string{"update", "--restart", policy}, containers...)
+	return docker.RunDockerCommand(s.ctx, s.Stack.StackDir, args...)
+}
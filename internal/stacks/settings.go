@@ -0,0 +1,387 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultPrometheusPort = 9090
+
+// stackSetting is an option of a stack that can be changed after it has been created. set updates
+// the stack and any config files the option is written to, and returns the services that have to
+// be recreated or restarted to pick up the change. The stack.json and docker compose file are written
+// afterwards.
+type stackSetting struct {
+	description string
+	get         func(s *types.Stack) string
+	set         func(s *StackManager, value string) ([]string, error)
+}
+
+var stackSettings = map[string]*stackSetting{
+	"prometheus.enabled": {
+		description: "run a Prometheus server that scrapes the metrics of each FireFly core",
+		get:         func(s *types.Stack) string { return strconv.FormatBool(s.PrometheusEnabled) },
+		set:         setPrometheusEnabled,
+	},
+	"prometheus.port": {
+		description: "the port the Prometheus server is published on",
+		get:         func(s *types.Stack) string { return strconv.Itoa(s.ExposedPrometheusPort) },
+		set:         setPrometheusPort,
+	},
+	"sandbox.enabled": {
+		description: "run the FireFly sandbox for each member",
+		get:         func(s *types.Stack) string { return strconv.FormatBool(s.SandboxEnabled) },
+		set:         setSandboxEnabled,
+	},
+	"blockPeriod": {
+		description: "the seconds between blocks of an anvil blockchain. 0 mines a block for each transaction",
+		get:         func(s *types.Stack) string { return strconv.Itoa(s.BlockPeriod) },
+		set:         setBlockPeriod,
+	},
+	"requestTimeout": {
+		description: "the timeout in seconds of the requests the CLI makes to FireFly. 0 uses the default",
+		get:         func(s *types.Stack) string { return strconv.Itoa(s.RequestTimeout) },
+		set:         setRequestTimeout,
+	},
+}
+
+// SettingKeys returns the keys of the options that can be changed with SetSetting
+func SettingKeys() []string {
+	keys := make([]string, 0, len(stackSettings))
+	for key := range stackSettings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SettingDescription returns the description of a setting
+func SettingDescription(key string) string {
+	if setting, ok := stackSettings[key]; ok {
+		return setting.description
+	}
+	return ""
+}
+
+func lookupSetting(key string) (*stackSetting, error) {
+	setting, ok := stackSettings[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown setting '%s'. valid settings are: %s", key, strings.Join(SettingKeys(), ", "))
+	}
+	return setting, nil
+}
+
+// GetSetting returns the current value of a setting of the stack
+func (s *StackManager) GetSetting(key string) (string, error) {
+	setting, err := lookupSetting(key)
+	if err != nil {
+		return "", err
+	}
+	return setting.get(s.Stack), nil
+}
+
+// SettingChange lists the services a setting change affects. A service whose docker compose definition
+// changed, or that was added or removed, is recreated. The others only read config files that are
+// bind-mounted into them, so restarting them is enough.
+type SettingChange struct {
+	Recreate []string
+	Restart  []string
+}
+
+// Services returns every service affected by the change
+func (c *SettingChange) Services() []string {
+	services := append(append([]string{}, c.Recreate...), c.Restart...)
+	sort.Strings(services)
+	return services
+}
+
+// SetSetting changes a setting of the stack, and regenerates the files it affects. It returns the
+// services that have to be recreated or restarted for the change to take effect, which is done by
+// ApplySettings.
+func (s *StackManager) SetSetting(key, value string) (*SettingChange, error) {
+	setting, err := lookupSetting(key)
+	if err != nil {
+		return nil, err
+	}
+	before := s.buildDockerCompose()
+	services, err := setting.set(s, value)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.writeStackJSON(); err != nil {
+		return nil, err
+	}
+	after := s.buildDockerCompose()
+	if err := s.writeDockerCompose(after); err != nil {
+		return nil, err
+	}
+	return settingChange(services, before, after), nil
+}
+
+func settingChange(services []string, before, after *docker.DockerComposeConfig) *SettingChange {
+	change := &SettingChange{}
+	for _, service := range services {
+		if reflect.DeepEqual(before.Services[service], after.Services[service]) {
+			change.Restart = append(change.Restart, service)
+		} else {
+			change.Recreate = append(change.Recreate, service)
+		}
+	}
+	sort.Strings(change.Recreate)
+	sort.Strings(change.Restart)
+	return change
+}
+
+// ApplySettings updates the affected services of a running stack. Services that are no longer in the
+// docker compose file have their containers removed. A FireFly core that is recreated keeps its
+// SQLite database.
+func (s *StackManager) ApplySettings(change *SettingChange) error {
+	compose := s.buildDockerCompose()
+	var recreate []string
+	for _, service := range change.Recreate {
+		if _, ok := compose.Services[service]; ok {
+			recreate = append(recreate, service)
+			continue
+		}
+		s.Log.Info(fmt.Sprintf("removing %s", service))
+		containerName := fmt.Sprintf("%s_%s", s.Stack.ResourcePrefix(), service)
+		if err := docker.RunDockerCommand(s.ctx, s.Stack.StackDir, "rm", "-f", containerName); err != nil {
+			return err
+		}
+	}
+	if len(recreate) > 0 {
+		s.Log.Info(fmt.Sprintf("recreating %s", strings.Join(recreate, ", ")))
+		if err := s.forceRecreate(recreate...); err != nil {
+			return err
+		}
+	}
+	if len(change.Restart) > 0 {
+		s.Log.Info(fmt.Sprintf("restarting %s", strings.Join(change.Restart, ", ")))
+		if err := s.runDockerComposeCommand(append([]string{"restart"}, change.Restart...)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseSettingBool(value string) (bool, error) {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("'%s' is not true or false", value)
+	}
+	return b, nil
+}
+
+func parseSettingInt(value string, min int) (int, error) {
+	i, err := strconv.Atoi(value)
+	if err != nil || i < min {
+		return 0, fmt.Errorf("'%s' is not a whole number of at least %d", value, min)
+	}
+	return i, nil
+}
+
+// nextMemberPort returns the port after the highest one of the member's services. Each member has
+// a block of 100 ports, starting from the one below its admin port.
+func nextMemberPort(member *types.Organization) int {
+	base := member.ExposedFireflyAdminSPIPort - 1
+	ports := append([]int{
+		member.ExposedFireflyAdminSPIPort,
+		member.ExposedConnectorPort,
+		member.ExposedUIPort,
+		member.ExposedDatabasePort,
+		member.ExposedDataexchangePort,
+		member.ExposedIPFSApiPort,
+		member.ExposedIPFSGWPort,
+		member.ExposedFireflyMetricsPort,
+		member.ExposedSandboxPort,
+	}, member.ExposedTokensPorts...)
+	next := base + 1
+	for _, port := range ports {
+		if port >= next && port < base+100 {
+			next = port + 1
+		}
+	}
+	return next
+}
+
+func (s *StackManager) coreServices() []string {
+	var services []string
+	for _, member := range s.Stack.Members {
+		if !member.External {
+			services = append(services, fmt.Sprintf("firefly_core_%s", member.ID))
+		}
+	}
+	return services
+}
+
+// configDirs returns the config directories of the stack that exist. The init directory is copied
+// to the runtime directory the first time the stack starts, so after that both are kept up to date.
+func (s *StackManager) configDirs() []string {
+	var dirs []string
+	for _, dir := range []string{s.Stack.InitDir, s.Stack.RuntimeDir} {
+		if _, err := os.Stat(filepath.Join(dir, "config")); err == nil {
+			dirs = append(dirs, filepath.Join(dir, "config"))
+		}
+	}
+	return dirs
+}
+
+// updateCoreConfigs applies a change to the FireFly core config file of every member
+func (s *StackManager) updateCoreConfigs(update func(config map[string]interface{}, member *types.Organization)) error {
+	for _, dir := range s.configDirs() {
+		for _, member := range s.Stack.Members {
+			configFile := filepath.Join(dir, fmt.Sprintf("firefly_core_%s.yml", member.ID))
+			b, err := ioutil.ReadFile(configFile)
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return err
+			}
+			var config map[string]interface{}
+			if err := yaml.Unmarshal(b, &config); err != nil {
+				return err
+			}
+			update(config, member)
+			if b, err = yaml.Marshal(config); err != nil {
+				return err
+			}
+			if err := ioutil.WriteFile(configFile, b, 0755); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func metricsConfig(member *types.Organization, enabled bool) map[string]interface{} {
+	if !enabled {
+		return map[string]interface{}{"enabled": false}
+	}
+	return map[string]interface{}{
+		"enabled":   true,
+		"address":   "0.0.0.0",
+		"port":      member.ExposedFireflyMetricsPort,
+		"publicURL": fmt.Sprintf("http://127.0.0.1:%d", member.ExposedFireflyMetricsPort),
+		"path":      "/metrics",
+	}
+}
+
+func (s *StackManager) writePrometheusConfig() error {
+	configBytes, err := yaml.Marshal(s.GeneratePrometheusConfig())
+	if err != nil {
+		return err
+	}
+	for _, dir := range s.configDirs() {
+		if err := ioutil.WriteFile(filepath.Join(dir, "prometheus.yml"), configBytes, 0755); err != nil {
+			return err
+		}
+	}
+	hasBeenRun, err := s.Stack.HasRunBefore()
+	if err != nil || !hasBeenRun {
+		return err
+	}
+	volumeName := fmt.Sprintf("%s_prometheus_config", s.Stack.ResourcePrefix())
+	return docker.CopyFileToVolume(s.ctx, volumeName, filepath.Join(s.Stack.RuntimeDir, "config", "prometheus.yml"), "/prometheus.yml")
+}
+
+func setPrometheusEnabled(s *StackManager, value string) ([]string, error) {
+	enabled, err := parseSettingBool(value)
+	if err != nil || enabled == s.Stack.PrometheusEnabled {
+		return nil, err
+	}
+	s.Stack.PrometheusEnabled = enabled
+	if enabled {
+		if s.Stack.ExposedPrometheusPort == 0 {
+			s.Stack.ExposedPrometheusPort = defaultPrometheusPort
+		}
+		for _, member := range s.Stack.Members {
+			if member.ExposedFireflyMetricsPort == 0 {
+				member.ExposedFireflyMetricsPort = nextMemberPort(member)
+			}
+		}
+		if err := s.writePrometheusConfig(); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.updateCoreConfigs(func(config map[string]interface{}, member *types.Organization) {
+		config["metrics"] = metricsConfig(member, enabled)
+	}); err != nil {
+		return nil, err
+	}
+	return append(s.coreServices(), "prometheus"), nil
+}
+
+func setPrometheusPort(s *StackManager, value string) ([]string, error) {
+	port, err := parseSettingInt(value, 1)
+	if err != nil || port == s.Stack.ExposedPrometheusPort {
+		return nil, err
+	}
+	s.Stack.ExposedPrometheusPort = port
+	if !s.Stack.PrometheusEnabled {
+		return nil, nil
+	}
+	return []string{"prometheus"}, nil
+}
+
+func setSandboxEnabled(s *StackManager, value string) ([]string, error) {
+	enabled, err := parseSettingBool(value)
+	if err != nil || enabled == s.Stack.SandboxEnabled {
+		return nil, err
+	}
+	s.Stack.SandboxEnabled = enabled
+	var services []string
+	for _, member := range s.Stack.Members {
+		if enabled && member.ExposedSandboxPort == 0 {
+			member.ExposedSandboxPort = nextMemberPort(member)
+		}
+		services = append(services, fmt.Sprintf("sandbox_%s", member.ID))
+	}
+	return services, nil
+}
+
+func setBlockPeriod(s *StackManager, value string) ([]string, error) {
+	period, err := parseSettingInt(value, 0)
+	if err != nil || period == s.Stack.BlockPeriod {
+		return nil, err
+	}
+	if !s.Stack.BlockchainNodeProvider.Equals(types.BlockchainNodeProviderAnvil) {
+		return nil, fmt.Errorf("the block period of a %s blockchain is part of its genesis block, so it can only be set when the stack is created", s.Stack.BlockchainNodeProvider)
+	}
+	s.Stack.BlockPeriod = period
+	return []string{"anvil"}, nil
+}
+
+func setRequestTimeout(s *StackManager, value string) ([]string, error) {
+	timeout, err := parseSettingInt(value, 0)
+	if err != nil {
+		return nil, err
+	}
+	s.Stack.RequestTimeout = timeout
+	return nil, nil
+}
@@ -0,0 +1,125 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func newSettingsTestManager(t *testing.T) *StackManager {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "init", "config"), 0755))
+	stack := &types.Stack{
+		Name:                   "settingstest",
+		InitDir:                filepath.Join(dir, "init"),
+		RuntimeDir:             filepath.Join(dir, "runtime"),
+		BlockchainNodeProvider: types.BlockchainNodeProviderGeth,
+		Members: []*types.Organization{
+			{ID: "0", ExposedFireflyAdminSPIPort: 5101, ExposedConnectorPort: 5102, ExposedUIPort: 5103, ExposedDatabasePort: 5104, ExposedTokensPorts: []int{5108}},
+		},
+	}
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "init", "config", "firefly_core_0.yml"), []byte("metrics:\n  enabled: false\n"), 0755))
+	return &StackManager{ctx: context.Background(), Log: &log.StdoutLogger{}, Stack: stack}
+}
+
+func TestNextMemberPort(t *testing.T) {
+	member := &types.Organization{ExposedFireflyAdminSPIPort: 5201, ExposedConnectorPort: 5202, ExposedDataexchangePort: 10405, ExposedTokensPorts: []int{5209}}
+	assert.Equal(t, 5210, nextMemberPort(member))
+}
+
+func TestSetPrometheusEnabled(t *testing.T) {
+	s := newSettingsTestManager(t)
+	services, err := setPrometheusEnabled(s, "true")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"firefly_core_0", "prometheus"}, services)
+	assert.Equal(t, 9090, s.Stack.ExposedPrometheusPort)
+	assert.Equal(t, 5109, s.Stack.Members[0].ExposedFireflyMetricsPort)
+
+	b, err := ioutil.ReadFile(filepath.Join(s.Stack.InitDir, "config", "firefly_core_0.yml"))
+	assert.NoError(t, err)
+	var config map[string]interface{}
+	assert.NoError(t, yaml.Unmarshal(b, &config))
+	metrics := config["metrics"].(map[string]interface{})
+	assert.Equal(t, true, metrics["enabled"])
+	assert.Equal(t, 5109, metrics["port"])
+	assert.FileExists(t, filepath.Join(s.Stack.InitDir, "config", "prometheus.yml"))
+
+	services, err = setPrometheusEnabled(s, "true")
+	assert.NoError(t, err)
+	assert.Empty(t, services)
+
+	_, err = setPrometheusEnabled(s, "maybe")
+	assert.Regexp(t, "not true or false", err)
+}
+
+func TestSetSandboxEnabled(t *testing.T) {
+	s := newSettingsTestManager(t)
+	services, err := setSandboxEnabled(s, "true")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sandbox_0"}, services)
+	assert.Equal(t, 5109, s.Stack.Members[0].ExposedSandboxPort)
+}
+
+func TestSetBlockPeriod(t *testing.T) {
+	s := newSettingsTestManager(t)
+	_, err := setBlockPeriod(s, "2")
+	assert.Regexp(t, "part of its genesis block", err)
+
+	s.Stack.BlockchainNodeProvider = types.BlockchainNodeProviderAnvil
+	services, err := setBlockPeriod(s, "2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"anvil"}, services)
+	assert.Equal(t, 2, s.Stack.BlockPeriod)
+
+	_, err = setBlockPeriod(s, "-1")
+	assert.Regexp(t, "at least 0", err)
+}
+
+func TestGetSettingUnknown(t *testing.T) {
+	s := newSettingsTestManager(t)
+	_, err := s.GetSetting("nope")
+	assert.Regexp(t, "unknown setting 'nope'", err)
+	value, err := s.GetSetting("sandbox.enabled")
+	assert.NoError(t, err)
+	assert.Equal(t, "false", value)
+}
+
+func TestSettingChange(t *testing.T) {
+	before := &docker.DockerComposeConfig{Services: map[string]*docker.Service{
+		"firefly_core_0": {Image: "firefly", Ports: []string{"5000:5000"}},
+		"sandbox_0":      {Image: "sandbox", Ports: []string{"5109:3001"}},
+	}}
+	after := &docker.DockerComposeConfig{Services: map[string]*docker.Service{
+		"firefly_core_0": {Image: "firefly", Ports: []string{"5000:5000"}},
+		"sandbox_0":      {Image: "sandbox", Ports: []string{"5110:3001"}},
+		"prometheus":     {Image: "prometheus"},
+	}}
+	change := settingChange([]string{"sandbox_0", "firefly_core_0", "prometheus"}, before, after)
+	assert.Equal(t, []string{"prometheus", "sandbox_0"}, change.Recreate)
+	assert.Equal(t, []string{"firefly_core_0"}, change.Restart)
+	assert.Equal(t, []string{"firefly_core_0", "prometheus", "sandbox_0"}, change.Services())
+}
@@ -18,6 +18,7 @@ package stacks
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -26,19 +27,25 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/hyperledger/firefly-cli/internal/blockchain"
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/anvil"
 	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/besu"
 	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/geth"
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/quorum"
 	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum/remoterpc"
 	"github.com/hyperledger/firefly-cli/internal/blockchain/fabric"
 	"github.com/hyperledger/firefly-cli/internal/constants"
 	"github.com/hyperledger/firefly-cli/internal/core"
 	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/hyperledger/firefly-cli/internal/errcodes"
 	"github.com/hyperledger/firefly-cli/internal/log"
+	"github.com/hyperledger/firefly-cli/internal/random"
 	"github.com/hyperledger/firefly-cli/internal/tokens"
 	"github.com/hyperledger/firefly-cli/internal/tokens/erc1155"
 	"github.com/hyperledger/firefly-cli/internal/tokens/erc20erc721"
@@ -51,6 +58,9 @@ import (
 	"github.com/otiai10/copy"
 )
 
+// How long to wait for the services of a stack to become healthy after they are started
+const healthyTimeout = 5 * time.Minute
+
 type StackManager struct {
 	ctx                context.Context
 	Log                log.Logger
@@ -58,6 +68,7 @@ type StackManager struct {
 	blockchainProvider blockchain.IBlockchainProvider
 	tokenProviders     []tokens.ITokensProvider
 	IsOldFileStructure bool
+	timer              *phaseTimer
 }
 
 func ListStacks() ([]string, error) {
@@ -87,6 +98,12 @@ func NewStackManager(ctx context.Context) *StackManager {
 }
 
 func (s *StackManager) InitStack(stackName string, memberCount int, options *types.InitOptions) (err error) {
+	return s.initStack(constants.StacksDir, stackName, memberCount, options)
+}
+
+// initStack creates the files of a new stack in a directory named after it in stacksDir
+func (s *StackManager) initStack(stacksDir, stackName string, memberCount int, options *types.InitOptions) (err error) {
+	s.timer = &phaseTimer{start: time.Now()}
 	s.Stack = &types.Stack{
 		Name:                   stackName,
 		Members:                make([]*types.Organization, memberCount),
@@ -96,29 +113,101 @@ func (s *StackManager) InitStack(stackName string, memberCount int, options *typ
 		BlockchainNodeProvider: fftypes.FFEnum(options.BlockchainNodeProvider),
 		BlockchainConnector:    fftypes.FFEnum(options.BlockchainConnector),
 		ContractAddress:        options.ContractAddress,
-		StackDir:               filepath.Join(constants.StacksDir, stackName),
-		InitDir:                filepath.Join(constants.StacksDir, stackName, "init"),
-		RuntimeDir:             filepath.Join(constants.StacksDir, stackName, "runtime"),
+		StackDir:               filepath.Join(stacksDir, stackName),
+		InitDir:                filepath.Join(stacksDir, stackName, "init"),
+		RuntimeDir:             filepath.Join(stacksDir, stackName, "runtime"),
 		State: &types.StackState{
 			DeployedContracts: make([]*types.DeployedContract, 0),
 			Accounts:          make([]interface{}, memberCount),
 		},
-		SandboxEnabled:    options.SandboxEnabled,
-		MultipartyEnabled: options.MultipartyEnabled,
-		ChainIDPtr:        &options.ChainID,
-		RemoteNodeURL:     options.RemoteNodeURL,
-		RequestTimeout:    options.RequestTimeout,
-		IPFSMode:          fftypes.FFEnum(options.IPFSMode),
+		SandboxEnabled:      options.SandboxEnabled,
+		MultipartyEnabled:   options.MultipartyEnabled,
+		ChainIDPtr:          &options.ChainID,
+		RemoteNodeURL:       options.RemoteNodeURL,
+		RequestTimeout:      options.RequestTimeout,
+		IPFSMode:            fftypes.FFEnum(options.IPFSMode),
+		LatencyProfile:      options.LatencyProfile,
+		DisableIPFS:         options.DisableIPFS,
+		DisableDataExchange: options.DisableDataExchange,
+		PrivateTransactions: options.PrivateTransactions,
+		NamePrefix:          options.NamePrefix,
+		BindAddress:         options.BindAddress,
+		Timezone:            options.Timezone,
+	}
+	if options.Deterministic {
+		seed := options.Seed
+		s.Stack.Seed = &seed
+	}
+
+	// A stack that fails part way through would leave a directory behind that blocks its name, so
+	// remove it again, unless it was already there or is kept to debug the failure
+	if _, statErr := os.Stat(s.Stack.StackDir); os.IsNotExist(statErr) {
+		defer func() {
+			if err != nil {
+				s.cleanupFailedInit(options)
+			}
+		}()
+	}
+
+	if options.APIAuth {
+		s.Stack.APIAuthToken = GenerateAPIAuthToken(random.Reader(s.Stack, "api-auth-token"))
 	}
 
-	tokenProviders, err := types.FFEnumArray(s.ctx, options.TokenProviders)
+	importedKeys, err := s.applyGenesisOptions(options)
+	if err != nil {
+		return err
+	}
+
+	if options.FabricTopologyPath != "" {
+		topology, err := fabric.ReadTopology(options.FabricTopologyPath, memberCount)
+		if err != nil {
+			return err
+		}
+		s.Stack.FabricTopology = topology
+	}
+
+	if options.BlockPeriod > 0 {
+		s.Stack.BlockPeriod = options.BlockPeriod
+	}
+
+	if len(options.Labels) > 0 {
+		labels, err := docker.ParseLabels(options.Labels)
+		if err != nil {
+			return err
+		}
+		s.Stack.Labels = labels
+	}
+
+	if len(options.ClockSkew) > 0 {
+		skew, err := docker.ParseClockSkew(options.ClockSkew)
+		if err != nil {
+			return err
+		}
+		for id := range skew {
+			if index, _ := strconv.Atoi(id); index >= memberCount {
+				return fmt.Errorf("cannot skew the clock of member %s. the stack only has %d members", id, memberCount)
+			}
+		}
+		s.Stack.ClockSkew = skew
+	}
+
+	if options.RPCProxyEnabled {
+		proxy := options.RPCProxy
+		s.Stack.RPCProxy = &proxy
+	}
+
+	s.Stack.DNS = options.DNS
+	s.Stack.ExtraHosts = options.ExtraHosts
+
+	tokenProviders, tokenProviderNames, err := ParseTokenProviders(s.ctx, options.TokenProviders)
 	if err != nil {
 		return err
 	}
 	s.Stack.TokenProviders = tokenProviders
+	s.Stack.TokenProviderNames = tokenProviderNames
 
 	if s.Stack.IPFSMode.Equals(types.IPFSModePrivate) {
-		s.Stack.SwarmKey = GenerateSwarmKey()
+		s.Stack.SwarmKey = GenerateSwarmKey(random.Reader(s.Stack, "swarm-key"))
 	}
 
 	if options.PrometheusEnabled {
@@ -126,8 +215,17 @@ func (s *StackManager) InitStack(stackName string, memberCount int, options *typ
 		s.Stack.ExposedPrometheusPort = options.PrometheusPort
 	}
 
+	if options.MessageQueue != "" && options.MessageQueue != types.MessageQueueNone.String() {
+		s.Stack.MessageQueue = fftypes.FFEnum(options.MessageQueue)
+		s.Stack.ExposedMessageQueuePort = options.MessageQueuePort
+		if s.Stack.ExposedMessageQueuePort == 0 {
+			s.Stack.ExposedMessageQueuePort = docker.MessageQueuePort(s.Stack.MessageQueue)
+		}
+	}
+
 	var manifest *types.VersionManifest
 
+	endPhase := s.startPhase("fetch version manifest")
 	if options.ManifestPath != "" {
 		// If a path to a manifest file is set, read the existing file
 		manifest, err = core.ReadManifestFile(options.ManifestPath)
@@ -139,23 +237,32 @@ func (s *StackManager) InitStack(stackName string, memberCount int, options *typ
 		if options.FireFlyVersion == "" || strings.ToLower(options.FireFlyVersion) == "latest" {
 			manifest, err = core.GetManifestForReleaseChannel(fftypes.FFEnum(options.ReleaseChannel))
 			if err != nil {
-				return err
+				return errcodes.Wrap(errcodes.ManifestUnavailable, err)
 			}
+			// Recorded so that ff refresh follows the channel to its newer releases
+			s.Stack.ReleaseChannel = fftypes.FFEnum(options.ReleaseChannel)
 		} else {
 			manifest, err = core.GetReleaseManifest(options.FireFlyVersion)
 			if err != nil {
-				return err
+				return errcodes.Wrap(errcodes.ManifestUnavailable, err)
 			}
 		}
 	}
 
+	endPhase()
+
 	s.Stack.VersionManifest = manifest
 	s.blockchainProvider = s.getBlockchainProvider()
 	s.tokenProviders = s.getITokenProviders()
 
+	endPhase = s.startPhase("create members")
 	for i := 0; i < memberCount; i++ {
 		externalProcess := i < options.ExternalProcesses
-		member, err := s.createMember(fmt.Sprint(i), i, options, externalProcess)
+		var privateKey string
+		if i < len(importedKeys) {
+			privateKey = importedKeys[i]
+		}
+		member, err := s.createMember(fmt.Sprint(i), i, options, externalProcess, privateKey)
 		if err != nil {
 			return err
 		}
@@ -165,6 +272,10 @@ func (s *StackManager) InitStack(stackName string, memberCount int, options *typ
 		}
 	}
 
+	endPhase()
+
+	endPhase = s.startPhase("write config")
+	defer endPhase()
 	if err := s.ensureInitDirectories(); err != nil {
 		return err
 	}
@@ -176,10 +287,27 @@ func (s *StackManager) InitStack(stackName string, memberCount int, options *typ
 	if err := s.writeDockerComposeOverride(compose); err != nil {
 		return fmt.Errorf("failed to write docker-compose.override.yml: %s", err)
 	}
-	return s.writeConfig(options)
+	if err := s.writeConfig(options); err != nil {
+		return err
+	}
+	return s.writeAppEnv()
 }
 
 func (s *StackManager) runDockerComposeCommand(command ...string) error {
+	// A dry run only prints the commands, so leaves the compose files as they are
+	if !docker.DryRunFromContext(s.ctx) {
+		if err := s.prepareComposeFiles(); err != nil {
+			return err
+		}
+	}
+	// Every service is assigned to a profile, so enable them all. Subsets of the stack are started by name.
+	args := append([]string{"-p", s.Stack.ResourcePrefix()}, docker.AllProfilesArgs()...)
+	return docker.RunDockerComposeCommand(s.ctx, s.Stack.StackDir, append(args, command...)...)
+}
+
+// prepareComposeFiles moves the compose file of a stack with the old file structure into the stack
+// directory, and makes the compose files suit the version of docker compose that is installed
+func (s *StackManager) prepareComposeFiles() error {
 	baseCompose := filepath.Join(s.Stack.StackDir, "docker-compose.yml")
 	runtimeCompose := filepath.Join(s.Stack.RuntimeDir, "docker-compose.yml")
 	if _, err := os.Stat(baseCompose); os.IsNotExist(err) {
@@ -188,14 +316,26 @@ func (s *StackManager) runDockerComposeCommand(command ...string) error {
 			copy.Copy(runtimeCompose, baseCompose)
 		}
 	}
-	return docker.RunDockerComposeCommand(s.ctx, s.Stack.StackDir, command...)
+	// The stack may have been created with the other major version of docker compose
+	for _, filename := range []string{baseCompose, filepath.Join(s.Stack.StackDir, "docker-compose.override.yml")} {
+		if err := docker.FixComposeFileVersion(filename); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *StackManager) buildDockerCompose() *docker.DockerComposeConfig {
 	compose := docker.CreateDockerCompose(s.Stack)
 	extraServices := s.blockchainProvider.GetDockerServiceDefinitions()
+	for _, serviceDefinition := range extraServices {
+		serviceDefinition.Service.Profiles = []string{docker.ProfileBlockchain}
+	}
 	for i, tp := range s.tokenProviders {
-		extraServices = append(extraServices, tp.GetDockerServiceDefinitions(i)...)
+		for _, serviceDefinition := range tp.GetDockerServiceDefinitions(i) {
+			serviceDefinition.Service.Profiles = []string{docker.ProfileTokens}
+			extraServices = append(extraServices, serviceDefinition)
+		}
 	}
 
 	for _, serviceDefinition := range extraServices {
@@ -203,7 +343,7 @@ func (s *StackManager) buildDockerCompose() *docker.DockerComposeConfig {
 		compose.Services[serviceDefinition.ServiceName] = serviceDefinition.Service
 		// Add the volume name for each volume used by this service
 		for _, volumeName := range serviceDefinition.VolumeNames {
-			compose.Volumes[volumeName] = struct{}{}
+			compose.Volumes[volumeName] = &docker.Volume{}
 		}
 
 		// Add a dependency so each firefly core container won't start up until dependencies are up
@@ -217,9 +357,40 @@ func (s *StackManager) buildDockerCompose() *docker.DockerComposeConfig {
 			}
 		}
 	}
+
+	// Use any locally built images in place of the ones from the manifest
+	for serviceName, image := range s.Stack.LocalImages {
+		if service, ok := compose.Services[serviceName]; ok {
+			service.Image = image
+		}
+	}
+
+	docker.ApplyBindAddress(compose, s.Stack)
+	docker.ApplyClockSkew(compose, s.Stack)
+	docker.ApplyTimezone(compose, s.Stack)
+	docker.ApplyLabels(compose, s.Stack)
+	docker.ApplyNetworkOverrides(compose, s.Stack)
+	docker.ApplyRestartPolicy(compose, s.Stack)
 	return compose
 }
 
+// cleanupFailedInit removes the directory of a stack that failed to initialize. Init writes nothing
+// outside of it, as docker resources are only created when the stack first starts.
+func (s *StackManager) cleanupFailedInit(options *types.InitOptions) {
+	if _, err := os.Stat(s.Stack.StackDir); options.DryRun || err != nil {
+		return
+	}
+	if options.KeepFailed {
+		fmt.Fprintf(os.Stderr, "the partly initialized stack was kept in %s for debugging. delete the directory before using the name '%s' again\n", s.Stack.StackDir, s.Stack.Name)
+		return
+	}
+	if err := os.RemoveAll(s.Stack.StackDir); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to remove the partly initialized stack in %s: %s\n", s.Stack.StackDir, err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "removed the partly initialized stack in %s. use --keep-failed to keep it for debugging\n", s.Stack.StackDir)
+}
+
 func CheckExists(stackName string) (bool, error) {
 	_, err := os.Stat(filepath.Join(constants.StacksDir, stackName, "stack.json"))
 	if os.IsNotExist(err) {
@@ -238,9 +409,9 @@ func (s *StackManager) LoadStack(stackName string) error {
 		return err
 	}
 	if !exists {
-		return fmt.Errorf("stack '%s' does not exist", stackName)
+		return errcodes.New(errcodes.StackNotFound, "stack '%s' does not exist", stackName)
 	}
-	d, err := ioutil.ReadFile(filepath.Join(stackDir, "stack.json"))
+	d, err := migrateFile(s.Log, filepath.Join(stackDir, "stack.json"), stackMigrations, docker.DryRunFromContext(s.ctx))
 	if err != nil {
 		return err
 	}
@@ -256,6 +427,7 @@ func (s *StackManager) LoadStack(stackName string) error {
 	if s.Stack.RequestTimeout > 0 {
 		core.SetRequestTimeout(s.Stack.RequestTimeout)
 	}
+	s.setAPIAuth()
 
 	isOldFileStructure, err := s.Stack.IsOldFileStructure()
 	if err != nil {
@@ -277,45 +449,6 @@ func (s *StackManager) LoadStack(stackName string) error {
 		}
 	}
 
-	// For backwards compatibility, add a "default" VersionManifest
-	// in memory for stacks that were created with old CLI versions
-	if s.Stack.VersionManifest == nil {
-		s.Stack.VersionManifest = &types.VersionManifest{
-			FireFly: &types.ManifestEntry{
-				Image: "ghcr.io/hyperledger/firefly",
-				Tag:   "latest",
-			},
-			Ethconnect: &types.ManifestEntry{
-				Image: "ghcr.io/hyperledger/firefly-ethconnect",
-				Tag:   "latest",
-			},
-			Fabconnect: &types.ManifestEntry{
-				Image: "ghcr.io/hyperledger/firefly-fabconnect",
-				Tag:   "latest",
-			},
-			DataExchange: &types.ManifestEntry{
-				Image: "ghcr.io/hyperledger/firefly-dataexchange-https",
-				Tag:   "latest",
-			},
-			TokensERC1155: &types.ManifestEntry{
-				Image: "ghcr.io/hyperledger/firefly-tokens-erc1155",
-				Tag:   "latest",
-			},
-			TokensERC20ERC721: &types.ManifestEntry{
-				Image: "ghcr.io/hyperledger/firefly-tokens-erc20-erc721",
-				Tag:   "latest",
-			},
-		}
-	}
-
-	// If signer is not specified in the manifest, use the previously hardcoded default
-	if s.Stack.VersionManifest.Signer == nil {
-		s.Stack.VersionManifest.Signer = &types.ManifestEntry{
-			Image: "ghcr.io/hyperledger/firefly-signer",
-			Tag:   "v0.9.6",
-		}
-	}
-
 	stackHasRunBefore, err := s.Stack.HasRunBefore()
 	if err != nil {
 		return nil
@@ -339,7 +472,7 @@ func (s *StackManager) loadStackStateJSON() error {
 		return err
 	}
 
-	b, err := ioutil.ReadFile(stackStatePath)
+	b, err := migrateFile(s.Log, stackStatePath, stackStateMigrations, docker.DryRunFromContext(s.ctx))
 	if err != nil {
 		return err
 	}
@@ -359,6 +492,7 @@ func (s *StackManager) loadStackStateJSON() error {
 }
 
 func (s *StackManager) writeStackStateJSON(directory string) error {
+	s.Stack.State.SchemaVersion = StackSchemaVersion
 	stackStateBytes, err := json.MarshalIndent(s.Stack.State, "", "  ")
 	if err != nil {
 		return err
@@ -373,9 +507,11 @@ func (s *StackManager) ensureInitDirectories() error {
 		return err
 	}
 
-	for _, member := range s.Stack.Members {
-		if err := os.MkdirAll(filepath.Join(configDir, "dataexchange_"+member.ID, "peer-certs"), 0755); err != nil {
-			return err
+	if !s.Stack.DisableDataExchange {
+		for _, member := range s.Stack.Members {
+			if err := os.MkdirAll(filepath.Join(configDir, "dataexchange_"+member.ID, "peer-certs"), 0755); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -396,7 +532,11 @@ func (s *StackManager) writeDockerCompose(compose *docker.DockerComposeConfig) e
 func (s *StackManager) writeDockerComposeOverride(compose *docker.DockerComposeConfig) error {
 	comments := "# Add custom config overrides here\n# See https://docs.docker.com/compose/extends\n"
 	bytes := []byte(comments)
-	yamlBytes, err := yaml.Marshal(map[string]interface{}{"version": compose.Version})
+	override := map[string]interface{}{}
+	if compose.Version != "" {
+		override["version"] = compose.Version
+	}
+	yamlBytes, err := yaml.Marshal(override)
 	if err != nil {
 		return err
 	}
@@ -405,14 +545,42 @@ func (s *StackManager) writeDockerComposeOverride(compose *docker.DockerComposeC
 }
 
 func (s *StackManager) writeStackConfig() error {
+	if err := s.writeStackJSON(); err != nil {
+		return err
+	}
+	return s.writeStackStateJSON(s.Stack.InitDir)
+}
+
+func (s *StackManager) writeStackJSON() error {
+	s.Stack.SchemaVersion = StackSchemaVersion
 	stackConfigBytes, err := json.MarshalIndent(s.Stack, "", " ")
 	if err != nil {
 		return err
 	}
-	if err := ioutil.WriteFile(filepath.Join(s.Stack.StackDir, "stack.json"), stackConfigBytes, 0755); err != nil {
-		return err
+	return ioutil.WriteFile(filepath.Join(s.Stack.StackDir, "stack.json"), stackConfigBytes, 0755)
+}
+
+func (s *StackManager) generateCoreConfig(member *types.Organization) *types.FireflyConfig {
+	config := core.NewFireflyConfig(s.Stack, member)
+
+	// TODO: This code assumes that there is only one plugin instance per type. When we add support for
+	// multiple namespaces, this code will likely have to change a lot
+	blockchainConfig := s.blockchainProvider.GetBlockchainPluginConfig(s.Stack, member)
+	blockchainConfig.Name = "blockchain0"
+	config.Plugins.Blockchain = []*types.BlockchainConfig{
+		blockchainConfig,
 	}
-	return s.writeStackStateJSON(s.Stack.InitDir)
+
+	if config.Plugins.Tokens == nil {
+		config.Plugins.Tokens = []*types.TokensConfig{}
+	}
+
+	for iTok, tp := range s.tokenProviders {
+		tokenConfig := tp.GetFireflyConfig(member, iTok)
+		tokenConfig.Name = s.Stack.TokenProviderName(iTok)
+		config.Plugins.Tokens = append(config.Plugins.Tokens, tokenConfig)
+	}
+	return config
 }
 
 func (s *StackManager) writeConfig(options *types.InitOptions) error {
@@ -420,38 +588,28 @@ func (s *StackManager) writeConfig(options *types.InitOptions) error {
 		return err
 	}
 
-	for _, member := range s.Stack.Members {
-		config := core.NewFireflyConfig(s.Stack, member)
-
-		// TODO: This code assumes that there is only one plugin instance per type. When we add support for
-		// multiple namespaces, this code will likely have to change a lot
-		blockchainConfig := s.blockchainProvider.GetBlockchainPluginConfig(s.Stack, member)
-		blockchainConfig.Name = "blockchain0"
-		config.Plugins.Blockchain = []*types.BlockchainConfig{
-			blockchainConfig,
-		}
-
-		if config.Plugins.Tokens == nil {
-			config.Plugins.Tokens = []*types.TokensConfig{}
-		}
-
-		for iTok, tp := range s.tokenProviders {
-			tokenConfig := tp.GetFireflyConfig(member, iTok)
-			tokenConfig.Name = tp.GetName()
-			config.Plugins.Tokens = append(config.Plugins.Tokens, tokenConfig)
-		}
+	if err := s.writeAPIPasswordFile(); err != nil {
+		return err
+	}
 
+	for _, member := range s.Stack.Members {
+		config := s.generateCoreConfig(member)
 		coreConfigFilename := filepath.Join(s.Stack.InitDir, "config", fmt.Sprintf("firefly_core_%s.yml", member.ID))
-		if err := core.WriteFireflyConfig(config, coreConfigFilename, options.ExtraCoreConfigPath); err != nil {
+		extraCoreConfig, err := core.ReadExtraConfig(options.ExtraCoreConfigPath, s.Stack, member)
+		if err != nil {
+			return err
+		}
+		if err := core.WriteFireflyConfig(config, coreConfigFilename, extraCoreConfig); err != nil {
 			return err
 		}
 	}
 
-	if err := s.writeStackConfig(); err != nil {
+	if err := s.blockchainProvider.WriteConfig(options); err != nil {
 		return err
 	}
 
-	if err := s.blockchainProvider.WriteConfig(options); err != nil {
+	// Written after the blockchain config, which can record keys it generates on each member
+	if err := s.writeStackConfig(); err != nil {
 		return err
 	}
 
@@ -470,6 +628,9 @@ func (s *StackManager) writeConfig(options *types.InitOptions) error {
 }
 
 func (s *StackManager) writeDataExchangeCerts() error {
+	if s.Stack.DisableDataExchange {
+		return nil
+	}
 	configDir := filepath.Join(s.Stack.InitDir, "config")
 	for _, member := range s.Stack.Members {
 
@@ -495,11 +656,14 @@ func (s *StackManager) writeDataExchangeCerts() error {
 }
 
 func (s *StackManager) copyDataExchangeConfigToVolumes() error {
+	if s.Stack.DisableDataExchange {
+		return nil
+	}
 	configDir := filepath.Join(s.Stack.RuntimeDir, "config")
 	for _, member := range s.Stack.Members {
 		// Copy files into docker volumes
 		memberDXDir := path.Join(configDir, "dataexchange_"+member.ID)
-		volumeName := fmt.Sprintf("%s_dataexchange_%s", s.Stack.Name, member.ID)
+		volumeName := fmt.Sprintf("%s_dataexchange_%s", s.Stack.ResourcePrefix(), member.ID)
 		docker.MkdirInVolume(s.ctx, volumeName, "peer-certs")
 		if err := docker.CopyFileToVolume(s.ctx, volumeName, path.Join(memberDXDir, "config.json"), "/config.json"); err != nil {
 			return err
@@ -514,7 +678,7 @@ func (s *StackManager) copyDataExchangeConfigToVolumes() error {
 	return nil
 }
 
-func (s *StackManager) createMember(id string, index int, options *types.InitOptions, external bool) (*types.Organization, error) {
+func (s *StackManager) createMember(id string, index int, options *types.InitOptions, external bool, privateKey string) (*types.Organization, error) {
 	serviceBase := options.ServicesBasePort + (index * 100)
 	member := &types.Organization{
 		ID:                         id,
@@ -541,12 +705,20 @@ func (s *StackManager) createMember(id string, index int, options *types.InitOpt
 		member.ExposedFireflyMetricsPort = nextPort
 		nextPort++
 	}
-	for range options.TokenProviders {
+	for range s.Stack.TokenProviders {
 		member.ExposedTokensPorts = append(member.ExposedTokensPorts, nextPort)
 		nextPort++
 	}
 
-	account, err := s.blockchainProvider.CreateAccount([]string{member.OrgName, member.OrgName})
+	if privateKey == "" && s.Stack.Seed != nil && s.Stack.BlockchainProvider.Equals(types.BlockchainProviderEthereum) {
+		// Import a key derived from the seed, rather than let the signer generate a random one
+		privateKey = hex.EncodeToString(random.Bytes(s.Stack, fmt.Sprintf("members/%s/key", id), 32))
+	}
+	accountArgs := []string{member.OrgName, member.OrgName}
+	if privateKey != "" {
+		accountArgs = append(accountArgs, privateKey)
+	}
+	account, err := s.blockchainProvider.CreateAccount(accountArgs)
 	if err != nil {
 		return nil, err
 	}
@@ -556,20 +728,42 @@ func (s *StackManager) createMember(id string, index int, options *types.InitOpt
 		member.ExposedSandboxPort = nextPort
 		nextPort++
 	}
+	if options.MemberAPITokens {
+		member.APIToken = GenerateAPIAuthToken(random.Reader(s.Stack, fmt.Sprintf("members/%s/api-token", id)))
+	}
 	return member, nil
 }
 
 func (s *StackManager) StartStack(options *types.StartOptions) (messages []string, err error) {
 	fmt.Printf("starting FireFly stack '%s'... ", s.Stack.Name)
+	s.timer = &phaseTimer{start: time.Now()}
 	// Check to make sure all of our ports are available
 	err = s.checkPortsAvailable()
 	if err != nil {
 		return messages, err
 	}
+	if !options.SkipCompatibilityCheck {
+		warnings, err := CheckCompatibility(s.Stack, options.CLIVersion)
+		for _, warning := range warnings {
+			s.Log.Warn(warning)
+		}
+		if err != nil {
+			return messages, err
+		}
+	}
+	for _, advisory := range s.CheckResources() {
+		s.Log.Warn(advisory)
+	}
 	hasBeenRun, err := s.Stack.HasRunBefore()
 	if err != nil {
 		return messages, err
 	}
+	if len(options.Profiles) > 0 {
+		if !hasBeenRun {
+			return messages, errcodes.New(errcodes.StackNotStarted, "stack '%s' must be started once without --profile before a subset of it can be started", s.Stack.Name)
+		}
+		return messages, s.startComposeProfiles(options.Profiles)
+	}
 	if !hasBeenRun {
 		setupMessages, err := s.runFirstTimeSetup(options)
 		messages = append(messages, setupMessages...)
@@ -594,12 +788,22 @@ func (s *StackManager) StartStack(options *types.StartOptions) (messages []strin
 			}
 		}
 	} else {
+		endPhase := s.startPhase("start containers")
 		err = s.runStartupSequence(false)
 		if err != nil {
 			return messages, err
 		}
+		endPhase()
 	}
-	return messages, s.ensureFireflyNodesUp(true)
+	endPhase := s.startPhase("readiness checks")
+	if err := s.ensureFireflyNodesUp(true); err != nil {
+		return messages, err
+	}
+	if err := s.ensureWebSocketsUp(); err != nil {
+		return messages, err
+	}
+	endPhase()
+	return messages, s.writeAppEnv()
 }
 
 func (s *StackManager) PullStack(options *types.PullOptions) error {
@@ -619,7 +823,9 @@ func (s *StackManager) PullStack(options *types.PullOptions) error {
 		}
 	}
 
-	images = append(images, constants.IPFSImageName)
+	if !s.Stack.DisableIPFS {
+		images = append(images, constants.IPFSImageName)
+	}
 
 	// Also pull postgres if we're using it
 	if s.Stack.Database.Equals(types.DatabaseSelectionPostgres) {
@@ -631,6 +837,23 @@ func (s *StackManager) PullStack(options *types.PullOptions) error {
 		images = append(images, constants.SandboxImageName)
 	}
 
+	// Also pull the message broker if there is one
+	if s.Stack.MessageQueue.Equals(types.MessageQueueNATS) {
+		images = append(images, constants.NATSImageName)
+	} else if s.Stack.MessageQueue.Equals(types.MessageQueueRedis) {
+		images = append(images, constants.RedisImageName)
+	}
+
+	// Also pull the netem sidecar image if a latency profile is set
+	if s.Stack.LatencyProfile != "" {
+		images = append(images, constants.NetemImageName)
+	}
+
+	// Also pull the faketime sidecar image if a member has a clock skew
+	if len(s.Stack.ClockSkew) > 0 {
+		images = append(images, constants.FaketimeImageName)
+	}
+
 	// Iterate over all images used by the blockchain provider
 	for _, service := range s.blockchainProvider.GetDockerServiceDefinitions() {
 		if !manifestImages[service.Service.Image] {
@@ -648,13 +871,7 @@ func (s *StackManager) PullStack(options *types.PullOptions) error {
 	}
 
 	// Use docker to pull every image - retry on failure
-	for _, image := range images {
-		s.Log.Info(fmt.Sprintf("pulling '%s'", image))
-		if err := docker.RunDockerCommandRetry(s.ctx, s.Stack.InitDir, options.Retries, "pull", image); err != nil {
-			return err
-		}
-	}
-	return nil
+	return s.pullImages(images, options)
 }
 
 func (s *StackManager) removeVolumes() {
@@ -671,7 +888,7 @@ func (s *StackManager) removeVolumes() {
 		volumes = append(volumes, volumeName)
 	}
 	for _, volumeName := range volumes {
-		docker.RunDockerCommand(s.ctx, "", "volume", "remove", fmt.Sprintf("%s_%s", s.Stack.Name, volumeName))
+		docker.RunDockerCommand(s.ctx, "", "volume", "remove", fmt.Sprintf("%s_%s", s.Stack.ResourcePrefix(), volumeName))
 	}
 }
 
@@ -684,6 +901,9 @@ func (s *StackManager) runStartupSequence(firstTimeSetup bool) error {
 	if err := s.runDockerComposeCommand("up", "-d"); err != nil {
 		return err
 	}
+	if err := s.waitForHealthyServices(firstTimeSetup); err != nil {
+		return err
+	}
 
 	if err := s.blockchainProvider.PostStart(firstTimeSetup); err != nil {
 		return err
@@ -692,6 +912,25 @@ func (s *StackManager) runStartupSequence(firstTimeSetup bool) error {
 	return nil
 }
 
+// waitForHealthyServices waits for every service with a healthcheck to report healthy, so the steps after
+// startup do not have to retry until the blockchain node and connectors are ready. On the first start the
+// FireFly core containers are disabled, so they are skipped.
+func (s *StackManager) waitForHealthyServices(firstTimeSetup bool) error {
+	containers := []string{}
+	for name, service := range s.buildDockerCompose().Services {
+		if service.HealthCheck == nil || (firstTimeSetup && strings.HasPrefix(name, "firefly_core_")) {
+			continue
+		}
+		containers = append(containers, service.ContainerName)
+	}
+	if len(containers) == 0 {
+		return nil
+	}
+	sort.Strings(containers)
+	s.Log.Info("waiting for services to become healthy")
+	return docker.WaitForHealthy(s.ctx, containers, healthyTimeout)
+}
+
 func (s *StackManager) StopStack() error {
 	return s.runDockerComposeCommand("stop")
 }
@@ -715,7 +954,10 @@ func (s *StackManager) RemoveStack() error {
 		return err
 	}
 	s.removeVolumes()
-	return os.RemoveAll(s.Stack.StackDir)
+	if err := os.RemoveAll(s.Stack.StackDir); err != nil {
+		return err
+	}
+	return clearCurrentStackIf(s.Stack.Name)
 }
 
 func (s *StackManager) checkPortsAvailable() error {
@@ -733,9 +975,13 @@ func (s *StackManager) checkPortsAvailable() error {
 			ports = append(ports, member.ExposedFireflyPort)
 			ports = append(ports, member.ExposedFireflyMetricsPort)
 		}
-		ports = append(ports, member.ExposedDataexchangePort)
-		ports = append(ports, member.ExposedIPFSApiPort)
-		ports = append(ports, member.ExposedIPFSGWPort)
+		if !s.Stack.DisableDataExchange {
+			ports = append(ports, member.ExposedDataexchangePort)
+		}
+		if !s.Stack.DisableIPFS {
+			ports = append(ports, member.ExposedIPFSApiPort)
+			ports = append(ports, member.ExposedIPFSGWPort)
+		}
 		if s.Stack.SandboxEnabled {
 			ports = append(ports, member.ExposedSandboxPort)
 		}
@@ -745,13 +991,17 @@ func (s *StackManager) checkPortsAvailable() error {
 		ports = append(ports, s.Stack.ExposedPrometheusPort)
 	}
 
+	if s.Stack.MessageQueue != "" {
+		ports = append(ports, s.Stack.ExposedMessageQueuePort)
+	}
+
 	for _, port := range ports {
 		available, err := checkPortAvailable(port)
 		if err != nil {
 			return err
 		}
 		if !available {
-			return fmt.Errorf("port %d is unavailable. please check to see if another process is listening on that port", port)
+			return errcodes.New(errcodes.PortUnavailable, "port %d is unavailable. please check to see if another process is listening on that port", port)
 		}
 	}
 	return nil
@@ -812,13 +1062,15 @@ func (s *StackManager) runFirstTimeSetup(options *types.StartOptions) (messages
 	}
 
 	s.Log.Info("initializing blockchain node")
+	endPhase := s.startPhase("blockchain genesis")
 	if err := s.blockchainProvider.FirstTimeSetup(); err != nil {
 		return messages, err
 	}
+	endPhase()
 
 	if s.Stack.PrometheusEnabled {
 		s.Log.Info("copying prometheus.yml to prometheus_config")
-		volumeName := fmt.Sprintf("%s_prometheus_config", s.Stack.Name)
+		volumeName := fmt.Sprintf("%s_prometheus_config", s.Stack.ResourcePrefix())
 		if err := docker.CopyFileToVolume(s.ctx, volumeName, path.Join(configDir, "prometheus.yml"), "/prometheus.yml"); err != nil {
 			return messages, err
 		}
@@ -829,16 +1081,21 @@ func (s *StackManager) runFirstTimeSetup(options *types.StartOptions) (messages
 	}
 
 	pullOptions := &types.PullOptions{
-		Retries: 2,
+		Retries: 3,
 	}
+	endPhase = s.startPhase("pull images")
 	if err := s.PullStack(pullOptions); err != nil {
 		return messages, err
 	}
+	endPhase()
 
+	endPhase = s.startPhase("start containers")
 	if err := s.runStartupSequence(true); err != nil {
 		return messages, err
 	}
+	endPhase()
 
+	endPhase = s.startPhase("deploy contracts")
 	for i, tp := range s.tokenProviders {
 		if !s.Stack.DisableTokenFactories {
 			result, err := tp.DeploySmartContracts(i)
@@ -861,13 +1118,22 @@ func (s *StackManager) runFirstTimeSetup(options *types.StartOptions) (messages
 				{
 					Name:        "default",
 					Description: "Default predefined namespace",
-					Plugins:     []string{"database0", "blockchain0", "dataexchange0", "sharedstorage0"},
+					Plugins:     []string{"database0", "blockchain0"},
 				},
 			},
 		},
 	}
 
-	newConfig.Namespaces.Predefined[0].Plugins = append(newConfig.Namespaces.Predefined[0].Plugins, types.FFEnumArrayToStrings(s.Stack.TokenProviders)...)
+	if !s.Stack.DisableDataExchange {
+		newConfig.Namespaces.Predefined[0].Plugins = append(newConfig.Namespaces.Predefined[0].Plugins, "dataexchange0")
+	}
+	if !s.Stack.DisableIPFS {
+		newConfig.Namespaces.Predefined[0].Plugins = append(newConfig.Namespaces.Predefined[0].Plugins, "sharedstorage0")
+	}
+
+	for iTok := range s.tokenProviders {
+		newConfig.Namespaces.Predefined[0].Plugins = append(newConfig.Namespaces.Predefined[0].Plugins, s.Stack.TokenProviderName(iTok))
+	}
 
 	var contractDeploymentResult *types.ContractDeploymentResult
 	if s.Stack.MultipartyEnabled {
@@ -888,6 +1154,8 @@ func (s *StackManager) runFirstTimeSetup(options *types.StartOptions) (messages
 		}
 	}
 
+	endPhase()
+
 	for _, member := range s.Stack.Members {
 		orgConfig := s.blockchainProvider.GetOrgConfig(s.Stack, member)
 		newConfig.Namespaces.Predefined[0].DefaultKey = orgConfig.Key
@@ -913,6 +1181,7 @@ func (s *StackManager) runFirstTimeSetup(options *types.StartOptions) (messages
 
 	// Restart all containers now that we've finalized the runtime config
 	s.Log.Info("restarting containers")
+	endPhase = s.startPhase("restart containers")
 	if err := s.runDockerComposeCommand("stop"); err != nil {
 		return messages, err
 	}
@@ -923,20 +1192,25 @@ func (s *StackManager) runFirstTimeSetup(options *types.StartOptions) (messages
 	if err := s.ensureFireflyNodesUp(true); err != nil {
 		return messages, err
 	}
+	endPhase()
 
 	if s.Stack.MultipartyEnabled {
 		s.Log.Info("registering FireFly identities")
+		endPhase = s.startPhase("identity registration")
 		if err := s.registerFireflyIdentities(); err != nil {
 			return messages, err
 		}
+		endPhase()
 	}
 
 	s.Log.Info("initializing token providers")
+	endPhase = s.startPhase("token provider setup")
 	for iTok, tp := range s.tokenProviders {
 		if err := tp.FirstTimeSetup(iTok); err != nil {
 			return messages, err
 		}
 	}
+	endPhase()
 
 	// Update the stack state with any new state that was created as a part of the setup process
 	return messages, s.writeStackStateJSON(s.Stack.RuntimeDir)
@@ -968,6 +1242,35 @@ func (s *StackManager) ensureFireflyNodesUp(firstTimeSetup bool) error {
 	return nil
 }
 
+// ensureWebSocketsUp checks that each member's WebSocket endpoint delivers the events already recorded
+// in its namespace. It does not create anything to cause an event, because it runs on every start and
+// refresh. A member with no events yet can only be checked for accepting the subscription.
+// The core of an external member runs in a debugger or IDE, where it may be paused, so it is not checked.
+func (s *StackManager) ensureWebSocketsUp() error {
+	for _, member := range s.Stack.Members {
+		if member.External {
+			s.Log.Info(fmt.Sprintf("skipping the websocket check for member %s, which runs outside the stack", member.ID))
+			continue
+		}
+		s.Log.Info(fmt.Sprintf("checking websocket events for member %s", member.ID))
+		var events []interface{}
+		eventsURL := fmt.Sprintf("http://127.0.0.1:%d/api/v1/namespaces/default/events?limit=1", member.ExposedFireflyPort)
+		if err := core.RequestWithRetry(s.ctx, "GET", eventsURL, nil, &events); err != nil {
+			return err
+		}
+		timeout := 30 * time.Second
+		if len(events) == 0 {
+			s.Log.Warn(fmt.Sprintf("member %s has no events yet, so its websocket is only checked for accepting a subscription", member.ID))
+			timeout = 2 * time.Second
+		}
+		wsURL := fmt.Sprintf("ws://127.0.0.1:%d/ws", member.ExposedFireflyPort)
+		if err := core.CheckWebSocket(s.ctx, wsURL, "default", len(events) > 0, timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *StackManager) waitForFireflyStart(port int) error {
 	retries := 120
 	retryPeriod := 1000 // ms
@@ -983,13 +1286,17 @@ func (s *StackManager) waitForFireflyStart(port int) error {
 		}
 		retriesRemaining--
 	}
-	return fmt.Errorf("waited for %v seconds for firefly to start on port %v but it was never available", retries*retryPeriod/1000, port)
+	return errcodes.New(errcodes.FireFlyStartTimeout, "waited for %v seconds for firefly to start on port %v but it was never available", retries*retryPeriod/1000, port)
 }
 
 func (s *StackManager) UpgradeStack() error {
 	if err := s.runDockerComposeCommand("down"); err != nil {
 		return err
 	}
+	if len(s.Stack.LocalImages) > 0 {
+		// Locally built images only exist on this machine, so they can't be pulled
+		return s.runDockerComposeCommand("pull", "--ignore-pull-failures")
+	}
 	return s.runDockerComposeCommand("pull")
 }
 
@@ -1012,6 +1319,7 @@ func (s *StackManager) disableFireflyCoreContainers() error {
 		if !member.External {
 			// Temporarily set the entrypoint to not run anything
 			compose.Services[fmt.Sprintf("firefly_core_%v", *member.Index)].EntryPoint = []string{"/bin/sh", "-c", "exit", "0"}
+			compose.Services[fmt.Sprintf("firefly_core_%v", *member.Index)].Restart = ""
 		}
 	}
 	return s.writeDockerCompose(compose)
@@ -1123,6 +1431,10 @@ func (s *StackManager) getBlockchainProvider() blockchain.IBlockchainProvider {
 			return geth.NewGethProvider(s.ctx, s.Stack)
 		case types.BlockchainNodeProviderBesu:
 			return besu.NewBesuProvider(s.ctx, s.Stack)
+		case types.BlockchainNodeProviderQuorum:
+			return quorum.NewQuorumProvider(s.ctx, s.Stack)
+		case types.BlockchainNodeProviderAnvil:
+			return anvil.NewAnvilProvider(s.ctx, s.Stack)
 		case types.BlockchainNodeProviderRemoteRPC:
 			s.Stack.DisableTokenFactories = true
 			return remoterpc.NewRemoteRPCProvider(s.ctx, s.Stack)
@@ -1150,3 +1462,9 @@ func (s *StackManager) getITokenProviders() []tokens.ITokensProvider {
 	}
 	return tps
 }
+
+// SetProtected marks the stack as protected, so destructive commands need an extra confirmation flag
+func (s *StackManager) SetProtected(protected bool) error {
+	s.Stack.Protected = protected
+	return s.writeStackJSON()
+}
@@ -0,0 +1,237 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/internal/blockchain/ethereum"
+	"github.com/hyperledger/firefly-cli/internal/blockchain/fabric"
+	"github.com/hyperledger/firefly-cli/internal/constants"
+	"github.com/hyperledger/firefly-cli/internal/random"
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// CreateTenants adds count gateway mode namespaces to every member of a stack that has already been
+// started. Each tenant gets a signing key on each member, and a username and token that only give
+// access to its own namespace. The FireFly core containers are recreated to mount the password files
+// and load the new namespaces, keeping their SQLite databases. Stacks whose API needs a token are refused.
+func (s *StackManager) CreateTenants(count int) ([]*types.Tenant, error) {
+	// FireFly checks the server wide basic auth of the API before the auth plugin of a namespace, so a
+	// tenant's token would never get through on a stack whose API needs the member's password
+	for _, member := range s.Stack.Members {
+		if s.Stack.APIToken(member) != "" {
+			return nil, fmt.Errorf("stack '%s' requires an API token for every request, which tenant tokens cannot pass. tenants can only be created on stacks created without --api-auth or --member-api-tokens", s.Stack.Name)
+		}
+	}
+	hasBeenRun, err := s.Stack.HasRunBefore()
+	if err != nil {
+		return nil, err
+	}
+	if !hasBeenRun {
+		return nil, fmt.Errorf("stack '%s' has not been started yet. start it before creating tenants", s.Stack.Name)
+	}
+
+	tenants := make([]*types.Tenant, 0, count)
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("tenant%d", len(s.Stack.Tenants)+i+1)
+		tenant := &types.Tenant{
+			Name:     name,
+			Username: name,
+			Token:    GenerateAPIAuthToken(random.Reader(s.Stack, fmt.Sprintf("tenants/%s/token", name))),
+			Keys:     make(map[string]string),
+		}
+		for _, member := range s.Stack.Members {
+			s.Log.Info(fmt.Sprintf("creating key for %s on member %s", name, member.ID))
+			key, err := s.createTenantKey(member, name)
+			if err != nil {
+				return nil, err
+			}
+			tenant.Keys[member.ID] = key
+		}
+		tenants = append(tenants, tenant)
+	}
+	s.Stack.Tenants = append(s.Stack.Tenants, tenants...)
+
+	if err := s.writeTenantPasswordFiles(); err != nil {
+		return nil, err
+	}
+	configDir := filepath.Join(s.Stack.RuntimeDir, "config")
+	coreServices := []string{}
+	for _, member := range s.Stack.Members {
+		configFile := path.Join(configDir, fmt.Sprintf("firefly_core_%s.yml", member.ID))
+		// A member running outside docker reads the password files straight from the runtime directory
+		passwordDir := constants.TenantPasswordDir
+		if member.External {
+			passwordDir = filepath.Join(configDir, "tenants")
+		}
+		if err := addTenantsToConfigFile(configFile, member, tenants, passwordDir); err != nil {
+			return nil, err
+		}
+		if !member.External {
+			coreServices = append(coreServices, fmt.Sprintf("firefly_core_%s", member.ID))
+		}
+	}
+	if err := s.writeStackJSON(); err != nil {
+		return nil, err
+	}
+	if err := s.writeStackStateJSON(s.Stack.RuntimeDir); err != nil {
+		return nil, err
+	}
+	if err := s.writeDockerCompose(s.buildDockerCompose()); err != nil {
+		return nil, err
+	}
+
+	s.Log.Info("recreating FireFly core to load the new namespaces")
+	if err := s.forceRecreate(coreServices...); err != nil {
+		return nil, err
+	}
+	for _, member := range s.Stack.Members {
+		if !member.External {
+			if err := s.waitForFireflyStart(member.ExposedFireflyPort); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return tenants, nil
+}
+
+// createTenantKey creates a new signing key for a tenant on a member, and returns the key
+// in the form FireFly uses as the default key of a namespace
+func (s *StackManager) createTenantKey(member *types.Organization, tenantName string) (string, error) {
+	args := []string{}
+	if s.Stack.BlockchainProvider.Equals(types.BlockchainProviderFabric) {
+		args = []string{member.OrgName, tenantName}
+	} else if s.Stack.Seed != nil {
+		// Import a key derived from the seed, rather than let the signer generate a random one
+		privateKey := hex.EncodeToString(random.Bytes(s.Stack, fmt.Sprintf("tenants/%s/members/%s/key", tenantName, member.ID), 32))
+		args = []string{member.OrgName, tenantName, privateKey}
+	}
+	account, err := s.blockchainProvider.CreateAccount(args)
+	if err != nil {
+		return "", err
+	}
+	s.Stack.State.Accounts = append(s.Stack.State.Accounts, account)
+	switch a := account.(type) {
+	case *ethereum.Account:
+		return a.Address, nil
+	case *fabric.Account:
+		return a.Name, nil
+	default:
+		return "", errors.New("tenants are not supported for this blockchain provider")
+	}
+}
+
+func (s *StackManager) writeTenantPasswordFiles() error {
+	tenantDir := filepath.Join(s.Stack.RuntimeDir, "config", "tenants")
+	if err := os.MkdirAll(tenantDir, 0755); err != nil {
+		return err
+	}
+	for _, tenant := range s.Stack.Tenants {
+		hash, err := hashPassword(s.Stack, fmt.Sprintf("tenants/%s/password", tenant.Name), tenant.Token)
+		if err != nil {
+			return err
+		}
+		content := fmt.Sprintf("%s:%s\n", tenant.Username, hash)
+		if err := ioutil.WriteFile(filepath.Join(tenantDir, tenant.Name+"_users"), []byte(content), 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addTenantsToConfigFile(configFile string, member *types.Organization, tenants []*types.Tenant, passwordDir string) error {
+	b, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return err
+	}
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(b, &config); err != nil {
+		return err
+	}
+	if err := addTenantNamespaces(config, member, tenants, passwordDir); err != nil {
+		return fmt.Errorf("failed to add tenants to %s: %s", configFile, err)
+	}
+	if b, err = yaml.Marshal(config); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(configFile, b, 0755)
+}
+
+// addTenantNamespaces adds a gateway mode namespace, and a basic auth plugin, for each tenant to a
+// FireFly core config. The namespaces use the same database, blockchain and token plugins as the
+// default namespace. Data exchange and shared storage are left out, as they are only for multiparty.
+func addTenantNamespaces(config map[string]interface{}, member *types.Organization, tenants []*types.Tenant, passwordDir string) error {
+	namespaces, _ := config["namespaces"].(map[string]interface{})
+	if namespaces == nil {
+		return errors.New("no namespaces are configured")
+	}
+	predefined, _ := namespaces["predefined"].([]interface{})
+	var plugins []interface{}
+	for _, ns := range predefined {
+		if ns, ok := ns.(map[string]interface{}); ok && ns["name"] == namespaces["default"] {
+			for _, plugin := range asList(ns["plugins"]) {
+				name := fmt.Sprint(plugin)
+				if !strings.HasPrefix(name, "dataexchange") && !strings.HasPrefix(name, "sharedstorage") {
+					plugins = append(plugins, name)
+				}
+			}
+		}
+	}
+	if plugins == nil {
+		return errors.New("the default namespace was not found")
+	}
+
+	pluginsConfig, _ := config["plugins"].(map[string]interface{})
+	if pluginsConfig == nil {
+		pluginsConfig = map[string]interface{}{}
+		config["plugins"] = pluginsConfig
+	}
+	auth := asList(pluginsConfig["auth"])
+	for _, tenant := range tenants {
+		authName := tenant.Name + "_auth"
+		auth = append(auth, map[string]interface{}{
+			"name": authName,
+			"type": "basic",
+			"basic": map[string]interface{}{
+				"passwordfile": path.Join(passwordDir, tenant.Name+"_users"),
+			},
+		})
+		predefined = append(predefined, map[string]interface{}{
+			"name":        tenant.Name,
+			"description": fmt.Sprintf("Demo tenant %s", tenant.Name),
+			"plugins":     append(append([]interface{}{}, plugins...), authName),
+			"defaultKey":  tenant.Keys[member.ID],
+		})
+	}
+	pluginsConfig["auth"] = auth
+	namespaces["predefined"] = predefined
+	return nil
+}
+
+func asList(v interface{}) []interface{} {
+	list, _ := v.([]interface{})
+	return list
+}
@@ -0,0 +1,78 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestAddTenantNamespaces(t *testing.T) {
+	var config map[string]interface{}
+	err := yaml.Unmarshal([]byte(`
+plugins:
+  database:
+  - name: database0
+namespaces:
+  default: default
+  predefined:
+  - name: default
+    plugins: [database0, blockchain0, dataexchange0, sharedstorage0, erc20_erc721]
+    defaultKey: "0x1111"
+`), &config)
+	assert.NoError(t, err)
+
+	member := &types.Organization{ID: "0"}
+	tenants := []*types.Tenant{{Name: "tenant1", Keys: map[string]string{"0": "0x2222"}}}
+	err = addTenantNamespaces(config, member, tenants, "/etc/firefly/tenants")
+	assert.NoError(t, err)
+
+	predefined := config["namespaces"].(map[string]interface{})["predefined"].([]interface{})
+	assert.Len(t, predefined, 2)
+	ns := predefined[1].(map[string]interface{})
+	assert.Equal(t, "tenant1", ns["name"])
+	assert.Equal(t, "0x2222", ns["defaultKey"])
+	assert.Equal(t, []interface{}{"database0", "blockchain0", "erc20_erc721", "tenant1_auth"}, ns["plugins"])
+
+	plugins := config["plugins"].(map[string]interface{})
+	assert.Len(t, plugins["database"], 1)
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"name":  "tenant1_auth",
+		"type":  "basic",
+		"basic": map[string]interface{}{"passwordfile": "/etc/firefly/tenants/tenant1_users"},
+	}}, plugins["auth"])
+}
+
+func TestAddTenantNamespacesNoDefault(t *testing.T) {
+	config := map[string]interface{}{
+		"namespaces": map[string]interface{}{"default": "default"},
+	}
+	err := addTenantNamespaces(config, &types.Organization{ID: "0"}, nil, "/etc/firefly/tenants")
+	assert.Regexp(t, "default namespace was not found", err)
+}
+
+func TestCreateTenantsAPIAuth(t *testing.T) {
+	s := &StackManager{Stack: &types.Stack{
+		Name:    "authtest",
+		Members: []*types.Organization{{ID: "0"}, {ID: "1", APIToken: "token1"}},
+	}}
+	_, err := s.CreateTenants(1)
+	assert.Regexp(t, "tenant tokens cannot pass", err)
+}
@@ -0,0 +1,116 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/constants"
+)
+
+type PhaseTiming struct {
+	Phase      string `json:"phase"`
+	DurationMS int64  `json:"durationMs"`
+}
+
+// TimingRecord is one entry in the local stats file. Nothing in it is ever sent anywhere.
+type TimingRecord struct {
+	Command                string         `json:"command"`
+	Stack                  string         `json:"stack"`
+	Time                   time.Time      `json:"time"`
+	FireFlyVersion         string         `json:"fireflyVersion,omitempty"`
+	BlockchainNodeProvider string         `json:"blockchainNodeProvider,omitempty"`
+	Members                int            `json:"members"`
+	OS                     string         `json:"os"`
+	Arch                   string         `json:"arch"`
+	TotalMS                int64          `json:"totalMs"`
+	Phases                 []*PhaseTiming `json:"phases"`
+}
+
+type phaseTimer struct {
+	start  time.Time
+	phases []*PhaseTiming
+}
+
+// startPhase starts timing a phase of init or start, and returns a function to call when the phase ends
+func (s *StackManager) startPhase(phase string) func() {
+	if s.timer == nil {
+		s.timer = &phaseTimer{start: time.Now()}
+	}
+	start := time.Now()
+	return func() {
+		s.timer.phases = append(s.timer.phases, &PhaseTiming{
+			Phase:      phase,
+			DurationMS: time.Since(start).Milliseconds(),
+		})
+	}
+}
+
+// PrintTimingSummary prints how long each phase of the command took, and appends the timings to the local
+// stats file so startup time can be compared across versions and machines
+func (s *StackManager) PrintTimingSummary(command string) error {
+	if s.timer == nil {
+		return nil
+	}
+	record := &TimingRecord{
+		Command:                command,
+		Stack:                  s.Stack.Name,
+		Time:                   s.timer.start,
+		BlockchainNodeProvider: s.Stack.BlockchainNodeProvider.String(),
+		Members:                len(s.Stack.Members),
+		OS:                     runtime.GOOS,
+		Arch:                   runtime.GOARCH,
+		TotalMS:                time.Since(s.timer.start).Milliseconds(),
+		Phases:                 s.timer.phases,
+	}
+	if s.Stack.VersionManifest != nil && s.Stack.VersionManifest.FireFly != nil {
+		record.FireFlyVersion = s.Stack.VersionManifest.FireFly.Tag
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Timing summary for '%s %s':\n", command, s.Stack.Name)
+	for _, p := range record.Phases {
+		fmt.Fprintf(w, "  %s\t%s\n", p.Phase, time.Duration(p.DurationMS)*time.Millisecond)
+	}
+	fmt.Fprintf(w, "  total\t%s\n\n", time.Duration(record.TotalMS)*time.Millisecond)
+	w.Flush()
+
+	return appendTimingRecord(record)
+}
+
+func appendTimingRecord(record *TimingRecord) error {
+	if err := os.MkdirAll(filepath.Dir(constants.StatsFile), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(constants.StatsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,118 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+)
+
+var tokenProviderNameRegex = regexp.MustCompile(`^[0-9a-zA-Z]([0-9a-zA-Z._-]{0,62}[0-9a-zA-Z])?$`)
+
+// The names of the other plugins in the generated core config, which a tokens plugin must not reuse
+var reservedPluginNames = map[string]bool{
+	"database0":      true,
+	"blockchain0":    true,
+	"dataexchange0":  true,
+	"sharedstorage0": true,
+}
+
+// ParseTokenProviders parses the --token-providers values of ff init. Each value is a connector type,
+// optionally followed by either ":<name>" to name the instance, or "*<count>" to run several instances
+// of the same type. It returns the type and the FireFly plugin name of each connector instance. An
+// instance without a name is named after its type, with a numeric suffix if the type is used more than once.
+func ParseTokenProviders(ctx context.Context, input []string) ([]fftypes.FFEnum, []string, error) {
+	providers := []fftypes.FFEnum{}
+	names := []string{}
+	for _, value := range input {
+		typeString, name, count := value, "", 1
+		if i := strings.Index(value, ":"); i >= 0 {
+			typeString, name = value[:i], value[i+1:]
+			if !tokenProviderNameRegex.MatchString(name) {
+				return nil, nil, fmt.Errorf("invalid token connector name '%s' in '%s'", name, value)
+			}
+		} else if i := strings.Index(value, "*"); i >= 0 {
+			typeString = value[:i]
+			c, err := strconv.Atoi(value[i+1:])
+			if err != nil || c < 1 {
+				return nil, nil, fmt.Errorf("invalid token connector count in '%s'. it must be a positive number", value)
+			}
+			count = c
+		}
+		tp, err := fftypes.FFEnumParseString(ctx, types.TokenProvider, typeString)
+		if err != nil {
+			return nil, nil, err
+		}
+		if tp.Equals(types.TokenProviderNone) {
+			if name != "" || count != 1 {
+				return nil, nil, fmt.Errorf("'%s' cannot be named or repeated", types.TokenProviderNone)
+			}
+			continue
+		}
+		for i := 0; i < count; i++ {
+			providers = append(providers, tp)
+			names = append(names, name)
+		}
+	}
+
+	unnamed := map[fftypes.FFEnum]int{}
+	for i, tp := range providers {
+		if names[i] == "" {
+			unnamed[tp]++
+		}
+	}
+	used := map[string]bool{}
+	for _, name := range names {
+		used[name] = true
+	}
+	next := map[fftypes.FFEnum]int{}
+	for i, tp := range providers {
+		if names[i] != "" {
+			continue
+		}
+		name := tp.String()
+		if unnamed[tp] > 1 || used[name] {
+			for {
+				next[tp]++
+				name = fmt.Sprintf("%s_%d", tp, next[tp])
+				if !used[name] {
+					break
+				}
+			}
+		}
+		names[i] = name
+		used[name] = true
+	}
+
+	seen := map[string]bool{}
+	for _, name := range names {
+		if reservedPluginNames[name] {
+			return nil, nil, fmt.Errorf("token connector name '%s' is used by another FireFly plugin", name)
+		}
+		if seen[name] {
+			return nil, nil, fmt.Errorf("token connector name '%s' is used more than once", name)
+		}
+		seen[name] = true
+	}
+	return providers, names, nil
+}
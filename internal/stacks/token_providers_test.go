@@ -0,0 +1,63 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-cli/pkg/types"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTokenProviders(t *testing.T) {
+	ctx := context.Background()
+
+	providers, names, err := ParseTokenProviders(ctx, []string{"erc20_erc721", "erc1155"})
+	assert.NoError(t, err)
+	assert.Equal(t, []fftypes.FFEnum{types.TokenProviderERC20_ERC721, types.TokenProviderERC1155}, providers)
+	assert.Equal(t, []string{"erc20_erc721", "erc1155"}, names)
+
+	providers, names, err = ParseTokenProviders(ctx, []string{"erc20_erc721:fungible", "erc20_erc721:nfts"})
+	assert.NoError(t, err)
+	assert.Equal(t, []fftypes.FFEnum{types.TokenProviderERC20_ERC721, types.TokenProviderERC20_ERC721}, providers)
+	assert.Equal(t, []string{"fungible", "nfts"}, names)
+
+	providers, names, err = ParseTokenProviders(ctx, []string{"erc20_erc721*2", "erc1155", "erc20_erc721:erc20_erc721_1"})
+	assert.NoError(t, err)
+	assert.Len(t, providers, 4)
+	assert.Equal(t, []string{"erc20_erc721_2", "erc20_erc721_3", "erc1155", "erc20_erc721_1"}, names)
+
+	providers, names, err = ParseTokenProviders(ctx, []string{"none"})
+	assert.NoError(t, err)
+	assert.Empty(t, providers)
+	assert.Empty(t, names)
+
+	_, _, err = ParseTokenProviders(ctx, []string{"erc20_erc721:a", "erc1155:a"})
+	assert.Regexp(t, "'a' is used more than once", err)
+	_, _, err = ParseTokenProviders(ctx, []string{"erc1155:database0"})
+	assert.Regexp(t, "used by another FireFly plugin", err)
+	_, _, err = ParseTokenProviders(ctx, []string{"erc1155:-bad"})
+	assert.Regexp(t, "invalid token connector name", err)
+	_, _, err = ParseTokenProviders(ctx, []string{"erc1155*0"})
+	assert.Regexp(t, "must be a positive number", err)
+	_, _, err = ParseTokenProviders(ctx, []string{"none:x"})
+	assert.Regexp(t, "cannot be named or repeated", err)
+	_, _, err = ParseTokenProviders(ctx, []string{"erc777"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,242 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/core"
+	"github.com/hyperledger/firefly-cli/internal/docker"
+)
+
+type ServiceSnapshot struct {
+	Service     string
+	State       string
+	Health      string
+	Restarts    int
+	Uptime      time.Duration
+	CPUPercent  float64
+	MemoryBytes float64
+}
+
+type MemberActivity struct {
+	ID           string
+	Messages     int64
+	Transactions int64
+	Err          error
+}
+
+type LogLine struct {
+	Service string
+	Time    string
+	Text    string
+}
+
+// TopSnapshot is one refresh of the "ff top" dashboard
+type TopSnapshot struct {
+	Stack    string
+	Time     time.Time
+	Services []*ServiceSnapshot
+	Members  []*MemberActivity
+	Logs     []*LogLine
+}
+
+type countResponse struct {
+	Total int64 `json:"total"`
+}
+
+// TopSnapshot collects the status and resource usage of every container in the stack, the message and
+// transaction counts of each FireFly node, and the most recent log lines across all services
+func (s *StackManager) TopSnapshot(logLines int) (*TopSnapshot, error) {
+	prefix := fmt.Sprintf("%s_", s.Stack.ResourcePrefix())
+	snapshot := &TopSnapshot{
+		Stack: s.Stack.Name,
+		Time:  time.Now(),
+	}
+	containers, err := docker.ListContainers(s.ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no containers found for stack '%s'", s.Stack.Name)
+	}
+	statuses, err := docker.GetContainerStatus(s.ctx, containers)
+	if err != nil {
+		return nil, err
+	}
+	running := []string{}
+	services := map[string]*ServiceSnapshot{}
+	for _, status := range statuses {
+		service := &ServiceSnapshot{
+			Service:  strings.TrimPrefix(status.Name, prefix),
+			State:    status.State,
+			Health:   status.Health,
+			Restarts: status.RestartCount,
+		}
+		if status.State == "running" {
+			service.Uptime = snapshot.Time.Sub(status.StartedAt).Round(time.Second)
+			running = append(running, status.Name)
+		}
+		services[status.Name] = service
+		snapshot.Services = append(snapshot.Services, service)
+	}
+	sort.Slice(snapshot.Services, func(i, j int) bool { return snapshot.Services[i].Service < snapshot.Services[j].Service })
+
+	if len(running) > 0 {
+		stats, err := docker.GetContainerStats(s.ctx, running)
+		if err != nil {
+			return nil, err
+		}
+		for _, stat := range stats {
+			if service, ok := services[stat.Name]; ok {
+				service.CPUPercent = stat.CPUPercent
+				service.MemoryBytes = stat.MemoryBytes
+			}
+		}
+	}
+
+	for _, member := range s.Stack.Members {
+		snapshot.Members = append(snapshot.Members, s.memberActivity(member.ID, member.ExposedFireflyPort))
+	}
+
+	if logLines > 0 {
+		for _, name := range running {
+			lines, err := docker.GetRecentLogs(s.ctx, name, logLines)
+			if err != nil {
+				continue
+			}
+			for _, line := range lines {
+				logLine := &LogLine{Service: services[name].Service, Text: line}
+				if i := strings.Index(line, " "); i > 0 {
+					logLine.Time = line[:i]
+					logLine.Text = line[i+1:]
+				}
+				snapshot.Logs = append(snapshot.Logs, logLine)
+			}
+		}
+		sort.SliceStable(snapshot.Logs, func(i, j int) bool { return snapshot.Logs[i].Time < snapshot.Logs[j].Time })
+		if len(snapshot.Logs) > logLines {
+			snapshot.Logs = snapshot.Logs[len(snapshot.Logs)-logLines:]
+		}
+	}
+	return snapshot, nil
+}
+
+func (s *StackManager) memberActivity(id string, port int) *MemberActivity {
+	activity := &MemberActivity{ID: id}
+	ffURL := fmt.Sprintf("http://127.0.0.1:%d/api/v1", port)
+	var messages, transactions countResponse
+	if activity.Err = core.Request(http.MethodGet, fmt.Sprintf("%s/messages?count=true&limit=1", ffURL), nil, &messages); activity.Err != nil {
+		return activity
+	}
+	if activity.Err = core.Request(http.MethodGet, fmt.Sprintf("%s/transactions?count=true&limit=1", ffURL), nil, &transactions); activity.Err != nil {
+		return activity
+	}
+	activity.Messages = messages.Total
+	activity.Transactions = transactions.Total
+	return activity
+}
+
+// RenderTop writes the dashboard for a snapshot, cutting lines to the width of the terminal. Log lines
+// fill whatever is left of the height. A width or height of zero means there is no limit. Services that are not running, unhealthy or restarting are shown
+// in red if color is enabled.
+func RenderTop(w io.Writer, snapshot *TopSnapshot, width, height int, color bool) {
+	lines := []string{
+		fmt.Sprintf("Stack '%s' at %s", snapshot.Stack, snapshot.Time.Format("15:04:05")),
+		"",
+	}
+
+	buff := &bytes.Buffer{}
+	tw := tabwriter.NewWriter(buff, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVICE\tSTATE\tHEALTH\tRESTARTS\tUPTIME\tCPU\tMEM")
+	for _, service := range snapshot.Services {
+		health := service.Health
+		if health == "" {
+			health = "-"
+		}
+		uptime, cpu, mem := "-", "-", "-"
+		if service.State == "running" {
+			uptime = service.Uptime.String()
+			cpu = fmt.Sprintf("%.2f%%", service.CPUPercent)
+			mem = formatBytes(service.MemoryBytes)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n", service.Service, service.State, health, service.Restarts, uptime, cpu, mem)
+	}
+	tw.Flush()
+	tableLines := strings.Split(strings.TrimRight(buff.String(), "\n"), "\n")
+	lines = append(lines, tableLines[0])
+	for i, service := range snapshot.Services {
+		line := truncate(tableLines[i+1], width)
+		if color && (service.State != "running" || service.Health == "unhealthy" || service.Restarts > 0) {
+			line = fmt.Sprintf("\u001b[31m%s\u001b[0m", line)
+		}
+		lines = append(lines, line)
+	}
+
+	buff.Reset()
+	tw = tabwriter.NewWriter(buff, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MEMBER\tMESSAGES\tTRANSACTIONS")
+	for _, member := range snapshot.Members {
+		if member.Err != nil {
+			fmt.Fprintf(tw, "%s\t-\t-\t(%s)\n", member.ID, member.Err)
+		} else {
+			fmt.Fprintf(tw, "%s\t%d\t%d\n", member.ID, member.Messages, member.Transactions)
+		}
+	}
+	tw.Flush()
+	lines = append(lines, "")
+	lines = append(lines, strings.Split(strings.TrimRight(buff.String(), "\n"), "\n")...)
+
+	logs := snapshot.Logs
+	if remaining := height - len(lines) - 2; height > 0 && remaining < len(logs) {
+		if remaining < 0 {
+			remaining = 0
+		}
+		logs = logs[len(logs)-remaining:]
+	}
+	if len(logs) > 0 {
+		lines = append(lines, "", "RECENT LOGS")
+		for _, l := range logs {
+			lines = append(lines, fmt.Sprintf("[%s] %s", l.Service, l.Text))
+		}
+	}
+
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "\u001b") {
+			line = truncate(line, width)
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width])
+}
@@ -0,0 +1,201 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+)
+
+// watchTarget is a service to restart when a watched file changes. Files that are copied into a
+// volume when the stack is first started, rather than mounted, are copied again before the restart.
+type watchTarget struct {
+	Service string
+	Volume  string
+	Dest    string
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watch polls the config files of the stack, and any extra paths, and restarts the services that
+// use a file when it changes. Extra paths map a file or directory to the services to restart.
+// It runs until the context is cancelled.
+func (s *StackManager) Watch(ctx context.Context, extraPaths map[string][]string, interval time.Duration) error {
+	roots := s.watchTargets(extraPaths)
+	if len(roots) == 0 {
+		return fmt.Errorf("stack '%s' has no config files to watch", s.Stack.Name)
+	}
+	targets, prev := snapshotFiles(roots)
+	s.Log.Info(fmt.Sprintf("watching %d files for changes", len(prev)))
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+		var curr map[string]fileState
+		targets, curr = snapshotFiles(roots)
+		changed := changedFiles(prev, curr)
+		prev = curr
+		if len(changed) > 0 {
+			if err := s.applyWatchedChanges(changed, targets); err != nil {
+				// Keep watching, so the next edit can fix the problem
+				s.Log.Error(err)
+			}
+		}
+	}
+}
+
+func (s *StackManager) applyWatchedChanges(changed []string, targets map[string][]watchTarget) error {
+	services := []string{}
+	seen := make(map[string]bool)
+	for _, file := range changed {
+		for _, target := range targets[file] {
+			if target.Volume != "" {
+				s.Log.Info(fmt.Sprintf("copying %s to %s", file, target.Volume))
+				if err := docker.CopyFileToVolume(s.ctx, target.Volume, file, target.Dest); err != nil {
+					return err
+				}
+			}
+			if !seen[target.Service] {
+				seen[target.Service] = true
+				services = append(services, target.Service)
+			}
+		}
+	}
+	if len(services) == 0 {
+		return nil
+	}
+	sort.Strings(services)
+	names := make([]string, len(changed))
+	for i, file := range changed {
+		names[i] = file
+		if rel, err := filepath.Rel(s.Stack.StackDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+			names[i] = rel
+		}
+	}
+	s.Log.Info(fmt.Sprintf("%s changed - restarting %s", strings.Join(names, ", "), strings.Join(services, ", ")))
+	return s.runDockerComposeCommand(append([]string{"restart"}, services...)...)
+}
+
+// watchTargets returns the services to restart for each watched path. This covers every file of the
+// stack directory that is bind mounted into a service, and the connector and data exchange configs
+// that are copied into volumes at first start.
+func (s *StackManager) watchTargets(extraPaths map[string][]string) map[string][]watchTarget {
+	targets := bindMountTargets(s.buildDockerCompose(), s.Stack.StackDir)
+	configDir := filepath.Join(s.Stack.RuntimeDir, "config")
+	connector := s.blockchainProvider.GetConnectorName()
+	for i, member := range s.Stack.Members {
+		connectorConfig := filepath.Join(configDir, fmt.Sprintf("%s_%v.yaml", connector, i))
+		if _, err := os.Stat(connectorConfig); err == nil {
+			targets[connectorConfig] = append(targets[connectorConfig], watchTarget{
+				Service: fmt.Sprintf("%s_%s", connector, member.ID),
+				Volume:  fmt.Sprintf("%s_%s_config_%v", s.Stack.ResourcePrefix(), connector, i),
+				Dest:    "config.yaml",
+			})
+		}
+		if !s.Stack.DisableDataExchange {
+			dxConfig := filepath.Join(configDir, fmt.Sprintf("dataexchange_%s", member.ID), "config.json")
+			if _, err := os.Stat(dxConfig); err == nil {
+				targets[dxConfig] = append(targets[dxConfig], watchTarget{
+					Service: fmt.Sprintf("dataexchange_%s", member.ID),
+					Volume:  fmt.Sprintf("%s_dataexchange_%s", s.Stack.ResourcePrefix(), member.ID),
+					Dest:    "/config.json",
+				})
+			}
+		}
+	}
+	for p, services := range extraPaths {
+		for _, service := range services {
+			targets[p] = append(targets[p], watchTarget{Service: service})
+		}
+	}
+	return targets
+}
+
+// bindMountTargets maps the host path of each bind mount under dir to the services that mount it
+func bindMountTargets(compose *docker.DockerComposeConfig, dir string) map[string][]watchTarget {
+	targets := make(map[string][]watchTarget)
+	for name, service := range compose.Services {
+		for _, volume := range service.Volumes {
+			source := bindMountSource(volume)
+			if source == "" || !strings.HasPrefix(source, dir+string(filepath.Separator)) {
+				continue
+			}
+			targets[source] = append(targets[source], watchTarget{Service: name})
+		}
+	}
+	return targets
+}
+
+// bindMountSource returns the host path of a compose volume, or an empty string for a named volume
+func bindMountSource(volume string) string {
+	parts := strings.Split(volume, ":")
+	if len(parts) > 2 && len(parts[0]) == 1 {
+		// A Windows path with a drive letter
+		parts = append([]string{parts[0] + ":" + parts[1]}, parts[2:]...)
+	}
+	if len(parts) < 2 || !filepath.IsAbs(parts[0]) {
+		return ""
+	}
+	return filepath.Clean(parts[0])
+}
+
+// snapshotFiles records the state of every file under the watched paths. A directory is expanded to
+// the files it contains, which share the targets of the directory.
+func snapshotFiles(roots map[string][]watchTarget) (map[string][]watchTarget, map[string]fileState) {
+	targets := make(map[string][]watchTarget)
+	states := make(map[string]fileState)
+	for root, rootTargets := range roots {
+		_ = filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return nil
+			}
+			targets[p] = append(targets[p], rootTargets...)
+			states[p] = fileState{modTime: info.ModTime(), size: info.Size()}
+			return nil
+		})
+	}
+	return targets, states
+}
+
+// changedFiles returns the files that were added, modified or removed between two snapshots
+func changedFiles(prev, curr map[string]fileState) []string {
+	changed := []string{}
+	for p, state := range curr {
+		if old, ok := prev[p]; !ok || old != state {
+			changed = append(changed, p)
+		}
+	}
+	for p := range prev {
+		if _, ok := curr[p]; !ok {
+			changed = append(changed, p)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,73 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-cli/internal/docker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindMountTargets(t *testing.T) {
+	compose := &docker.DockerComposeConfig{
+		Services: map[string]*docker.Service{
+			"firefly_core_0": {Volumes: []string{"/stacks/dev/runtime/config/firefly_core_0.yml:/etc/firefly/firefly.core.yml:ro"}},
+			"ethconnect_0":   {Volumes: []string{"ethconnect_config_0:/ethconnect/config"}},
+			"portainer":      {Volumes: []string{"/var/run/docker.sock:/var/run/docker.sock"}},
+		},
+	}
+	targets := bindMountTargets(compose, "/stacks/dev")
+	assert.Equal(t, map[string][]watchTarget{
+		"/stacks/dev/runtime/config/firefly_core_0.yml": {{Service: "firefly_core_0"}},
+	}, targets)
+}
+
+func TestBindMountSource(t *testing.T) {
+	assert.Equal(t, "/a/b.yml", bindMountSource("/a/b.yml:/etc/b.yml:ro"))
+	assert.Equal(t, "", bindMountSource("named_volume:/data"))
+	assert.Equal(t, "", bindMountSource("/anonymous"))
+}
+
+func TestSnapshotAndChangedFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "config", "core.yml")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+	assert.NoError(t, ioutil.WriteFile(file, []byte("a: 1"), 0644))
+
+	roots := map[string][]watchTarget{filepath.Join(dir, "config"): {{Service: "firefly_core_0"}}}
+	targets, prev := snapshotFiles(roots)
+	assert.Equal(t, []watchTarget{{Service: "firefly_core_0"}}, targets[file])
+
+	_, curr := snapshotFiles(roots)
+	assert.Empty(t, changedFiles(prev, curr))
+
+	assert.NoError(t, ioutil.WriteFile(file, []byte("a: 12"), 0644))
+	assert.NoError(t, os.Chtimes(file, time.Now(), time.Now().Add(time.Second)))
+	_, curr = snapshotFiles(roots)
+	assert.Equal(t, []string{file}, changedFiles(prev, curr))
+
+	assert.NoError(t, os.Remove(file))
+	_, next := snapshotFiles(roots)
+	assert.Equal(t, []string{file}, changedFiles(curr, next))
+}
@@ -33,6 +33,15 @@ import (
 const tokenProviderName = "erc1155"
 const contractName = "ERC1155MixedFungible"
 
+// instanceName returns the name of the contract deployed for the connector at tokenIndex. The first
+// keeps the plain contract name used before a stack could have more than one erc1155 connector.
+func instanceName(tokenIndex int) string {
+	if tokenIndex == 0 {
+		return contractName
+	}
+	return fmt.Sprintf("%s_%d", contractName, tokenIndex)
+}
+
 type ERC1155Provider struct {
 	ctx                context.Context
 	stack              *types.Stack
@@ -52,7 +61,7 @@ func (p *ERC1155Provider) DeploySmartContracts(tokenIndex int) (*types.ContractD
 	var containerName string
 	for _, member := range p.stack.Members {
 		if !member.External {
-			containerName = fmt.Sprintf("%s_tokens_%s_%d", p.stack.Name, member.ID, tokenIndex)
+			containerName = fmt.Sprintf("%s_tokens_%s_%d", p.stack.ResourcePrefix(), member.ID, tokenIndex)
 			break
 		}
 	}
@@ -65,7 +74,7 @@ func (p *ERC1155Provider) DeploySmartContracts(tokenIndex int) (*types.ContractD
 		return nil, err
 	}
 	constructorArgs := []string{"firefly://"}
-	return p.blockchainProvider.DeployContract(filepath.Join(p.stack.RuntimeDir, "contracts", "ERC1155MixedFungible.json"), contractName, contractName, p.stack.Members[0], constructorArgs)
+	return p.blockchainProvider.DeployContract(filepath.Join(p.stack.RuntimeDir, "contracts", "ERC1155MixedFungible.json"), contractName, instanceName(tokenIndex), p.stack.Members[0], constructorArgs)
 }
 
 func (p *ERC1155Provider) FirstTimeSetup(tokenIdx int) error {
@@ -87,7 +96,7 @@ func (p *ERC1155Provider) GetDockerServiceDefinitions(tokenIdx int) []*docker.Se
 
 		var contractAddress types.HexAddress
 		for _, contract := range p.stack.State.DeployedContracts {
-			if contract.Name == contractName {
+			if contract.Name == instanceName(tokenIdx) {
 				switch loc := contract.Location.(type) {
 				case map[string]string:
 					contractAddress = types.HexAddress(loc["address"])
@@ -105,11 +114,11 @@ func (p *ERC1155Provider) GetDockerServiceDefinitions(tokenIdx int) []*docker.Se
 			ServiceName: connectorName,
 			Service: &docker.Service{
 				Image:         p.stack.VersionManifest.TokensERC1155.GetDockerImageString(),
-				ContainerName: fmt.Sprintf("%s_tokens_%v_%v", p.stack.Name, i, tokenIdx),
+				ContainerName: fmt.Sprintf("%s_tokens_%v_%v", p.stack.ResourcePrefix(), i, tokenIdx),
 				Ports:         []string{fmt.Sprintf("%d:3000", member.ExposedTokensPorts[tokenIdx])},
 				Environment:   env,
 				DependsOn: map[string]map[string]string{
-					fmt.Sprintf("%s_%s", p.blockchainProvider.GetConnectorName(), member.ID): {"condition": "service_started"},
+					fmt.Sprintf("%s_%s", p.blockchainProvider.GetConnectorName(), member.ID): {"condition": "service_healthy"},
 				},
 				HealthCheck: &docker.HealthCheck{
 					Test: []string{"CMD", "curl", "http://localhost:3000/api"},
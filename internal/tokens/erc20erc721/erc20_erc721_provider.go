@@ -51,7 +51,7 @@ func (p *ERC20ERC721Provider) DeploySmartContracts(tokenIndex int) (*types.Contr
 	var containerName string
 	for _, member := range p.stack.Members {
 		if !member.External {
-			containerName = fmt.Sprintf("%s_tokens_%s_%d", p.stack.Name, member.ID, tokenIndex)
+			containerName = fmt.Sprintf("%s_tokens_%s_%d", p.stack.ResourcePrefix(), member.ID, tokenIndex)
 			break
 		}
 	}
@@ -108,11 +108,11 @@ func (p *ERC20ERC721Provider) GetDockerServiceDefinitions(tokenIdx int) []*docke
 			ServiceName: connectorName,
 			Service: &docker.Service{
 				Image:         p.stack.VersionManifest.TokensERC20ERC721.GetDockerImageString(),
-				ContainerName: fmt.Sprintf("%s_tokens_%v_%v", p.stack.Name, i, tokenIdx),
+				ContainerName: fmt.Sprintf("%s_tokens_%v_%v", p.stack.ResourcePrefix(), i, tokenIdx),
 				Ports:         []string{fmt.Sprintf("%d:3000", member.ExposedTokensPorts[tokenIdx])},
 				Environment:   env,
 				DependsOn: map[string]map[string]string{
-					fmt.Sprintf("%s_%s", p.blockchainProvider.GetConnectorName(), member.ID): {"condition": "service_started"},
+					fmt.Sprintf("%s_%s", p.blockchainProvider.GetConnectorName(), member.ID): {"condition": "service_healthy"},
 				},
 				HealthCheck: &docker.HealthCheck{
 					Test: []string{"CMD", "curl", "http://localhost:3000/api"},
@@ -0,0 +1,30 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+type EventStream struct {
+	ID        string                 `json:"id"`
+	Name      string                 `json:"name,omitempty"`
+	Listeners []*EventStreamListener `json:"listeners"`
+}
+
+type EventStreamListener struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name,omitempty"`
+	FromBlock  string      `json:"fromBlock,omitempty"`
+	Checkpoint interface{} `json:"checkpoint,omitempty"`
+}
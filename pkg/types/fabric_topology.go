@@ -0,0 +1,34 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// FabricTopology describes the orderers, peer organizations and channels of a Fabric stack.
+// The first channel is the one used by FireFly.
+type FabricTopology struct {
+	Orderers int                      `yaml:"orderers,omitempty" json:"orderers,omitempty"`
+	Orgs     []*FabricTopologyOrg     `yaml:"orgs,omitempty" json:"orgs,omitempty"`
+	Channels []*FabricTopologyChannel `yaml:"channels,omitempty" json:"channels,omitempty"`
+}
+
+type FabricTopologyOrg struct {
+	Name string `yaml:"name" json:"name"`
+}
+
+type FabricTopologyChannel struct {
+	Name string   `yaml:"name" json:"name"`
+	Orgs []string `yaml:"orgs" json:"orgs"`
+}
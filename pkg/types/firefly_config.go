@@ -21,9 +21,19 @@ type LogConfig struct {
 }
 
 type HttpServerConfig struct {
-	Port      int    `yaml:"port,omitempty"`
-	Address   string `yaml:"address,omitempty"`
-	PublicURL string `yaml:"publicURL,omitempty"`
+	Port      int             `yaml:"port,omitempty"`
+	Address   string          `yaml:"address,omitempty"`
+	PublicURL string          `yaml:"publicURL,omitempty"`
+	Auth      *HttpAuthConfig `yaml:"auth,omitempty"`
+}
+
+type HttpAuthConfig struct {
+	Type  string           `yaml:"type,omitempty"`
+	Basic *BasicAuthConfig `yaml:"basic,omitempty"`
+}
+
+type BasicAuthConfig struct {
+	PasswordFile string `yaml:"passwordfile,omitempty"`
 }
 
 type AdminServerConfig struct {
@@ -25,10 +25,24 @@ type PullOptions struct {
 	Retries int
 }
 
-type StartOptions struct {
+type RefreshOptions struct {
+	Force      bool
 	NoRollback bool
 }
 
+type StartOptions struct {
+	NoRollback             bool
+	DryRun                 bool
+	Profiles               []string
+	SkipCompatibilityCheck bool
+	CLIVersion             string
+}
+
+type ReportOptions struct {
+	LogLines   int
+	CLIVersion string
+}
+
 type InitOptions struct {
 	FireFlyBasePort          int
 	ServicesBasePort         int
@@ -56,6 +70,31 @@ type InitOptions struct {
 	ReleaseChannel           string
 	MultipartyEnabled        bool
 	IPFSMode                 string
+	DryRun                   bool
+	LatencyProfile           string
+	Minimal                  bool
+	DisableIPFS              bool
+	DisableDataExchange      bool
+	NamePrefix               string
+	Labels                   []string
+	BindAddress              string
+	DNS                      []string
+	ExtraHosts               []string
+	APIAuth                  bool
+	MemberAPITokens          bool
+	GenesisPath              string
+	ChainDataPath            string
+	PrivateTransactions      bool
+	FabricTopologyPath       string
+	MessageQueue             string
+	MessageQueuePort         int
+	Timezone                 string
+	ClockSkew                []string
+	RPCProxyEnabled          bool
+	RPCProxy                 RPCProxyConfig
+	KeepFailed               bool
+	Deterministic            bool
+	Seed                     int64
 }
 
 const IPFSMode = "ipfs_mode"
@@ -65,6 +104,14 @@ var (
 	IPFSModePublic  = fftypes.FFEnumValue(IPFSMode, "public")
 )
 
+const MessageQueue = "message_queue"
+
+var (
+	MessageQueueNone  = fftypes.FFEnumValue(MessageQueue, "none")
+	MessageQueueNATS  = fftypes.FFEnumValue(MessageQueue, "nats")
+	MessageQueueRedis = fftypes.FFEnumValue(MessageQueue, "redis")
+)
+
 const BlockchainProvider = "blockchain_provider"
 
 var (
@@ -86,7 +133,9 @@ const BlockchainNodeProvider = "blockchain_node_provider"
 var (
 	BlockchainNodeProviderGeth      = fftypes.FFEnumValue(BlockchainNodeProvider, "geth")
 	BlockchainNodeProviderBesu      = fftypes.FFEnumValue(BlockchainNodeProvider, "besu")
+	BlockchainNodeProviderQuorum    = fftypes.FFEnumValue(BlockchainNodeProvider, "quorum")
 	BlockchainNodeProviderRemoteRPC = fftypes.FFEnumValue(BlockchainNodeProvider, "remote-rpc")
+	BlockchainNodeProviderAnvil     = fftypes.FFEnumValue(BlockchainNodeProvider, "anvil")
 )
 
 const DatabaseSelection = "database_selection"
@@ -111,6 +160,7 @@ var (
 	ReleaseChannelAlpha  = fftypes.FFEnumValue(ReleaseChannelSelection, "alpha")
 	ReleaseChannelBeta   = fftypes.FFEnumValue(ReleaseChannelSelection, "beta")
 	ReleaseChannelRC     = fftypes.FFEnumValue(ReleaseChannelSelection, "rc")
+	ReleaseChannelHead   = fftypes.FFEnumValue(ReleaseChannelSelection, "head")
 )
 
 func FFEnumArray(ctx context.Context, a []string) ([]fftypes.FFEnum, error) {
@@ -35,4 +35,6 @@ type Organization struct {
 	OrgName                    string       `json:"orgName,omitempty"`
 	NodeName                   string       `json:"nodeName,omitempty"`
 	Namespaces                 []*Namespace `json:"namespaces"`
+	TesseraPublicKey           string       `json:"tesseraPublicKey,omitempty"`
+	APIToken                   string       `json:"apiToken,omitempty"`
 }
@@ -0,0 +1,29 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// RPCProxyConfig configures the local proxy that the signer of a remote-rpc stack sends its
+// requests through. Durations are in the format of Go's time.ParseDuration.
+type RPCProxyConfig struct {
+	Cache             bool    `json:"cache"`
+	RequestsPerSecond int     `json:"requestsPerSecond,omitempty"`
+	Budget            int     `json:"budget,omitempty"`
+	BudgetWindow      string  `json:"budgetWindow,omitempty"`
+	Latency           string  `json:"latency,omitempty"`
+	TimeoutPercent    float64 `json:"timeoutPercent,omitempty"`
+	Timeout           string  `json:"timeout,omitempty"`
+}
@@ -17,6 +17,7 @@
 package types
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -25,30 +26,55 @@ import (
 )
 
 type Stack struct {
-	Name                   string           `json:"name,omitempty"`
-	Members                []*Organization  `json:"members,omitempty"`
-	SwarmKey               string           `json:"swarmKey,omitempty"`
-	ExposedBlockchainPort  int              `json:"exposedBlockchainPort,omitempty"`
-	Database               fftypes.FFEnum   `json:"database"`
-	BlockchainProvider     fftypes.FFEnum   `json:"blockchainProvider"`
-	BlockchainConnector    fftypes.FFEnum   `json:"blockchainConnector"`
-	BlockchainNodeProvider fftypes.FFEnum   `json:"blockchainNodeProvider"`
-	TokenProviders         []fftypes.FFEnum `json:"tokenProviders"`
-	VersionManifest        *VersionManifest `json:"versionManifest,omitempty"`
-	PrometheusEnabled      bool             `json:"prometheusEnabled,omitempty"`
-	SandboxEnabled         bool             `json:"sandboxEnabled,omitempty"`
-	MultipartyEnabled      bool             `json:"multiparty"`
-	ExposedPrometheusPort  int              `json:"exposedPrometheusPort,omitempty"`
-	ContractAddress        string           `json:"contractAddress,omitempty"`
-	ChainIDPtr             *int64           `json:"chainID,omitempty"`
-	RemoteNodeURL          string           `json:"remoteNodeURL,omitempty"`
-	DisableTokenFactories  bool             `json:"disableTokenFactories,omitempty"`
-	RequestTimeout         int              `json:"requestTimeout,omitempty"`
-	IPFSMode               fftypes.FFEnum   `json:"ipfsMode"`
-	InitDir                string           `json:"-"`
-	RuntimeDir             string           `json:"-"`
-	StackDir               string           `json:"-"`
-	State                  *StackState      `json:"-"`
+	SchemaVersion           int               `json:"schemaVersion,omitempty"`
+	Name                    string            `json:"name,omitempty"`
+	Members                 []*Organization   `json:"members,omitempty"`
+	SwarmKey                string            `json:"swarmKey,omitempty"`
+	ExposedBlockchainPort   int               `json:"exposedBlockchainPort,omitempty"`
+	Database                fftypes.FFEnum    `json:"database"`
+	BlockchainProvider      fftypes.FFEnum    `json:"blockchainProvider"`
+	BlockchainConnector     fftypes.FFEnum    `json:"blockchainConnector"`
+	BlockchainNodeProvider  fftypes.FFEnum    `json:"blockchainNodeProvider"`
+	TokenProviders          []fftypes.FFEnum  `json:"tokenProviders"`
+	TokenProviderNames      []string          `json:"tokenProviderNames,omitempty"`
+	VersionManifest         *VersionManifest  `json:"versionManifest,omitempty"`
+	ReleaseChannel          fftypes.FFEnum    `json:"releaseChannel,omitempty"`
+	PrometheusEnabled       bool              `json:"prometheusEnabled,omitempty"`
+	SandboxEnabled          bool              `json:"sandboxEnabled,omitempty"`
+	MultipartyEnabled       bool              `json:"multiparty"`
+	ExposedPrometheusPort   int               `json:"exposedPrometheusPort,omitempty"`
+	ContractAddress         string            `json:"contractAddress,omitempty"`
+	ChainIDPtr              *int64            `json:"chainID,omitempty"`
+	RemoteNodeURL           string            `json:"remoteNodeURL,omitempty"`
+	DisableTokenFactories   bool              `json:"disableTokenFactories,omitempty"`
+	RequestTimeout          int               `json:"requestTimeout,omitempty"`
+	IPFSMode                fftypes.FFEnum    `json:"ipfsMode"`
+	LatencyProfile          string            `json:"latencyProfile,omitempty"`
+	LocalImages             map[string]string `json:"localImages,omitempty"`
+	Protected               bool              `json:"protected,omitempty"`
+	BlockPeriod             int               `json:"blockPeriod,omitempty"`
+	DisableIPFS             bool              `json:"disableIPFS,omitempty"`
+	DisableDataExchange     bool              `json:"disableDataExchange,omitempty"`
+	NamePrefix              string            `json:"namePrefix,omitempty"`
+	Labels                  map[string]string `json:"labels,omitempty"`
+	BindAddress             string            `json:"bindAddress,omitempty"`
+	DNS                     []string          `json:"dns,omitempty"`
+	ExtraHosts              []string          `json:"extraHosts,omitempty"`
+	APIAuthToken            string            `json:"apiAuthToken,omitempty"`
+	PrivateTransactions     bool              `json:"privateTransactions,omitempty"`
+	FabricTopology          *FabricTopology   `json:"fabricTopology,omitempty"`
+	MessageQueue            fftypes.FFEnum    `json:"messageQueue,omitempty"`
+	ExposedMessageQueuePort int               `json:"exposedMessageQueuePort,omitempty"`
+	Tenants                 []*Tenant         `json:"tenants,omitempty"`
+	RestartPolicy           string            `json:"restartPolicy,omitempty"`
+	Timezone                string            `json:"timezone,omitempty"`
+	ClockSkew               map[string]int    `json:"clockSkew,omitempty"`
+	RPCProxy                *RPCProxyConfig   `json:"rpcProxy,omitempty"`
+	Seed                    *int64            `json:"seed,omitempty"`
+	InitDir                 string            `json:"-"`
+	RuntimeDir              string            `json:"-"`
+	StackDir                string            `json:"-"`
+	State                   *StackState       `json:"-"`
 }
 
 func (s *Stack) ChainID() int64 {
@@ -58,6 +84,41 @@ func (s *Stack) ChainID() int64 {
 	return *s.ChainIDPtr
 }
 
+// ResourcePrefix returns the prefix used for the names of the containers, volumes and network
+// of the stack. This is the stack name, unless a custom prefix was set when the stack was created.
+func (s *Stack) ResourcePrefix() string {
+	if s.NamePrefix != "" {
+		return s.NamePrefix
+	}
+	return s.Name
+}
+
+// TokenProviderName returns the FireFly plugin name of the token connector at index i. Stacks
+// created before connectors could be named use the name of the connector type.
+func (s *Stack) TokenProviderName(i int) string {
+	if i < len(s.TokenProviderNames) && s.TokenProviderNames[i] != "" {
+		return s.TokenProviderNames[i]
+	}
+	return s.TokenProviders[i].String()
+}
+
+// APIToken returns the password that the FireFly API of a member requires, which is either the
+// member's own token or the one shared by all members. It is empty if the API is open.
+func (s *Stack) APIToken(member *Organization) string {
+	if member.APIToken != "" {
+		return member.APIToken
+	}
+	return s.APIAuthToken
+}
+
+// APIPasswordFileName returns the name of the password file for the FireFly API of a member
+func (s *Stack) APIPasswordFileName(member *Organization) string {
+	if member.APIToken != "" {
+		return fmt.Sprintf("api_users_%s", member.ID)
+	}
+	return "api_users"
+}
+
 func (s *Stack) HasRunBefore() (bool, error) {
 	stackDir := filepath.Join(constants.StacksDir, s.Name)
 	isOldFileStructure, err := s.IsOldFileStructure()
@@ -21,6 +21,7 @@ type DeployedContract struct {
 }
 
 type StackState struct {
+	SchemaVersion     int                 `json:"schemaVersion,omitempty"`
 	DeployedContracts []*DeployedContract `json:"deployedContracts"`
 	Accounts          []interface{}       `json:"accounts"`
 }
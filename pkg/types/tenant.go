@@ -0,0 +1,26 @@
+// Copyright © 2022 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// Tenant is a namespace created by "ff demo multitenant", with its own API credentials
+// and a signing key on each member
+type Tenant struct {
+	Name     string            `json:"name"`
+	Username string            `json:"username"`
+	Token    string            `json:"token"`
+	Keys     map[string]string `json:"keys"`
+}